@@ -1,10 +0,0 @@
--- Migration 000: Create migrations tracking table
--- This table tracks which migrations have been executed
-
-CREATE TABLE IF NOT EXISTS schema_migrations (
-    version VARCHAR(255) PRIMARY KEY,
-    applied_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
-);
-
--- Create index for faster lookups
-CREATE INDEX IF NOT EXISTS idx_schema_migrations_version ON schema_migrations(version);
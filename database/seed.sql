@@ -1,5 +1,7 @@
 -- Seed data for BizGenie Product Website
 -- This file contains sample data for development and testing
+-- Superseded by "main seed -profile demo" (main-api/internal/services/seed_profiles.go),
+-- which loads the same content through the API services; see docs/seed-data.md
 
 -- Insert admin user (password: admin123 - should be hashed in production)
 -- Default password hash for 'admin123' using bcrypt
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"bizgenie-api/internal/config"
+	"bizgenie-api/internal/services"
+)
+
+const backupUsage = `usage: main backup [-o FILE] [-dir DIR] [-keep N]
+
+Writes the content tables to a compressed archive tagged with the schema version.
+User password hashes are never archived. Archives are only readable by their owner.
+Without -o the archive is written to DIR (default BACKUP_DIR) with a timestamped name
+and all but the newest N archives there are removed (default BACKUP_RETENTION, 0 keeps all).`
+
+const restoreUsage = `usage: main restore [-on-conflict fail|skip|overwrite] [-truncate] FILE
+
+Restores an archive written by "main backup" in a single transaction, after migrations.
+  -on-conflict  what to do with rows whose id or unique key already exists (default fail)
+  -truncate     empty the content tables, users included, first
+
+Archives hold no password hashes. Users that already existed keep their password, with
+or without -truncate; users that did not exist get an unusable password that must be reset.`
+
+// runBackupCommand handles the "backup" subcommand of the server binary
+func runBackupCommand(db *sql.DB, cfg *config.Config, args []string) error {
+	flags := flag.NewFlagSet("backup", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	out := flags.String("o", "", "")
+	dir := flags.String("dir", cfg.BackupDir, "")
+	keep := flags.Int("keep", cfg.BackupRetention, "")
+	if err := flags.Parse(args); err != nil || flags.NArg() > 0 {
+		return fmt.Errorf("%s", backupUsage)
+	}
+
+	backups := services.NewBackupService(db)
+	ctx := context.Background()
+
+	var path string
+	var summary *services.BackupSummary
+	var err error
+	if *out != "" {
+		path = *out
+		summary, err = backups.BackupToFile(ctx, path)
+	} else {
+		path, summary, err = backups.BackupToDir(ctx, *dir, *keep)
+	}
+	if err != nil {
+		return err
+	}
+
+	printBackupSummary(summary, false)
+	fmt.Printf("Backup written to %s (schema %s)\n", path, summary.SchemaVersion)
+	return nil
+}
+
+// runRestoreCommand handles the "restore" subcommand of the server binary
+func runRestoreCommand(db *sql.DB, args []string) error {
+	flags := flag.NewFlagSet("restore", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	onConflict := flags.String("on-conflict", services.ConflictFail, "")
+	truncate := flags.Bool("truncate", false, "")
+	if err := flags.Parse(args); err != nil || flags.NArg() != 1 {
+		return fmt.Errorf("%s", restoreUsage)
+	}
+
+	f, err := os.Open(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	opts := services.RestoreOptions{OnConflict: *onConflict, Truncate: *truncate}
+	summary, err := services.NewBackupService(db).Restore(context.Background(), f, opts)
+	if err != nil {
+		return err
+	}
+
+	printBackupSummary(summary, true)
+	fmt.Printf("Restored backup of %s (schema %s)\n", summary.CreatedAt.Format("2006-01-02 15:04:05"), summary.SchemaVersion)
+	fmt.Println("Rebuild the assistant index with POST /api/admin/assistant/reindex to refresh content chunks")
+	return nil
+}
+
+func printBackupSummary(summary *services.BackupSummary, restored bool) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	if restored {
+		fmt.Fprintln(w, "TABLE\tRESTORED\tSKIPPED")
+	} else {
+		fmt.Fprintln(w, "TABLE\tROWS")
+	}
+	for _, table := range summary.Tables {
+		if restored {
+			fmt.Fprintf(w, "%s\t%d\t%d\n", table.Table, table.Rows, table.Skipped)
+		} else {
+			fmt.Fprintf(w, "%s\t%d\n", table.Table, table.Rows)
+		}
+	}
+	w.Flush()
+}
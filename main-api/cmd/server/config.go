@@ -0,0 +1,51 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"bizgenie-api/internal/config"
+
+	"gopkg.in/yaml.v3"
+)
+
+const configUsage = `usage: main config print [-redacted]
+
+Prints the resolved configuration as YAML, in the format CONFIG_FILE accepts, with the
+source of each value (env, secret file, config file or default) as a comment.
+  -redacted  hide secrets and passwords in URLs, for sharing the output`
+
+// runConfigCommand handles the "config" subcommand of the server binary. It runs before
+// the database connection so it works while the database is unreachable.
+func runConfigCommand(cfg *config.Config, args []string) error {
+	if len(args) == 0 || args[0] != "print" {
+		return fmt.Errorf("%s", configUsage)
+	}
+	flags := flag.NewFlagSet("config print", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	redacted := flags.Bool("redacted", false, "")
+	if err := flags.Parse(args[1:]); err != nil || flags.NArg() > 0 {
+		return fmt.Errorf("%s", configUsage)
+	}
+
+	doc := &yaml.Node{Kind: yaml.MappingNode}
+	for _, setting := range cfg.Settings() {
+		value := setting.Value
+		if *redacted {
+			value = setting.Redacted
+		}
+		doc.Content = append(doc.Content,
+			&yaml.Node{Kind: yaml.ScalarNode, Value: strings.ToLower(setting.Key)},
+			&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value, LineComment: setting.Source},
+		)
+	}
+	encoder := yaml.NewEncoder(os.Stdout)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(doc); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
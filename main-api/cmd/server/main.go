@@ -1,14 +1,24 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
+	"bizgenie-api/internal/clientip"
 	"bizgenie-api/internal/config"
 	"bizgenie-api/internal/database"
 	"bizgenie-api/internal/handlers"
 	"bizgenie-api/internal/logger"
+	"bizgenie-api/internal/metrics"
 	"bizgenie-api/internal/middleware"
 	"bizgenie-api/internal/services"
+	"bizgenie-api/internal/tracing"
 
 	"github.com/gin-gonic/gin"
 )
@@ -20,9 +30,21 @@ func main() {
 		logger.FatalWithErr("Failed to load config", err)
 	}
 
-	// Initialize logger with configured level
+	// "main config print" shows the resolved configuration and exits
+	if len(os.Args) > 1 && os.Args[1] == "config" {
+		if err := runConfigCommand(cfg, os.Args[2:]); err != nil {
+			logger.FatalWithErr("Config command failed", err)
+		}
+		return
+	}
+
+	// Initialize logger with configured format and levels
+	logger.SetFormat(cfg.LogFormat)
 	logger.SetLevelFromString(cfg.LogLevel)
-	logger.Info("Logger initialized with level: %s", cfg.LogLevel)
+	if err := logger.SetPackageLevelsFromString(cfg.LogPackageLevels); err != nil {
+		logger.Warn("Ignoring LOG_PACKAGE_LEVELS: %v", err)
+	}
+	logger.Info("Logger initialized with level: %s, format: %s", cfg.LogLevel, cfg.LogFormat)
 
 	// Initialize database
 	db, err := database.Connect(cfg.DatabaseURL)
@@ -32,23 +54,99 @@ func main() {
 	defer db.Close()
 	logger.Info("Database connected successfully")
 
+	// "main migrate ..." manages the schema and exits without starting the server
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := runMigrateCommand(db, cfg, os.Args[2:]); err != nil {
+			db.Close()
+			logger.FatalWithErr("Migrate command failed", err)
+		}
+		return
+	}
+
 	// Run migrations
-	if err := database.RunMigrations(db); err != nil {
+	if err := database.RunMigrations(db, migratorOptions(cfg)); err != nil {
 		logger.FatalWithErr("Failed to run migrations", err)
 	}
 	logger.Info("Database migrations completed successfully")
 
+	// "main backup", "main restore" and "main seed" run against the migrated schema and exit
+	if len(os.Args) > 1 && (os.Args[1] == "backup" || os.Args[1] == "restore" || os.Args[1] == "seed") {
+		switch os.Args[1] {
+		case "backup":
+			err = runBackupCommand(db, cfg, os.Args[2:])
+		case "restore":
+			err = runRestoreCommand(db, os.Args[2:])
+		default:
+			err = runSeedCommand(db, cfg, os.Args[2:])
+		}
+		if err != nil {
+			db.Close()
+			logger.FatalWithErr("The "+os.Args[1]+" command failed", err)
+		}
+		return
+	}
+
 	// Create default admin user if it doesn't exist
-	// Set forceUpdate to true to reset password on each startup (useful for development)
-	forceUpdate := os.Getenv("FORCE_UPDATE_ADMIN") == "true"
+	// Set FORCE_UPDATE_ADMIN to reset password to ADMIN_PASSWORD on each startup (development only)
 	authService := services.NewAuthService(cfg.JWTSecret)
-	if err := database.CreateDefaultAdmin(db, authService.HashPassword, forceUpdate); err != nil {
+	if err := database.CreateDefaultAdmin(db, authService.HashPassword, cfg.AdminPassword, cfg.ForceUpdateAdmin); err != nil {
 		logger.Warn("Failed to create default admin user: %v", err)
 		// Don't fail startup if admin already exists or other non-critical error
 	} else {
 		logger.Info("Default admin user checked/created successfully")
 	}
 
+	// Background work is tracked so shutdown can wait for it; backgroundCtx stops the scheduler
+	tasks := &services.BackgroundTasks{}
+	backgroundCtx, stopBackground := context.WithCancel(context.Background())
+	defer stopBackground()
+
+	// Scheduled backups; replicas sharing BACKUP_DIR take turns
+	if cfg.BackupIntervalHours > 0 {
+		interval := time.Duration(cfg.BackupIntervalHours) * time.Hour
+		tasks.Go(func() {
+			services.NewBackupService(db).RunSchedule(backgroundCtx, cfg.BackupDir, interval, cfg.BackupRetention)
+		})
+		logger.Info("Scheduled backups every %s into %s, keeping %d", interval, cfg.BackupDir, cfg.BackupRetention)
+	}
+
+	// Rate limiting: buckets in Postgres are shared by every replica
+	var rateLimitStore services.RateLimitStore
+	switch cfg.RateLimit.Store {
+	case "postgres":
+		rateLimitStore = services.NewRateLimitService(db)
+	case "memory":
+		rateLimitStore = services.NewMemoryRateLimitStore()
+	}
+	rateLimit := gin.HandlerFunc(func(c *gin.Context) { c.Next() })
+	if rateLimitStore != nil && len(cfg.RateLimit.Routes) > 0 {
+		rateLimit = middleware.RateLimit(rateLimitStore, cfg.RateLimit.Routes)
+		// A bucket idle for its whole period is full again and can go
+		var idle time.Duration
+		for _, rule := range cfg.RateLimit.Routes {
+			idle = max(idle, rule.Period)
+		}
+		tasks.Go(func() {
+			services.RunRateLimitCleanup(backgroundCtx, rateLimitStore, 10*time.Minute, idle)
+		})
+		logger.Info("Rate limiting %d routes with the %s store", len(cfg.RateLimit.Routes), cfg.RateLimit.Store)
+	}
+
+	// Tracing: spans go to the OTLP collector when one is configured
+	shutdownTracing, err := tracing.Setup(context.Background(), tracing.Options{
+		ServiceName: cfg.ServiceName,
+		Environment: cfg.Environment,
+		Endpoint:    cfg.OTLPEndpoint,
+		SampleRatio: cfg.TraceSampleRatio,
+	})
+	if err != nil {
+		db.Close()
+		logger.FatalWithErr("Failed to set up tracing", err)
+	}
+	if cfg.OTLPEndpoint != "" {
+		logger.Info("Exporting traces to %s, sampling %.2f of new traces", cfg.OTLPEndpoint, cfg.TraceSampleRatio)
+	}
+
 	// Set Gin mode
 	if cfg.Environment == "production" {
 		gin.SetMode(gin.ReleaseMode)
@@ -57,28 +155,61 @@ func main() {
 		logger.Info("Running in development mode")
 	}
 
+	// Client addresses come from forwarding headers only when sent by a trusted proxy
+	clientIPs, err := clientip.NewResolver(cfg.TrustedProxies, cfg.ClientIPHeaders)
+	if err != nil {
+		db.Close()
+		logger.FatalWithErr("Invalid trusted proxy configuration", err)
+	}
+
 	// Initialize router without default logger (we use custom logger)
 	router := gin.New()
+	// c.ClientIP() sees the direct peer only; the resolved address is in the request context
+	if err := router.SetTrustedProxies(nil); err != nil {
+		db.Close()
+		logger.FatalWithErr("Failed to configure trusted proxies", err)
+	}
 	// Disable Gin's default logger to avoid duplicate logs
 	router.Use(gin.Recovery())
 
 	// Apply middleware (order matters)
-	router.Use(middleware.RequestLogger()) // Log requests first
-	router.Use(middleware.CORS())
-	router.Use(middleware.ErrorHandler()) // Handle errors last
+	router.Use(middleware.RequestID())         // Assign the request ID everything else reports
+	router.Use(middleware.ClientIP(clientIPs)) // Resolve the client behind the trusted proxies
+	router.Use(middleware.Tracing())           // Start the request span before anything else runs
+	router.Use(middleware.RequestLogger())     // Then log requests
+	router.Use(middleware.Metrics())
+	router.Use(middleware.SecurityHeaders(cfg.Security))
+	router.Use(middleware.CORS(
+		middleware.CORSRoute{PathPrefix: "/api", Policy: cfg.CORS},
+		middleware.CORSRoute{PathPrefix: "/api/admin", Policy: cfg.AdminCORS},
+	))
+	router.Use(middleware.ErrorHandler())            // Handle errors of the handlers and limits below
+	router.Use(middleware.RequestLimits(cfg.Limits)) // Body size, JSON depth and timeout per route
 
 	// Initialize handlers
-	h := handlers.New(db, cfg)
+	h, err := handlers.New(db, cfg, tasks)
+	if err != nil {
+		logger.FatalWithErr("Failed to set up the assistant", err)
+	}
+	metrics.RegisterDBStats(db)
 
 	// Public routes
 	api := router.Group("/api")
+	api.Use(rateLimit)
 	{
 		// Products
 		api.GET("/products", h.GetProducts)
+		api.GET("/products/facets", h.GetProductFacets)
 		api.GET("/products/:id", h.GetProductByID)
+		api.GET("/products/:id/related", h.GetRelatedProducts)
 		api.GET("/products/slug/:slug", h.GetProductBySlug)
 		api.GET("/products/search", h.SearchProducts)
 
+		// Site-wide search
+		api.GET("/search", h.Search)
+		api.GET("/search/passages", h.SearchPassages)
+		api.POST("/search/click", h.TrackSearchClick)
+
 		// Categories
 		api.GET("/categories", h.GetCategories)
 		api.GET("/categories/:id", h.GetCategoryByID)
@@ -87,6 +218,7 @@ func main() {
 		// Blog
 		api.GET("/blog", h.GetBlogPosts)
 		api.GET("/blog/:id", h.GetBlogPostByID)
+		api.GET("/blog/:id/related", h.GetRelatedBlogPosts)
 		api.GET("/blog/slug/:slug", h.GetBlogPostBySlug)
 
 		// Blog Categories
@@ -104,6 +236,12 @@ func main() {
 		// Social Media Links
 		api.GET("/social-media-links", h.GetSocialMediaLinks)
 
+		// FAQs
+		api.GET("/faqs", h.GetFAQs)
+
+		// Assistant
+		api.POST("/assistant/ask", h.AskAssistant)
+
 		// Auth
 		api.POST("/auth/login", h.Login)
 		api.POST("/auth/refresh", h.RefreshToken)
@@ -111,7 +249,7 @@ func main() {
 
 	// Admin routes (require JWT)
 	admin := router.Group("/api/admin")
-	admin.Use(middleware.JWTAuth(cfg.JWTSecret))
+	admin.Use(middleware.JWTAuth(cfg.JWTSecret), rateLimit) // Admins are limited per user
 	{
 		// Products admin
 		admin.GET("/products", h.GetAdminProducts)
@@ -160,21 +298,132 @@ func main() {
 		admin.POST("/social-media-links", h.CreateSocialMediaLink)
 		admin.PUT("/social-media-links/:id", h.UpdateSocialMediaLink)
 		admin.DELETE("/social-media-links/:id", h.DeleteSocialMediaLink)
+
+		// Search analytics admin
+		admin.GET("/search-analytics", h.GetSearchAnalytics)
+
+		// Vector index admin
+		admin.GET("/vector-index", h.GetVectorIndexStatus)
+		admin.POST("/vector-index/rebuild", h.RebuildVectorIndex)
+		admin.GET("/vector-index/recall", h.GetVectorIndexRecall)
+
+		// FAQs admin
+		admin.GET("/faqs", h.GetAdminFAQs)
+		admin.GET("/faqs/:id", h.GetFAQByID)
+		admin.POST("/faqs", h.CreateFAQ)
+		admin.PUT("/faqs/:id", h.UpdateFAQ)
+		admin.DELETE("/faqs/:id", h.DeleteFAQ)
+
+		// Assistant admin
+		admin.GET("/assistant/conversations", h.GetAssistantConversations)
+		admin.GET("/assistant/conversations/:id", h.GetAssistantConversationByID)
+		admin.POST("/assistant/reindex", h.ReindexAssistantContent)
+
+		// Content bundles
+		admin.GET("/export", h.ExportContent)
+		admin.POST("/import", h.ImportContent)
+
+		// Runtime log levels
+		admin.GET("/log-levels", h.GetLogLevels)
+		admin.PUT("/log-levels", h.UpdateLogLevels)
 	}
 
-	// Health check
-	router.GET("/health", func(c *gin.Context) {
-		c.JSON(200, gin.H{"status": "ok"})
-	})
+	// Health checks: liveness for restarts, readiness for routing; /health is kept for
+	// existing checks and behaves like readiness
+	router.GET("/health/live", h.Live)
+	router.GET("/health/ready", h.Ready)
+	router.GET("/health", h.Ready)
+
+	// Per-route limits name routes as registered; a typo would silently apply the defaults
+	registered := map[string]bool{}
+	for _, route := range router.Routes() {
+		registered[route.Method+" "+route.Path] = true
+	}
+	for route := range cfg.Limits.RouteMaxBodyBytes {
+		if !registered[route] {
+			logger.Warn("ROUTE_BODY_LIMITS names unknown route %s", route)
+		}
+	}
+	for route := range cfg.Limits.RouteTimeouts {
+		if !registered[route] {
+			logger.Warn("ROUTE_TIMEOUTS names unknown route %s", route)
+		}
+	}
+	for route := range cfg.RateLimit.Routes {
+		if !registered[route] {
+			logger.Warn("RATE_LIMITS names unknown route %s", route)
+		}
+	}
+
+	// Metrics: a separate listener keeps them off the public port; otherwise require a token
+	var metricsSrv *http.Server
+	if cfg.MetricsAddr != "" {
+		metricsRouter := gin.New()
+		metricsRouter.Use(gin.Recovery())
+		metricsRouter.GET("/metrics", h.Metrics)
+		metricsSrv = &http.Server{Addr: cfg.MetricsAddr, Handler: metricsRouter}
+	} else if cfg.MetricsToken != "" {
+		router.GET("/metrics", middleware.MetricsToken(cfg.MetricsToken), h.Metrics)
+	}
 
 	// Start server
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "8080"
+	srv := &http.Server{
+		Addr:    fmt.Sprintf(":%d", cfg.Port),
+		Handler: router,
+		// Drops clients that send their headers too slowly
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	serverErr := make(chan error, 2)
+	go func() {
+		logger.Info("Server starting on port %d", cfg.Port)
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			serverErr <- err
+		}
+	}()
+	if metricsSrv != nil {
+		go func() {
+			logger.Info("Metrics server starting on %s", cfg.MetricsAddr)
+			if err := metricsSrv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				serverErr <- err
+			}
+		}()
 	}
 
-	logger.Info("Server starting on port %s", port)
-	if err := router.Run(":" + port); err != nil {
+	// Wait for SIGTERM (docker stop, Swarm rolling updates) or SIGINT
+	signals, stopSignals := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stopSignals()
+	select {
+	case err := <-serverErr:
+		db.Close()
 		logger.FatalWithErr("Failed to start server", err)
+	case <-signals.Done():
+	}
+	stopSignals()
+
+	// Fail health checks first so nginx and Swarm stop routing here, then drain
+	h.BeginShutdown()
+	logger.Info("Shutting down: health check unhealthy, draining in %s", cfg.ShutdownDelay)
+	time.Sleep(cfg.ShutdownDelay)
+
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(ctx); err != nil {
+		logger.Warn("In-flight requests did not finish before the shutdown timeout: %v", err)
+	}
+	if metricsSrv != nil {
+		metricsSrv.Shutdown(ctx)
+	}
+
+	stopBackground()
+	if err := tasks.Wait(ctx); err != nil {
+		logger.Warn("Background tasks did not finish before the shutdown timeout: %v", err)
+	}
+	if err := shutdownTracing(ctx); err != nil {
+		logger.Warn("Failed to flush traces: %v", err)
+	}
+
+	if err := db.Close(); err != nil {
+		logger.Warn("Failed to close database pool: %v", err)
 	}
+	logger.Info("Server stopped")
 }
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"bizgenie-api/internal/config"
+	"bizgenie-api/internal/database"
+)
+
+const migrateUsage = `usage: main migrate <command>
+
+commands:
+  up             apply all pending migrations
+  down [N]       roll back the last N applied migrations (default 1)
+  to N           apply or roll back migrations until version N is the latest applied (0 rolls back all)
+  status         list migrations, whether they are applied and whether their file changed since
+  baseline [N]   record migrations up to N (default all) as applied without running them,
+                 for a database whose schema was created outside the migrator
+  verify         compare the live schema with the schema the applied migrations produce`
+
+// migratorOptions fails on modified migration files in production and only warns elsewhere
+func migratorOptions(cfg *config.Config) database.MigratorOptions {
+	return database.MigratorOptions{
+		LockTimeout:     cfg.MigrationLockTimeout,
+		StrictChecksums: cfg.Environment == "production",
+	}
+}
+
+// runMigrateCommand handles the "migrate" subcommand of the server binary
+func runMigrateCommand(db *sql.DB, cfg *config.Config, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("%s", migrateUsage)
+	}
+
+	migrator, err := database.NewMigrator(db, migratorOptions(cfg))
+	if err != nil {
+		return err
+	}
+
+	switch args[0] {
+	case "up":
+		count, err := migrator.Up()
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Applied %d migration(s)\n", count)
+
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			steps, err = strconv.Atoi(args[1])
+			if err != nil || steps < 1 {
+				return fmt.Errorf("invalid number of steps: %s", args[1])
+			}
+		}
+		count, err := migrator.Down(steps)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Rolled back %d migration(s)\n", count)
+
+	case "to":
+		if len(args) < 2 {
+			return fmt.Errorf("%s", migrateUsage)
+		}
+		version, err := strconv.Atoi(args[1])
+		if err != nil || version < 0 {
+			return fmt.Errorf("invalid version: %s", args[1])
+		}
+		count, err := migrator.To(version)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Migrated to version %d (%d migration(s) applied or rolled back)\n", version, count)
+
+	case "status":
+		statuses, err := migrator.Status()
+		if err != nil {
+			return err
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tNAME\tSTATUS\tAPPLIED AT\tAPPLIED BY\tDURATION\tCHECKSUM")
+		for _, status := range statuses {
+			state, appliedAt, appliedBy, duration := "pending", "-", "-", "-"
+			if status.Applied {
+				state = "applied"
+				if status.Modified {
+					state = "applied (modified)"
+				}
+				appliedAt = status.AppliedAt.Format("2006-01-02 15:04:05")
+				if status.AppliedBy != nil {
+					appliedBy = *status.AppliedBy
+				}
+				// Rows written before durations were tracked have neither a duration nor a replica
+				if status.DurationMs != nil {
+					duration = fmt.Sprintf("%dms", *status.DurationMs)
+				} else if status.AppliedBy != nil {
+					duration = "baseline"
+				}
+			}
+			fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%s\t%s\n",
+				status.Version, status.Name, state, appliedAt, appliedBy, duration, status.Checksum[:12])
+		}
+		w.Flush()
+
+		unknown, err := migrator.UnknownApplied()
+		if err != nil {
+			return err
+		}
+		for _, name := range unknown {
+			fmt.Printf("warning: %s is applied but has no migration file in this binary\n", name)
+		}
+
+	case "baseline":
+		version := migrator.LatestVersion()
+		if len(args) > 1 {
+			version, err = strconv.Atoi(args[1])
+			if err != nil || version < 1 {
+				return fmt.Errorf("invalid version: %s", args[1])
+			}
+		}
+		count, err := migrator.Baseline(version)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Baselined %d migration(s) up to version %d\n", count, version)
+
+	case "verify":
+		diff, err := migrator.Verify()
+		if err != nil {
+			return err
+		}
+
+		for _, name := range diff.Modified {
+			fmt.Printf("modified:   migration %s changed after it was applied\n", name)
+		}
+		for _, obj := range diff.Missing {
+			fmt.Printf("missing:    %s\n", obj)
+		}
+		for _, obj := range diff.Changed {
+			fmt.Printf("changed:    %s\n", obj)
+		}
+		for _, obj := range diff.Unexpected {
+			fmt.Printf("unexpected: %s\n", obj)
+		}
+
+		if diff.HasErrors() {
+			return fmt.Errorf("live schema does not match the applied migrations")
+		}
+		fmt.Println("Schema matches the applied migrations")
+
+	default:
+		return fmt.Errorf("unknown migrate command: %s\n\n%s", args[0], migrateUsage)
+	}
+
+	return nil
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"bizgenie-api/internal/config"
+	"bizgenie-api/internal/database"
+	"bizgenie-api/internal/handlers"
+	"bizgenie-api/internal/services"
+)
+
+const seedUsage = `usage: main seed -profile demo|e2e [-reset]
+
+Loads the fixtures of a profile through the service layer, after migrations. Items are
+matched by natural key (username, slug, question, email, video URL, platform), so running
+it again only writes what differs from the fixtures.
+  -profile  fixture set to load
+  -reset    empty the content tables and users first, then recreate the default admin
+
+Refuses to run when ENVIRONMENT is production.`
+
+// runSeedCommand handles the "seed" subcommand of the server binary
+func runSeedCommand(db *sql.DB, cfg *config.Config, args []string) error {
+	flags := flag.NewFlagSet("seed", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	profile := flags.String("profile", "", "")
+	reset := flags.Bool("reset", false, "")
+	if err := flags.Parse(args); err != nil || flags.NArg() > 0 || *profile == "" {
+		return fmt.Errorf("%s", seedUsage)
+	}
+	if cfg.Environment == "production" {
+		return errors.New("refusing to seed a production database")
+	}
+
+	chat, embedder, err := handlers.LLMProviders(cfg)
+	if err != nil {
+		return err
+	}
+	chunks := services.NewChunkService(db, embedder)
+	assistant := services.NewAssistantService(db, chat, embedder, chunks, services.AssistantOptions{})
+	auth := services.NewAuthService(cfg.JWTSecret)
+	seeder := services.NewSeedService(db, auth, chunks, assistant)
+	ctx := context.Background()
+
+	if *reset {
+		if err := seeder.Reset(ctx); err != nil {
+			return err
+		}
+		if err := database.CreateDefaultAdmin(db, auth.HashPassword, cfg.AdminPassword, false); err != nil {
+			return err
+		}
+		fmt.Println("Emptied the content tables and recreated the default admin")
+	}
+
+	result, err := seeder.Seed(ctx, *profile)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "TYPE\tKEY\tACTION\tFIELDS")
+	for _, change := range result.Changes {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", change.Type, change.Key, change.Action, strings.Join(change.Fields, ","))
+	}
+	w.Flush()
+	fmt.Printf("Seeded profile %s: %d created, %d updated, %d unchanged\n", *profile, result.Created, result.Updated, result.Unchanged)
+	return nil
+}
@@ -0,0 +1,176 @@
+package apperror
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+)
+
+// Kind classifies an error by how the client should react to it, and sets the HTTP status
+type Kind int
+
+const (
+	KindInternal Kind = iota
+	KindValidation
+	KindUnauthorized
+	KindForbidden
+	KindNotFound
+	KindConflict
+	KindPayloadTooLarge
+	KindTooManyRequests
+	KindTimeout
+	KindUnavailable
+)
+
+// Status returns the HTTP status of errors of kind k
+func (k Kind) Status() int {
+	switch k {
+	case KindValidation:
+		return http.StatusBadRequest
+	case KindUnauthorized:
+		return http.StatusUnauthorized
+	case KindForbidden:
+		return http.StatusForbidden
+	case KindNotFound:
+		return http.StatusNotFound
+	case KindConflict:
+		return http.StatusConflict
+	case KindPayloadTooLarge:
+		return http.StatusRequestEntityTooLarge
+	case KindTooManyRequests:
+		return http.StatusTooManyRequests
+	case KindTimeout:
+		return http.StatusGatewayTimeout
+	case KindUnavailable:
+		return http.StatusServiceUnavailable
+	}
+	return http.StatusInternalServerError
+}
+
+// Error is an error the API can explain to its client. Code is stable and names the
+// message in the catalog, Args fill in its placeholders, and Fields lists the problems
+// with individual request fields. Err is the underlying cause; it is logged, never shown.
+type Error struct {
+	Kind   Kind
+	Code   string
+	Args   []any
+	Fields []FieldError
+	Err    error
+}
+
+// FieldError is a problem with one request field. Param is the limit, format or choices
+// the value failed, such as "6" for a minimum length.
+type FieldError struct {
+	Field string
+	Code  string
+	Param string
+}
+
+// New returns an error of kind with the message code and its arguments
+func New(kind Kind, code string, args ...any) *Error {
+	return &Error{Kind: kind, Code: code, Args: args}
+}
+
+func Internal(err error) *Error {
+	return &Error{Kind: KindInternal, Code: "internal_error", Err: err}
+}
+
+func Validation(code string, args ...any) *Error {
+	return New(KindValidation, code, args...)
+}
+
+// Invalid returns a validation error listing the fields that failed
+func Invalid(fields ...FieldError) *Error {
+	return &Error{Kind: KindValidation, Code: "validation_failed", Fields: fields}
+}
+
+func Unauthorized(code string, args ...any) *Error {
+	return New(KindUnauthorized, code, args...)
+}
+
+func Forbidden(code string, args ...any) *Error {
+	return New(KindForbidden, code, args...)
+}
+
+func NotFound(code string, args ...any) *Error {
+	return New(KindNotFound, code, args...)
+}
+
+func Conflict(code string, args ...any) *Error {
+	return New(KindConflict, code, args...)
+}
+
+func TooManyRequests(code string, args ...any) *Error {
+	return New(KindTooManyRequests, code, args...)
+}
+
+func Unavailable(code string, args ...any) *Error {
+	return New(KindUnavailable, code, args...)
+}
+
+// Error returns the English message followed by the cause, for logs and the CLI
+func (e *Error) Error() string {
+	msg := e.Message(English)
+	if e.Err != nil {
+		msg += ": " + e.Err.Error()
+	}
+	return msg
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is matches errors of the same kind and code, so an *Error declared as a sentinel can
+// be checked with errors.Is whatever arguments and cause it was returned with
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Kind == e.Kind && t.Code == e.Code
+}
+
+// Status returns the HTTP status of the error
+func (e *Error) Status() int {
+	return e.Kind.Status()
+}
+
+// Wrap returns a copy of e caused by err
+func (e *Error) Wrap(err error) *Error {
+	copied := *e
+	copied.Err = err
+	return &copied
+}
+
+// WithArgs returns a copy of e with the message arguments args
+func (e *Error) WithArgs(args ...any) *Error {
+	copied := *e
+	copied.Args = args
+	return &copied
+}
+
+// WithFields returns a copy of e listing the fields that caused it
+func (e *Error) WithFields(fields ...FieldError) *Error {
+	copied := *e
+	copied.Fields = fields
+	return &copied
+}
+
+// KindOf returns the kind of err as From classifies it
+func KindOf(err error) Kind {
+	return From(err).Kind
+}
+
+// From returns err as an *Error. Errors that are not one already become internal errors,
+// except canceled or timed out requests and sql.ErrNoRows, which has no better name here.
+func From(err error) *Error {
+	var appErr *Error
+	switch {
+	case errors.As(err, &appErr):
+		return appErr
+	case errors.Is(err, context.DeadlineExceeded), errors.Is(err, context.Canceled):
+		return New(KindTimeout, "timeout").Wrap(err)
+	case errors.Is(err, sql.ErrNoRows):
+		return NotFound("not_found").Wrap(err)
+	}
+	return Internal(err)
+}
@@ -0,0 +1,175 @@
+package apperror
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Languages messages are available in; English is the default
+const (
+	English    = "en"
+	Vietnamese = "vi"
+)
+
+type message struct {
+	en, vi string
+}
+
+func (m message) in(lang string) string {
+	if lang == Vietnamese {
+		return m.vi
+	}
+	return m.en
+}
+
+// messages holds the message of each error code; Args fill in the placeholders
+var messages = map[string]message{
+	// Fallbacks by kind
+	"internal_error":      {"An unexpected error occurred, please try again later", "Đã xảy ra lỗi không mong muốn, vui lòng thử lại sau"},
+	"validation_failed":   {"The request is invalid", "Yêu cầu không hợp lệ"},
+	"unauthorized":        {"Authentication is required", "Cần xác thực để tiếp tục"},
+	"forbidden":           {"You are not allowed to do this", "Bạn không có quyền thực hiện thao tác này"},
+	"not_found":           {"The requested resource was not found", "Không tìm thấy tài nguyên được yêu cầu"},
+	"conflict":            {"The request conflicts with existing data", "Yêu cầu xung đột với dữ liệu hiện có"},
+	"payload_too_large":   {"Request body is too large", "Nội dung yêu cầu quá lớn"},
+	"too_many_requests":   {"Too many requests, please try again later", "Quá nhiều yêu cầu, vui lòng thử lại sau"},
+	"timeout":             {"Request timed out", "Yêu cầu đã quá thời gian xử lý"},
+	"service_unavailable": {"The service is temporarily unavailable", "Dịch vụ tạm thời không khả dụng"},
+
+	// Request
+	"invalid_json":    {"Request body is not valid JSON", "Nội dung yêu cầu không phải JSON hợp lệ"},
+	"unreadable_body": {"Failed to read request body", "Không đọc được nội dung yêu cầu"},
+	"body_too_large":  {"Request body exceeds the %[1]s limit", "Nội dung yêu cầu vượt quá giới hạn %[1]s"},
+	"json_too_deep":   {"JSON nesting exceeds %[1]d levels", "JSON lồng nhau vượt quá %[1]d cấp"},
+	"rate_limited":    {"Too many requests, please try again later", "Quá nhiều yêu cầu, vui lòng thử lại sau"},
+	"invalid_value":   {"A value in the request is invalid", "Một giá trị trong yêu cầu không hợp lệ"},
+	"value_too_long":  {"A value in the request is too long", "Một giá trị trong yêu cầu quá dài"},
+	"duplicate_value": {"A record with the same value already exists", "Đã có bản ghi với giá trị này"},
+	"resource_in_use": {"The resource is still in use by other records", "Tài nguyên vẫn đang được dữ liệu khác sử dụng"},
+	"slug_taken":      {"The slug is already in use", "Slug đã được sử dụng"},
+	"username_taken":  {"The username is already in use", "Tên đăng nhập đã được sử dụng"},
+	"email_taken":     {"The email address is already in use", "Địa chỉ email đã được sử dụng"},
+
+	// Authentication
+	"authorization_required":       {"Authorization header required", "Thiếu header Authorization"},
+	"invalid_authorization_header": {"Invalid authorization header format", "Header Authorization không đúng định dạng"},
+	"invalid_token":                {"Invalid or expired token", "Token không hợp lệ hoặc đã hết hạn"},
+	"invalid_credentials":          {"Invalid credentials", "Tên đăng nhập hoặc mật khẩu không đúng"},
+	"invalid_metrics_token":        {"Invalid metrics token", "Token metrics không hợp lệ"},
+	"admin_required":               {"Only admins can do this", "Chỉ quản trị viên mới được thực hiện thao tác này"},
+
+	// Resources
+	"product_not_found":           {"Product not found", "Không tìm thấy sản phẩm"},
+	"category_not_found":          {"Category not found", "Không tìm thấy danh mục"},
+	"blog_post_not_found":         {"Blog post not found", "Không tìm thấy bài viết"},
+	"blog_category_not_found":     {"Blog category not found", "Không tìm thấy chuyên mục blog"},
+	"faq_not_found":               {"FAQ not found", "Không tìm thấy câu hỏi thường gặp"},
+	"social_media_link_not_found": {"Social media link not found", "Không tìm thấy liên kết mạng xã hội"},
+	"video_demo_not_found":        {"Video demo not found", "Không tìm thấy video demo"},
+	"contact_not_found":           {"Contact not found", "Không tìm thấy liên hệ"},
+	"user_not_found":              {"User not found", "Không tìm thấy người dùng"},
+	"conversation_not_found":      {"Conversation not found", "Không tìm thấy cuộc hội thoại"},
+	"search_not_found":            {"Search not found", "Không tìm thấy lượt tìm kiếm"},
+
+	// Features
+	"assistant_quota_exceeded":         {"Too many questions, please try again later", "Bạn đã hỏi quá nhiều, vui lòng thử lại sau"},
+	"assistant_disabled":               {"The assistant is not enabled on this site", "Trợ lý chưa được bật trên trang này"},
+	"vector_index_rebuild_in_progress": {"A vector index rebuild is already in progress, please try again later", "Vector index đang được dựng lại, vui lòng thử lại sau"},
+	"invalid_content_bundle":           {"The content bundle is invalid: %[1]s", "Gói nội dung không hợp lệ: %[1]s"},
+	"invalid_content_selection":        {"The content selection is invalid: %[1]s", "Lựa chọn nội dung không hợp lệ: %[1]s"},
+}
+
+// kindCodes names the fallback message of each kind, for codes missing from the catalog
+var kindCodes = map[Kind]string{
+	KindInternal:        "internal_error",
+	KindValidation:      "validation_failed",
+	KindUnauthorized:    "unauthorized",
+	KindForbidden:       "forbidden",
+	KindNotFound:        "not_found",
+	KindConflict:        "conflict",
+	KindPayloadTooLarge: "payload_too_large",
+	KindTooManyRequests: "too_many_requests",
+	KindTimeout:         "timeout",
+	KindUnavailable:     "service_unavailable",
+}
+
+// fieldMessages holds the message of each field error code; the field name fills in %[1]s
+// and Param %[2]s
+var fieldMessages = map[string]message{
+	"required":      {"%[1]s is required", "%[1]s là bắt buộc"},
+	"invalid":       {"%[1]s is invalid", "%[1]s không hợp lệ"},
+	"invalid_type":  {"%[1]s must be of type %[2]s", "%[1]s phải có kiểu %[2]s"},
+	"invalid_email": {"%[1]s must be a valid email address", "%[1]s phải là địa chỉ email hợp lệ"},
+	"invalid_url":   {"%[1]s must be a valid URL", "%[1]s phải là URL hợp lệ"},
+	"invalid_date":  {"%[1]s must be a date in the format %[2]s", "%[1]s phải là ngày theo định dạng %[2]s"},
+	"one_of":        {"%[1]s must be one of: %[2]s", "%[1]s phải là một trong: %[2]s"},
+	"too_short":     {"%[1]s must be at least %[2]s characters long", "%[1]s phải có ít nhất %[2]s ký tự"},
+	"too_long":      {"%[1]s must be at most %[2]s characters long", "%[1]s chỉ được có tối đa %[2]s ký tự"},
+	"too_small":     {"%[1]s must be at least %[2]s", "%[1]s phải lớn hơn hoặc bằng %[2]s"},
+	"too_large":     {"%[1]s must be at most %[2]s", "%[1]s phải nhỏ hơn hoặc bằng %[2]s"},
+	"not_after":     {"%[1]s must not be after %[2]s", "%[1]s không được sau %[2]s"},
+	"not_found":     {"%[1]s refers to a record that does not exist", "%[1]s tham chiếu đến bản ghi không tồn tại"},
+	"taken":         {"%[1]s is already in use", "%[1]s đã được sử dụng"},
+}
+
+// Message returns the message of e in lang. A validation error lists its field messages
+// after its own.
+func (e *Error) Message(lang string) string {
+	m, ok := messages[e.Code]
+	if !ok {
+		m = messages[kindCodes[e.Kind]]
+	}
+	msg := m.in(lang)
+	if len(e.Args) > 0 && strings.Contains(msg, "%") {
+		msg = fmt.Sprintf(msg, e.Args...)
+	}
+	if e.Kind == KindValidation && len(e.Fields) > 0 {
+		details := make([]string, len(e.Fields))
+		for i, f := range e.Fields {
+			details[i] = f.Message(lang)
+		}
+		msg += ": " + strings.Join(details, "; ")
+	}
+	return msg
+}
+
+// Message returns the message of f in lang
+func (f FieldError) Message(lang string) string {
+	m, ok := fieldMessages[f.Code]
+	if !ok {
+		m = fieldMessages["invalid"]
+	}
+	return fmt.Sprintf(m.in(lang), f.Field, f.Param)
+}
+
+// Language picks the language to answer in from an Accept-Language header, preferring
+// the client's order and weights and falling back to English
+func Language(acceptLanguage string) string {
+	type candidate struct {
+		lang   string
+		weight float64
+	}
+	var candidates []candidate
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		weight := 1.0
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			w, err := strconv.ParseFloat(q, 64)
+			if err != nil {
+				continue
+			}
+			weight = w
+		}
+		primary, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(tag)), "-")
+		if (primary == English || primary == Vietnamese) && weight > 0 {
+			candidates = append(candidates, candidate{primary, weight})
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].weight > candidates[j].weight })
+	if len(candidates) == 0 {
+		return English
+	}
+	return candidates[0].lang
+}
@@ -0,0 +1,81 @@
+package apperror
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestLanguage(t *testing.T) {
+	tests := []struct {
+		header string
+		want   string
+	}{
+		{"", English},
+		{"vi", Vietnamese},
+		{"VI", Vietnamese},
+		{"vi-VN,vi;q=0.9,en;q=0.8", Vietnamese},
+		{"en-US,vi;q=0.5", English},
+		{"en;q=0.4, vi;q=0.6", Vietnamese},
+		{"en;q=0.8, vi;q=0.8", English},
+		{"fr-FR,vi;q=0.7", Vietnamese},
+		{"fr", English},
+		{"*", English},
+		{"vi;q=0, en;q=0.1", English},
+		{"vi;q=abc, en;q=0.1", English},
+	}
+	for _, tt := range tests {
+		if got := Language(tt.header); got != tt.want {
+			t.Errorf("Language(%q) = %q, want %q", tt.header, got, tt.want)
+		}
+	}
+}
+
+func TestMessage(t *testing.T) {
+	tests := []struct {
+		name string
+		err  *Error
+		lang string
+		want string
+	}{
+		{"catalog", NotFound("product_not_found"), Vietnamese, "Không tìm thấy sản phẩm"},
+		{"catalog in english", NotFound("product_not_found"), English, "Product not found"},
+		{"unknown language", NotFound("product_not_found"), "fr", "Product not found"},
+		{"unknown code falls back to kind", NotFound("widget_not_found"), English, "The requested resource was not found"},
+		{"args", New(KindPayloadTooLarge, "body_too_large", "16KB"), English, "Request body exceeds the 16KB limit"},
+		{"numeric args", Validation("json_too_deep", 32), Vietnamese, "JSON lồng nhau vượt quá 32 cấp"},
+		{"fields", Invalid(
+			FieldError{Field: "username", Code: "required"},
+			FieldError{Field: "password", Code: "too_short", Param: "6"},
+		), Vietnamese, "Yêu cầu không hợp lệ: username là bắt buộc; password phải có ít nhất 6 ký tự"},
+		{"unknown field code", Invalid(FieldError{Field: "table", Code: "bogus"}), English, "The request is invalid: table is invalid"},
+		{"cause never shown", Internal(errors.New("pq: relation \"users\" does not exist")), English,
+			"An unexpected error occurred, please try again later"},
+	}
+	for _, tt := range tests {
+		if got := tt.err.Message(tt.lang); got != tt.want {
+			t.Errorf("%s: Message(%q) = %q, want %q", tt.name, tt.lang, got, tt.want)
+		}
+	}
+}
+
+// Every message needs both languages with the same placeholders
+func TestMessageCatalog(t *testing.T) {
+	for name, catalog := range map[string]map[string]message{"messages": messages, "fieldMessages": fieldMessages} {
+		for code, m := range catalog {
+			if m.en == "" || m.vi == "" {
+				t.Errorf("%s[%q] is missing a translation", name, code)
+			}
+			for _, verb := range []string{"%[1]s", "%[1]d", "%[2]s"} {
+				if strings.Contains(m.en, verb) != strings.Contains(m.vi, verb) {
+					t.Errorf("%s[%q]: %s is used in only one language", name, code, verb)
+				}
+			}
+		}
+	}
+	for kind, code := range kindCodes {
+		if _, ok := messages[code]; !ok {
+			t.Errorf("kind %v falls back to %q, which has no message", kind, code)
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package clientip
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+type contextKey struct{}
+
+// Resolver finds the address of the client behind the trusted proxies. Forwarding headers
+// are only read from requests sent by a trusted proxy, and are walked from the nearest hop
+// back, stopping at the first address that is not a trusted proxy: entries further left
+// were written by the client and can be forged.
+type Resolver struct {
+	trusted []netip.Prefix
+	headers []string
+}
+
+// NewResolver returns a Resolver trusting the proxies in the given CIDRs or single
+// addresses and reading the given headers in order of preference: Forwarded,
+// X-Forwarded-For or X-Real-IP.
+func NewResolver(trustedProxies, headers []string) (*Resolver, error) {
+	r := &Resolver{}
+	for _, proxy := range trustedProxies {
+		prefix, err := parsePrefix(proxy)
+		if err != nil {
+			return nil, err
+		}
+		r.trusted = append(r.trusted, prefix)
+	}
+	for _, header := range headers {
+		switch http.CanonicalHeaderKey(header) {
+		case "Forwarded", "X-Forwarded-For", "X-Real-Ip":
+		default:
+			return nil, fmt.Errorf("unsupported client IP header %q", header)
+		}
+		r.headers = append(r.headers, http.CanonicalHeaderKey(header))
+	}
+	return r, nil
+}
+
+// parsePrefix parses a CIDR such as "10.0.0.0/8", or a single address as a /32 or /128
+func parsePrefix(s string) (netip.Prefix, error) {
+	if strings.Contains(s, "/") {
+		prefix, err := netip.ParsePrefix(s)
+		if err != nil {
+			return netip.Prefix{}, fmt.Errorf("invalid CIDR %q", s)
+		}
+		return prefix.Masked(), nil
+	}
+	addr, err := netip.ParseAddr(s)
+	if err != nil {
+		return netip.Prefix{}, fmt.Errorf("invalid IP address %q", s)
+	}
+	return netip.PrefixFrom(addr.Unmap(), addr.Unmap().BitLen()), nil
+}
+
+// Resolve returns the client address of req, or "" if the peer address is not an IP
+// address, as on requests made in-process
+func (r *Resolver) Resolve(req *http.Request) string {
+	remote, err := parseAddr(req.RemoteAddr)
+	if err != nil {
+		return ""
+	}
+	if !r.isTrusted(remote) {
+		return remote.String()
+	}
+
+	for _, header := range r.headers {
+		values := req.Header.Values(header)
+		if len(values) == 0 {
+			continue
+		}
+		hops, ok := parseHops(header, values)
+		if !ok {
+			continue
+		}
+		// The hops were appended by each proxy in turn, so the nearest is last
+		client := remote
+		for i := len(hops) - 1; i >= 0; i-- {
+			client = hops[i]
+			if !r.isTrusted(client) {
+				break
+			}
+		}
+		return client.String()
+	}
+	return remote.String()
+}
+
+func (r *Resolver) isTrusted(addr netip.Addr) bool {
+	for _, prefix := range r.trusted {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseHops returns the addresses listed by a forwarding header, or false if any entry is
+// not an address, such as "unknown" or an obfuscated Forwarded identifier, since the
+// chain cannot be followed past it
+func parseHops(header string, values []string) ([]netip.Addr, bool) {
+	var hops []netip.Addr
+	for _, value := range values {
+		for _, entry := range strings.Split(value, ",") {
+			if header == "Forwarded" {
+				entry = forwardedFor(entry)
+			}
+			addr, err := parseAddr(strings.TrimSpace(entry))
+			if err != nil {
+				return nil, false
+			}
+			hops = append(hops, addr)
+		}
+	}
+	return hops, len(hops) > 0
+}
+
+// forwardedFor returns the for= parameter of one element of a Forwarded header (RFC 7239),
+// such as `for=192.0.2.60;proto=https` or `for="[2001:db8::1]:4711"`
+func forwardedFor(element string) string {
+	for _, pair := range strings.Split(element, ";") {
+		name, value, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if ok && strings.EqualFold(name, "for") {
+			return strings.Trim(value, `"`)
+		}
+	}
+	return ""
+}
+
+// parseAddr parses an address with or without a port, IPv6 addresses in brackets or not
+func parseAddr(s string) (netip.Addr, error) {
+	if host, _, err := net.SplitHostPort(s); err == nil {
+		s = host
+	}
+	addr, err := netip.ParseAddr(strings.Trim(s, "[]"))
+	if err != nil {
+		return netip.Addr{}, err
+	}
+	// Drop the zone and the IPv4-in-IPv6 mapping so equal clients compare equal
+	return addr.WithZone("").Unmap(), nil
+}
+
+// NewContext returns a copy of ctx carrying the client address ip
+func NewContext(ctx context.Context, ip string) context.Context {
+	return context.WithValue(ctx, contextKey{}, ip)
+}
+
+// FromContext returns the client address stored in ctx, or "" outside a request
+func FromContext(ctx context.Context) string {
+	ip, _ := ctx.Value(contextKey{}).(string)
+	return ip
+}
@@ -0,0 +1,79 @@
+package clientip
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestResolve(t *testing.T) {
+	resolver, err := NewResolver(
+		[]string{"10.0.0.0/8", "172.16.0.0/12", "::1"},
+		[]string{"Forwarded", "X-Forwarded-For", "X-Real-IP"},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		remote  string
+		headers map[string][]string
+		want    string
+	}{
+		{name: "direct client", remote: "203.0.113.7:51234", want: "203.0.113.7"},
+		{name: "untrusted peer forging headers", remote: "203.0.113.7:51234",
+			headers: map[string][]string{"X-Forwarded-For": {"198.51.100.1"}}, want: "203.0.113.7"},
+		{name: "trusted proxy without headers", remote: "10.0.0.5:80", want: "10.0.0.5"},
+		{name: "x-forwarded-for", remote: "10.0.0.5:80",
+			headers: map[string][]string{"X-Forwarded-For": {"198.51.100.1"}}, want: "198.51.100.1"},
+		{name: "forged entries left of the client are ignored", remote: "10.0.0.5:80",
+			headers: map[string][]string{"X-Forwarded-For": {"1.2.3.4, 198.51.100.1, 172.18.0.3"}}, want: "198.51.100.1"},
+		{name: "repeated header lines", remote: "10.0.0.5:80",
+			headers: map[string][]string{"X-Forwarded-For": {"1.2.3.4", "198.51.100.1"}}, want: "198.51.100.1"},
+		{name: "only trusted hops", remote: "10.0.0.5:80",
+			headers: map[string][]string{"X-Forwarded-For": {"172.18.0.3, 10.0.0.9"}}, want: "172.18.0.3"},
+		{name: "x-real-ip", remote: "10.0.0.5:80",
+			headers: map[string][]string{"X-Real-Ip": {"198.51.100.2"}}, want: "198.51.100.2"},
+		{name: "forwarded preferred over x-forwarded-for", remote: "10.0.0.5:80",
+			headers: map[string][]string{
+				"Forwarded":       {`for=198.51.100.3;proto=https`},
+				"X-Forwarded-For": {"198.51.100.1"},
+			}, want: "198.51.100.3"},
+		{name: "forwarded ipv6 with port", remote: "[::1]:80",
+			headers: map[string][]string{"Forwarded": {`for="[2001:db8::1]:4711"`}}, want: "2001:db8::1"},
+		{name: "unknown hop falls back to the next header", remote: "10.0.0.5:80",
+			headers: map[string][]string{
+				"Forwarded": {"for=unknown"},
+				"X-Real-Ip": {"198.51.100.2"},
+			}, want: "198.51.100.2"},
+		{name: "invalid header falls back to the peer", remote: "10.0.0.5:80",
+			headers: map[string][]string{"X-Forwarded-For": {"not-an-ip"}}, want: "10.0.0.5"},
+		{name: "ipv4-mapped peer", remote: "[::ffff:203.0.113.7]:51234", want: "203.0.113.7"},
+		{name: "peer without an address", remote: "pipe", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := &http.Request{RemoteAddr: tt.remote, Header: http.Header(tt.headers)}
+			if got := resolver.Resolve(req); got != tt.want {
+				t.Errorf("Resolve = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewResolverRejectsInvalidSettings(t *testing.T) {
+	tests := []struct {
+		proxies []string
+		headers []string
+	}{
+		{proxies: []string{"10.0.0.0/33"}},
+		{proxies: []string{"proxy.local"}},
+		{headers: []string{"CF-Connecting-IP"}},
+	}
+	for _, tt := range tests {
+		if _, err := NewResolver(tt.proxies, tt.headers); err == nil {
+			t.Errorf("NewResolver(%v, %v) succeeded, want an error", tt.proxies, tt.headers)
+		}
+	}
+}
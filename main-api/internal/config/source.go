@@ -0,0 +1,326 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Where a setting's value came from
+const (
+	SourceEnv        = "env"
+	SourceSecretFile = "secret file"
+	SourceConfigFile = "config file"
+	SourceDefault    = "default"
+)
+
+// Setting is one resolved configuration value, as shown by "main config print"
+type Setting struct {
+	Key    string // environment variable name
+	Value  string
+	Source string
+	// Value as printed with -redacted: secrets are hidden, URLs lose their password
+	Redacted string
+}
+
+// loader resolves settings from, in order of precedence, environment variables, the files
+// named by KEY_FILE variables (Docker secrets), the YAML config file and the defaults.
+// Parse errors are collected so a single run reports every problem.
+type loader struct {
+	file     map[string]string // YAML config file, keyed by environment variable name
+	used     map[string]bool
+	settings []Setting
+	problems []string
+}
+
+func newLoader(path string) (*loader, error) {
+	l := &loader{file: map[string]string{}, used: map[string]bool{}}
+	if path == "" {
+		return l, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+	var values map[string]string
+	if err := yaml.Unmarshal(data, &values); err != nil {
+		return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+	for key, value := range values {
+		l.file[strings.ToUpper(key)] = value
+	}
+	return l, nil
+}
+
+func (l *loader) problem(format string, v ...interface{}) {
+	l.problems = append(l.problems, fmt.Sprintf(format, v...))
+}
+
+// lookup returns the raw value of key and its source, or def when it is not set anywhere
+func (l *loader) lookup(key, def string) (string, string) {
+	l.used[key] = true
+	value, inEnv := os.LookupEnv(key)
+	path, inFile := os.LookupEnv(key + "_FILE")
+	switch {
+	case inEnv && value != "" && inFile && path != "":
+		l.problem("%s and %s_FILE are both set", key, key)
+		return value, SourceEnv
+	case inEnv && value != "":
+		return value, SourceEnv
+	case inFile && path != "":
+		data, err := os.ReadFile(path)
+		if err != nil {
+			l.problem("%s_FILE: %v", key, err)
+			return "", SourceSecretFile
+		}
+		return strings.TrimRight(string(data), "\r\n"), SourceSecretFile
+	}
+	if value, ok := l.file[key]; ok && value != "" {
+		return value, SourceConfigFile
+	}
+	return def, SourceDefault
+}
+
+func (l *loader) record(key, value, source, redacted string) {
+	l.settings = append(l.settings, Setting{Key: key, Value: value, Source: source, Redacted: redacted})
+}
+
+func (l *loader) string(key, def string) string {
+	value, source := l.lookup(key, def)
+	l.record(key, value, source, value)
+	return value
+}
+
+// optional reads a value that has a default but can be turned off by setting it to "off"
+func (l *loader) optional(key, def string) string {
+	value := l.string(key, def)
+	if strings.EqualFold(value, "off") {
+		return ""
+	}
+	return value
+}
+
+// secret reads a value hidden by "config print -redacted", such as a password or token
+func (l *loader) secret(key, def string) string {
+	value, source := l.lookup(key, def)
+	redacted := ""
+	if value != "" {
+		redacted = "[REDACTED]"
+	}
+	l.record(key, value, source, redacted)
+	return value
+}
+
+// url reads an absolute URL with one of schemes, or an empty value when def is empty.
+// Its password, if any, is hidden by "config print -redacted".
+func (l *loader) url(key, def string, schemes ...string) string {
+	value, source := l.lookup(key, def)
+	if value == "" {
+		l.record(key, value, source, value)
+		return value
+	}
+	u, err := url.Parse(value)
+	if err != nil || u.Host == "" || !contains(schemes, u.Scheme) {
+		l.problem("%s must be a %s URL", key, strings.Join(schemes, "/"))
+		l.record(key, value, source, "[REDACTED]")
+		return value
+	}
+	l.record(key, value, source, u.Redacted())
+	return value
+}
+
+// list reads a comma-separated list, dropping empty entries. "off" is an empty list, for
+// lists whose default is not.
+func (l *loader) list(key, def string) []string {
+	value, source := l.lookup(key, def)
+	l.record(key, value, source, value)
+	if strings.EqualFold(value, "off") {
+		return nil
+	}
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func (l *loader) int(key string, def int) int {
+	value, source := l.lookup(key, strconv.Itoa(def))
+	l.record(key, value, source, value)
+	i, err := strconv.Atoi(value)
+	if err != nil {
+		l.problem("%s must be an integer, got %q", key, value)
+		return def
+	}
+	return i
+}
+
+func (l *loader) float(key string, def float64) float64 {
+	value, source := l.lookup(key, strconv.FormatFloat(def, 'g', -1, 64))
+	l.record(key, value, source, value)
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		l.problem("%s must be a number, got %q", key, value)
+		return def
+	}
+	return f
+}
+
+func (l *loader) bool(key string, def bool) bool {
+	value, source := l.lookup(key, strconv.FormatBool(def))
+	l.record(key, value, source, value)
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		l.problem("%s must be true or false, got %q", key, value)
+		return def
+	}
+	return b
+}
+
+// duration reads a Go duration such as "90s" or "5m". A bare number is taken as seconds,
+// the unit these settings used before.
+func (l *loader) duration(key string, def time.Duration) time.Duration {
+	value, source := l.lookup(key, def.String())
+	l.record(key, value, source, value)
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(seconds) * time.Second
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		l.problem("%s must be a duration such as 30s or 5m, got %q", key, value)
+		return def
+	}
+	return d
+}
+
+// size reads a byte count such as "512KB" or "20MB"; units are powers of 1024
+func (l *loader) size(key, def string) int64 {
+	value, source := l.lookup(key, def)
+	l.record(key, value, source, value)
+	n, err := parseSize(value)
+	if err != nil {
+		l.problem("%s: %v", key, err)
+	}
+	return n
+}
+
+// routeSizes reads byte counts per route, written as "POST /api/contacts=16KB, ..."
+func (l *loader) routeSizes(key, def string) map[string]int64 {
+	sizes := map[string]int64{}
+	for route, value := range l.routeValues(key, def) {
+		n, err := parseSize(value)
+		if err != nil {
+			l.problem("%s: %s: %v", key, route, err)
+			continue
+		}
+		sizes[route] = n
+	}
+	return sizes
+}
+
+// routeDurations reads durations per route, written as "POST /api/assistant/ask=2m, ..."
+func (l *loader) routeDurations(key, def string) map[string]time.Duration {
+	durations := map[string]time.Duration{}
+	for route, value := range l.routeValues(key, def) {
+		d, err := time.ParseDuration(value)
+		if err != nil || d <= 0 {
+			l.problem("%s: %s must be a positive duration such as 30s or 5m, got %q", key, route, value)
+			continue
+		}
+		durations[route] = d
+	}
+	return durations
+}
+
+// routeRates reads rate limits per route, written as "POST /api/contacts=5/10m, ...". A
+// period without a number, as in "60/m", is one of that unit.
+func (l *loader) routeRates(key, def string) map[string]RateLimit {
+	rates := map[string]RateLimit{}
+	for route, value := range l.routeValues(key, def) {
+		limit, period, _ := strings.Cut(value, "/")
+		n, err := strconv.Atoi(strings.TrimSpace(limit))
+		if err != nil || n <= 0 {
+			l.problem("%s: %s must be a positive number of requests per period such as 5/10m, got %q", key, route, value)
+			continue
+		}
+		period = strings.TrimSpace(period)
+		if period != "" && (period[0] < '0' || period[0] > '9') {
+			period = "1" + period
+		}
+		d, err := time.ParseDuration(period)
+		if err != nil || d <= 0 {
+			l.problem("%s: %s must be a positive number of requests per period such as 5/10m, got %q", key, route, value)
+			continue
+		}
+		rates[route] = RateLimit{Limit: n, Period: d}
+	}
+	return rates
+}
+
+// routeValues splits "METHOD /path=value" entries, keyed by "METHOD /path"
+func (l *loader) routeValues(key, def string) map[string]string {
+	values := map[string]string{}
+	for _, entry := range l.list(key, def) {
+		route, value, ok := strings.Cut(entry, "=")
+		method, path, hasPath := strings.Cut(strings.TrimSpace(route), " ")
+		path = strings.TrimSpace(path)
+		if !ok || !hasPath || method != strings.ToUpper(method) || !strings.HasPrefix(path, "/") {
+			l.problem("%s: invalid entry %q, expected METHOD /route=value", key, entry)
+			continue
+		}
+		values[method+" "+path] = strings.TrimSpace(value)
+	}
+	return values
+}
+
+// parseSize parses a byte count with an optional B, KB, MB or GB unit
+func parseSize(value string) (int64, error) {
+	upper := strings.ToUpper(strings.TrimSpace(value))
+	multiplier := int64(1)
+	for _, unit := range []struct {
+		suffix string
+		bytes  int64
+	}{{"GB", 1 << 30}, {"MB", 1 << 20}, {"KB", 1 << 10}, {"B", 1}} {
+		if strings.HasSuffix(upper, unit.suffix) {
+			upper = strings.TrimSpace(strings.TrimSuffix(upper, unit.suffix))
+			multiplier = unit.bytes
+			break
+		}
+	}
+	n, err := strconv.ParseInt(upper, 10, 64)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("must be a positive size such as 512KB or 20MB, got %q", value)
+	}
+	return n * multiplier, nil
+}
+
+// unknownKeys reports config file keys that match no setting, usually typos
+func (l *loader) unknownKeys() {
+	var unknown []string
+	for key := range l.file {
+		if !l.used[key] {
+			unknown = append(unknown, strings.ToLower(key))
+		}
+	}
+	sort.Strings(unknown)
+	for _, key := range unknown {
+		l.problem("unknown setting %s in config file", key)
+	}
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,173 @@
+package config
+
+import (
+	"net/url"
+	"strings"
+
+	"bizgenie-api/internal/clientip"
+)
+
+// Shortest JWT_SECRET accepted in production: 32 bytes, as from "openssl rand -hex 16"
+const minProductionSecretLength = 32
+
+// validate checks ranges and enumerations that parsing alone does not, then refuses the
+// insecure development defaults in production
+func (c *Config) validate(l *loader) {
+	if c.Port < 1 || c.Port > 65535 {
+		l.problem("PORT must be between 1 and 65535, got %d", c.Port)
+	}
+	switch strings.ToLower(c.LogLevel) {
+	case "debug", "info", "warn", "warning", "error":
+	default:
+		l.problem("LOG_LEVEL must be debug, info, warn or error, got %q", c.LogLevel)
+	}
+	if c.LogFormat != "json" && c.LogFormat != "text" {
+		l.problem("LOG_FORMAT must be json or text, got %q", c.LogFormat)
+	}
+	if c.SlackWebhookURL != "" {
+		if u, err := url.Parse(c.SlackWebhookURL); err != nil || u.Scheme != "https" || u.Host == "" {
+			l.problem("SLACK_WEBHOOK_URL must be an https URL")
+		}
+	}
+	if c.TraceSampleRatio < 0 || c.TraceSampleRatio > 1 {
+		l.problem("TRACE_SAMPLE_RATIO must be between 0 and 1, got %g", c.TraceSampleRatio)
+	}
+	switch c.AssistantProvider {
+	case "", "fake", "openai":
+	default:
+		l.problem("ASSISTANT_PROVIDER must be fake or openai, got %q", c.AssistantProvider)
+	}
+
+	if c.MigrationLockTimeout <= 0 {
+		l.problem("MIGRATION_LOCK_TIMEOUT must be positive")
+	}
+	if c.ShutdownDelay < 0 {
+		l.problem("SHUTDOWN_DELAY must not be negative")
+	}
+	if c.ShutdownTimeout <= 0 {
+		l.problem("SHUTDOWN_TIMEOUT must be positive")
+	}
+	if c.HealthCheckTimeout <= 0 {
+		l.problem("HEALTH_CHECK_TIMEOUT must be positive")
+	}
+	for _, setting := range []struct {
+		key   string
+		value int
+	}{
+		{"BACKUP_INTERVAL_HOURS", c.BackupIntervalHours},
+		{"BACKUP_RETENTION", c.BackupRetention},
+		{"VECTOR_EF_SEARCH", c.VectorEfSearch},
+		{"VECTOR_PROBES", c.VectorProbes},
+		{"ASSISTANT_HOURLY_QUOTA", c.AssistantHourlyQuota},
+	} {
+		if setting.value < 0 {
+			l.problem("%s must not be negative, got %d", setting.key, setting.value)
+		}
+	}
+	if c.AssistantTopK < 1 {
+		l.problem("ASSISTANT_TOP_K must be at least 1, got %d", c.AssistantTopK)
+	}
+
+	if _, err := clientip.NewResolver(c.TrustedProxies, nil); err != nil {
+		l.problem("TRUSTED_PROXIES: %v", err)
+	}
+	if _, err := clientip.NewResolver(nil, c.ClientIPHeaders); err != nil {
+		l.problem("CLIENT_IP_HEADERS: %v", err)
+	}
+
+	c.CORS.validate(l, "CORS_")
+	c.AdminCORS.validate(l, "CORS_ADMIN_")
+
+	switch c.Security.FrameOptions {
+	case "", "DENY", "SAMEORIGIN":
+	default:
+		l.problem("FRAME_OPTIONS must be DENY, SAMEORIGIN or empty, got %q", c.Security.FrameOptions)
+	}
+	if c.Security.HSTSMaxAge < 0 {
+		l.problem("HSTS_MAX_AGE must not be negative")
+	}
+	if c.Limits.MaxJSONDepth < 1 {
+		l.problem("MAX_JSON_DEPTH must be at least 1, got %d", c.Limits.MaxJSONDepth)
+	}
+	if c.Limits.Timeout <= 0 {
+		l.problem("REQUEST_TIMEOUT must be positive")
+	}
+	switch c.RateLimit.Store {
+	case "postgres", "memory", "off":
+	default:
+		l.problem("RATE_LIMIT_STORE must be postgres, memory or off, got %q", c.RateLimit.Store)
+	}
+
+	if c.Environment == "production" {
+		c.validateProduction(l)
+	}
+}
+
+// validate checks the origins and methods of a policy read from settings starting with prefix
+func (p CORSPolicy) validate(l *loader, prefix string) {
+	for _, origin := range p.AllowedOrigins {
+		if origin == "*" {
+			if p.AllowCredentials {
+				l.problem("%sALLOWED_ORIGINS cannot be * when %sALLOW_CREDENTIALS is true", prefix, prefix)
+			}
+			continue
+		}
+		// A wildcard may only stand for the leftmost labels of the host
+		concrete := strings.Replace(origin, "://*.", "://wildcard.", 1)
+		u, err := url.Parse(concrete)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" ||
+			u.Path != "" || u.RawQuery != "" || u.User != nil || strings.Contains(concrete, "*") {
+			l.problem("%sALLOWED_ORIGINS: %q is not an origin such as https://example.com or https://*.example.com", prefix, origin)
+		}
+	}
+	for _, method := range p.AllowedMethods {
+		if method != strings.ToUpper(method) || strings.ContainsAny(method, " */") {
+			l.problem("%sALLOWED_METHODS: %q is not an HTTP method in upper case", prefix, method)
+		}
+	}
+	if p.MaxAge < 0 {
+		l.problem("%sMAX_AGE must not be negative", prefix)
+	}
+}
+
+// validateProduction refuses settings that are only safe on a developer machine
+func (c *Config) validateProduction(l *loader) {
+	if c.source("DATABASE_URL") == SourceDefault {
+		l.problem("DATABASE_URL must be set in production")
+	} else if u, err := url.Parse(c.DatabaseURL); err == nil {
+		if password, _ := u.User.Password(); password == "postgres" {
+			l.problem("DATABASE_URL uses the default postgres password in production")
+		}
+	}
+
+	for _, origin := range c.AdminCORS.AllowedOrigins {
+		if origin == "*" {
+			l.problem("CORS_ADMIN_ALLOWED_ORIGINS cannot be * in production")
+		}
+	}
+
+	// The fake provider would fill the content tables with meaningless vectors
+	if c.AssistantProvider == "fake" {
+		l.problem("ASSISTANT_PROVIDER cannot be fake in production")
+	}
+
+	switch {
+	case c.source("JWT_SECRET") == SourceDefault:
+		l.problem("JWT_SECRET must be set in production")
+	case strings.Contains(c.JWTSecret, "change-in-production"):
+		l.problem("JWT_SECRET is still the example value in production")
+	case len(c.JWTSecret) < minProductionSecretLength:
+		l.problem("JWT_SECRET must be at least %d characters in production", minProductionSecretLength)
+	}
+
+	switch {
+	case c.source("ADMIN_PASSWORD") == SourceDefault:
+		l.problem("ADMIN_PASSWORD must be set in production")
+	case strings.Contains(c.AdminPassword, "change-in-production"):
+		l.problem("ADMIN_PASSWORD is still the example value in production")
+	}
+	// Resetting the password on every start would undo changes made through the admin
+	if c.ForceUpdateAdmin {
+		l.problem("FORCE_UPDATE_ADMIN cannot be true in production")
+	}
+}
@@ -0,0 +1,586 @@
+package database
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"embed"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"bizgenie-api/internal/logger"
+)
+
+// migrationFS holds the migrations compiled into the binary.
+// Each migration is a pair NNN_description.up.sql / NNN_description.down.sql.
+//
+//go:embed migrations/*.sql
+var migrationFS embed.FS
+
+var migrationFilePattern = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.(up|down)\.sql$`)
+
+// migrationLockKey serializes migrations across API replicas
+const migrationLockKey = 727000
+
+// Migration is one versioned schema change with its rollback
+type Migration struct {
+	Version  int
+	Name     string // NNN_description, as recorded in schema_migrations
+	UpSQL    string
+	DownSQL  string
+	Checksum string // SHA-256 of UpSQL
+}
+
+// MigrationStatus reports whether a migration has been applied, when, by which replica,
+// and whether its file changed since
+type MigrationStatus struct {
+	Migration
+	Applied    bool
+	AppliedAt  *time.Time
+	AppliedBy  *string
+	DurationMs *int // nil for baselined migrations
+	Modified   bool // The applied checksum differs from the file
+}
+
+// MigratorOptions configures a Migrator
+type MigratorOptions struct {
+	// LockTimeout bounds how long to wait for another replica's migrations to finish
+	LockTimeout time.Duration
+	// StrictChecksums fails instead of warning when an applied migration file was modified
+	StrictChecksums bool
+}
+
+// Migrator applies and rolls back migrations, recording them in schema_migrations.
+// Every run holds a Postgres advisory lock so replicas starting together do not race.
+type Migrator struct {
+	db         *sql.DB
+	migrations []Migration
+	opts       MigratorOptions
+	replica    string
+}
+
+// NewMigrator returns a Migrator for the migrations embedded in the binary
+func NewMigrator(db *sql.DB, opts MigratorOptions) (*Migrator, error) {
+	migrations, err := LoadMigrations(migrationFS, "migrations")
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{db: db, migrations: migrations, opts: opts, replica: ReplicaID()}, nil
+}
+
+// ReplicaID identifies this process; in Docker the hostname is the container ID
+func ReplicaID() string {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "unknown"
+	}
+	return fmt.Sprintf("%s/%d", hostname, os.Getpid())
+}
+
+// LoadMigrations discovers the migration pairs in dir, ordered by their numeric prefix.
+// Every version must have exactly one up and one down file.
+func LoadMigrations(fsys fs.FS, dir string) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations: %w", err)
+	}
+
+	byVersion := map[int]*Migration{}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		match := migrationFilePattern.FindStringSubmatch(entry.Name())
+		if match == nil {
+			return nil, fmt.Errorf("invalid migration file name: %s", entry.Name())
+		}
+
+		version, _ := strconv.Atoi(match[1])
+		name := match[1] + "_" + match[2]
+
+		content, err := fs.ReadFile(fsys, dir+"/"+entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("failed to read migration file %s: %w", entry.Name(), err)
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: name}
+			byVersion[version] = m
+		}
+		if m.Name != name {
+			return nil, fmt.Errorf("migration version %d is used by both %s and %s", version, m.Name, name)
+		}
+
+		if match[3] == "up" {
+			m.UpSQL = string(content)
+			sum := sha256.Sum256(content)
+			m.Checksum = hex.EncodeToString(sum[:])
+		} else {
+			m.DownSQL = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if strings.TrimSpace(m.UpSQL) == "" {
+			return nil, fmt.Errorf("migration %s has no up file", m.Name)
+		}
+		if strings.TrimSpace(m.DownSQL) == "" {
+			return nil, fmt.Errorf("migration %s has no down file", m.Name)
+		}
+		migrations = append(migrations, *m)
+	}
+
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// RunMigrations applies all pending embedded migrations
+func RunMigrations(db *sql.DB, opts MigratorOptions) error {
+	m, err := NewMigrator(db, opts)
+	if err != nil {
+		return err
+	}
+	_, err = m.Up()
+	return err
+}
+
+// appliedMigration is a row of schema_migrations
+type appliedMigration struct {
+	at         time.Time
+	by         *string
+	checksum   *string
+	durationMs *int
+}
+
+// withLock runs fn on a dedicated connection holding the migration advisory lock.
+// If another replica holds the lock, it waits up to lockTimeout.
+func (m *Migrator) withLock(fn func(ctx context.Context, conn *sql.Conn) error) error {
+	ctx := context.Background()
+
+	// Advisory locks are per session, so keep a single connection for the whole run
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	var locked bool
+	if err := conn.QueryRowContext(ctx, `SELECT pg_try_advisory_lock($1)`, migrationLockKey).Scan(&locked); err != nil {
+		return fmt.Errorf("failed to acquire migration lock: %w", err)
+	}
+
+	if !locked {
+		logger.Info("Replica %s waiting up to %s for another replica to finish migrations", m.replica, m.opts.LockTimeout)
+		start := time.Now()
+
+		lockCtx, cancel := context.WithTimeout(ctx, m.opts.LockTimeout)
+		defer cancel()
+
+		if _, err := conn.ExecContext(lockCtx, `SELECT pg_advisory_lock($1)`, migrationLockKey); err != nil {
+			// The lock may have been granted just as the wait was cancelled
+			conn.ExecContext(ctx, `SELECT pg_advisory_unlock_all()`)
+			if lockCtx.Err() != nil {
+				return fmt.Errorf("timed out after %s waiting for migration lock", m.opts.LockTimeout)
+			}
+			return fmt.Errorf("failed to acquire migration lock: %w", err)
+		}
+		logger.Info("Replica %s acquired migration lock after %s", m.replica, time.Since(start).Round(time.Millisecond))
+	}
+	defer conn.ExecContext(ctx, `SELECT pg_advisory_unlock($1)`, migrationLockKey)
+
+	if err := ensureMigrationsTable(ctx, conn); err != nil {
+		return fmt.Errorf("failed to create migrations table: %w", err)
+	}
+
+	return fn(ctx, conn)
+}
+
+// Up applies all pending migrations and returns how many were applied
+func (m *Migrator) Up() (int, error) {
+	return m.migrateTo(m.LatestVersion())
+}
+
+// Down rolls back the last steps applied migrations and returns how many were rolled back
+func (m *Migrator) Down(steps int) (int, error) {
+	count := 0
+	err := m.withLock(func(ctx context.Context, conn *sql.Conn) error {
+		applied, err := appliedMigrations(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for i := len(m.migrations) - 1; i >= 0 && count < steps; i-- {
+			migration := m.migrations[i]
+			if _, ok := applied[migration.Name]; !ok {
+				continue
+			}
+			if err := m.rollback(ctx, conn, migration); err != nil {
+				return err
+			}
+			count++
+		}
+		return nil
+	})
+	return count, err
+}
+
+// To applies or rolls back migrations until exactly those up to version are applied.
+// Version 0 rolls back every migration.
+func (m *Migrator) To(version int) (int, error) {
+	if version != 0 && m.find(version) == nil {
+		return 0, fmt.Errorf("unknown migration version: %d", version)
+	}
+	return m.migrateTo(version)
+}
+
+func (m *Migrator) migrateTo(version int) (int, error) {
+	count := 0
+	err := m.withLock(func(ctx context.Context, conn *sql.Conn) error {
+		// Read only once the lock is held: a replica we waited for may have applied everything
+		applied, err := appliedMigrations(ctx, conn)
+		if err != nil {
+			return fmt.Errorf("failed to check migration status: %w", err)
+		}
+
+		if err := m.checkDrift(ctx, conn, applied); err != nil {
+			return err
+		}
+
+		logger.Info("Replica %s migrating database to version %d", m.replica, version)
+
+		for _, migration := range m.migrations {
+			if migration.Version > version {
+				break
+			}
+			if _, ok := applied[migration.Name]; ok {
+				logger.Debug("Migration already applied: %s", migration.Name)
+				continue
+			}
+			if err := m.apply(ctx, conn, migration); err != nil {
+				return err
+			}
+			count++
+		}
+
+		for i := len(m.migrations) - 1; i >= 0; i-- {
+			migration := m.migrations[i]
+			if migration.Version <= version {
+				break
+			}
+			if _, ok := applied[migration.Name]; !ok {
+				continue
+			}
+			if err := m.rollback(ctx, conn, migration); err != nil {
+				return err
+			}
+			count++
+		}
+
+		if count == 0 {
+			logger.Info("Database already at version %d, nothing to migrate", version)
+		}
+		return nil
+	})
+	return count, err
+}
+
+// apply runs an up migration and records it in one transaction
+func (m *Migrator) apply(ctx context.Context, conn *sql.Conn, migration Migration) error {
+	logger.Info("Running migration: %s", migration.Name)
+	start := time.Now()
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction for %s: %w", migration.Name, err)
+	}
+
+	if _, err := tx.ExecContext(ctx, migration.UpSQL); err != nil {
+		tx.Rollback()
+		logger.ErrorWithErr("Failed to execute migration", err)
+		if strings.Contains(err.Error(), "already exists") {
+			return fmt.Errorf("failed to run migration %s: %w (if this schema was created outside the migrator, record it with \"migrate baseline\")", migration.Name, err)
+		}
+		return fmt.Errorf("failed to run migration %s: %w", migration.Name, err)
+	}
+
+	duration := time.Since(start)
+	if err := m.markApplied(ctx, tx, migration, &duration); err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to mark migration as applied: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit migration transaction: %w", err)
+	}
+
+	logger.Info("Migration completed successfully: %s (replica %s, %s)", migration.Name, m.replica, duration.Round(time.Millisecond))
+	return nil
+}
+
+// execer is satisfied by *sql.Conn and *sql.Tx
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+// markApplied records a migration; duration is nil when it was baselined rather than run
+func (m *Migrator) markApplied(ctx context.Context, e execer, migration Migration, duration *time.Duration) error {
+	var durationMs *int64
+	if duration != nil {
+		ms := duration.Milliseconds()
+		durationMs = &ms
+	}
+
+	query := `
+		INSERT INTO schema_migrations (version, applied_by, checksum, duration_ms)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (version) DO NOTHING
+	`
+	_, err := e.ExecContext(ctx, query, migration.Name, m.replica, migration.Checksum, durationMs)
+	return err
+}
+
+// checkDrift compares the checksums of applied migrations with their files. Rows applied
+// before checksums were recorded get the current checksum. A modified file is an error
+// with StrictChecksums and a warning otherwise.
+func (m *Migrator) checkDrift(ctx context.Context, conn *sql.Conn, applied map[string]appliedMigration) error {
+	var modified []string
+	for _, migration := range m.migrations {
+		record, ok := applied[migration.Name]
+		if !ok {
+			continue
+		}
+
+		if record.checksum == nil {
+			if _, err := conn.ExecContext(ctx, `UPDATE schema_migrations SET checksum = $2 WHERE version = $1`, migration.Name, migration.Checksum); err != nil {
+				return fmt.Errorf("failed to record checksum for %s: %w", migration.Name, err)
+			}
+			logger.Info("Recorded checksum for previously applied migration: %s", migration.Name)
+			continue
+		}
+
+		if *record.checksum != migration.Checksum {
+			modified = append(modified, migration.Name)
+		}
+	}
+
+	if len(modified) == 0 {
+		return nil
+	}
+
+	msg := fmt.Sprintf("applied migrations were modified after being applied: %s", strings.Join(modified, ", "))
+	if m.opts.StrictChecksums {
+		return fmt.Errorf("%s; restore the original files and add a new migration instead", msg)
+	}
+	logger.Warn("%s", msg)
+	return nil
+}
+
+// Baseline records every migration up to version as applied without running it, for
+// databases whose schema was created outside the migrator. Returns how many were recorded.
+func (m *Migrator) Baseline(version int) (int, error) {
+	if version != 0 && m.find(version) == nil {
+		return 0, fmt.Errorf("unknown migration version: %d", version)
+	}
+
+	count := 0
+	err := m.withLock(func(ctx context.Context, conn *sql.Conn) error {
+		applied, err := appliedMigrations(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for _, migration := range m.migrations {
+			if migration.Version > version {
+				break
+			}
+			if _, ok := applied[migration.Name]; ok {
+				continue
+			}
+			if err := m.markApplied(ctx, conn, migration, nil); err != nil {
+				return fmt.Errorf("failed to baseline migration %s: %w", migration.Name, err)
+			}
+			logger.Warn("Migration %s marked as applied without running it (baseline)", migration.Name)
+			count++
+		}
+		return nil
+	})
+	return count, err
+}
+
+// rollback runs a down migration and removes its record in one transaction
+func (m *Migrator) rollback(ctx context.Context, conn *sql.Conn, migration Migration) error {
+	logger.Info("Rolling back migration: %s", migration.Name)
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction for %s: %w", migration.Name, err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, migration.DownSQL); err != nil {
+		logger.ErrorWithErr("Failed to roll back migration", err)
+		return fmt.Errorf("failed to roll back migration %s: %w", migration.Name, err)
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM schema_migrations WHERE version = $1`, migration.Name); err != nil {
+		return fmt.Errorf("failed to unmark migration: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit rollback transaction: %w", err)
+	}
+
+	logger.Info("Migration rolled back successfully: %s (replica %s)", migration.Name, m.replica)
+	return nil
+}
+
+// Status lists every known migration and whether it has been applied
+func (m *Migrator) Status() ([]MigrationStatus, error) {
+	var statuses []MigrationStatus
+	err := m.withLock(func(ctx context.Context, conn *sql.Conn) error {
+		applied, err := appliedMigrations(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		statuses = make([]MigrationStatus, 0, len(m.migrations))
+		for _, migration := range m.migrations {
+			status := MigrationStatus{Migration: migration}
+			if record, ok := applied[migration.Name]; ok {
+				status.Applied = true
+				status.AppliedAt = &record.at
+				status.AppliedBy = record.by
+				status.DurationMs = record.durationMs
+				status.Modified = record.checksum != nil && *record.checksum != migration.Checksum
+			}
+			statuses = append(statuses, status)
+		}
+		return nil
+	})
+	return statuses, err
+}
+
+// Pending returns the names of embedded migrations not recorded in schema_migrations.
+// Unlike Status it does not take the migration lock, so health checks can call it while
+// another replica is migrating.
+func (m *Migrator) Pending(ctx context.Context) ([]string, error) {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	applied, err := appliedMigrations(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	var pending []string
+	for _, migration := range m.migrations {
+		if _, ok := applied[migration.Name]; !ok {
+			pending = append(pending, migration.Name)
+		}
+	}
+	return pending, nil
+}
+
+// UnknownApplied returns versions recorded in schema_migrations that have no migration file,
+// e.g. after running an older binary against a newer database
+func (m *Migrator) UnknownApplied() ([]string, error) {
+	ctx := context.Background()
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	applied, err := appliedMigrations(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	var unknown []string
+	for name := range applied {
+		known := false
+		for _, migration := range m.migrations {
+			if migration.Name == name {
+				known = true
+				break
+			}
+		}
+		if !known {
+			unknown = append(unknown, name)
+		}
+	}
+
+	sort.Strings(unknown)
+	return unknown, nil
+}
+
+// LatestVersion returns the highest embedded migration version
+func (m *Migrator) LatestVersion() int {
+	if len(m.migrations) == 0 {
+		return 0
+	}
+	return m.migrations[len(m.migrations)-1].Version
+}
+
+func (m *Migrator) find(version int) *Migration {
+	for i := range m.migrations {
+		if m.migrations[i].Version == version {
+			return &m.migrations[i]
+		}
+	}
+	return nil
+}
+
+// appliedMigrations returns the recorded versions with when and by whom they were applied
+func appliedMigrations(ctx context.Context, conn *sql.Conn) (map[string]appliedMigration, error) {
+	rows, err := conn.QueryContext(ctx, `SELECT version, applied_at, applied_by, checksum, duration_ms FROM schema_migrations`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	applied := map[string]appliedMigration{}
+	for rows.Next() {
+		var version string
+		var record appliedMigration
+		if err := rows.Scan(&version, &record.at, &record.by, &record.checksum, &record.durationMs); err != nil {
+			return nil, err
+		}
+		applied[version] = record
+	}
+
+	return applied, rows.Err()
+}
+
+// ensureMigrationsTable creates the migrations tracking table if it doesn't exist.
+// It runs under the migration lock because concurrent CREATE TABLE IF NOT EXISTS can fail.
+func ensureMigrationsTable(ctx context.Context, conn *sql.Conn) error {
+	query := `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version VARCHAR(255) PRIMARY KEY,
+			applied_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+		);
+		CREATE INDEX IF NOT EXISTS idx_schema_migrations_version ON schema_migrations(version);
+		ALTER TABLE schema_migrations ADD COLUMN IF NOT EXISTS applied_by VARCHAR(255);
+		ALTER TABLE schema_migrations ADD COLUMN IF NOT EXISTS checksum VARCHAR(64);
+		ALTER TABLE schema_migrations ADD COLUMN IF NOT EXISTS duration_ms INTEGER;
+	`
+	_, err := conn.ExecContext(ctx, query)
+	if err != nil {
+		return fmt.Errorf("failed to create migrations table: %w", err)
+	}
+	return nil
+}
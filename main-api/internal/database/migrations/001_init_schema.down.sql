@@ -0,0 +1,12 @@
+-- Migration 001 (down): Drop initial schema
+-- Created for BizGenie Product Website
+
+DROP TABLE IF EXISTS contacts;
+DROP TABLE IF EXISTS blog_posts;
+DROP TABLE IF EXISTS product_images;
+DROP TABLE IF EXISTS products;
+DROP TABLE IF EXISTS categories;
+DROP TABLE IF EXISTS users;
+
+DROP FUNCTION IF EXISTS update_updated_at_column();
+DROP EXTENSION IF EXISTS "uuid-ossp";
@@ -0,0 +1,6 @@
+-- Migration 002 (down): Remove pgvector support
+-- Created for BizGenie Product Website
+
+ALTER TABLE products DROP COLUMN IF EXISTS embedding;
+
+DROP EXTENSION IF EXISTS vector;
@@ -0,0 +1,6 @@
+-- Migration 003 (down): Remove blog categories
+-- Created for BizGenie Product Website
+
+ALTER TABLE blog_posts DROP COLUMN IF EXISTS category_id;
+
+DROP TABLE IF EXISTS blog_categories;
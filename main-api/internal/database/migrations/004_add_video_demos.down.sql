@@ -0,0 +1,4 @@
+-- Migration 004 (down): Remove video demos
+-- Created for BizGenie Product Website
+
+DROP TABLE IF EXISTS video_demos;
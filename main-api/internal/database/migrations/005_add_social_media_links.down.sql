@@ -0,0 +1,4 @@
+-- Migration 005 (down): Remove social media links
+-- Created for BizGenie Product Website
+
+DROP TABLE IF EXISTS social_media_links;
@@ -0,0 +1,5 @@
+-- Migration 006 (down): Remove search analytics
+-- Created for BizGenie Product Website
+
+DROP TABLE IF EXISTS search_clicks;
+DROP TABLE IF EXISTS search_queries;
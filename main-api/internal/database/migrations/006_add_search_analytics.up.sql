@@ -0,0 +1,29 @@
+-- Migration 006: Add search analytics support
+-- Created for BizGenie Product Website
+
+-- Table: search_queries
+-- Stores normalized search queries only, no IP address or user agent
+CREATE TABLE IF NOT EXISTS search_queries (
+    id BIGSERIAL PRIMARY KEY,
+    query VARCHAR(255) NOT NULL, -- Normalized query (lowercased, trimmed, PII masked)
+    source VARCHAR(20) NOT NULL DEFAULT 'site', -- 'site', 'products'
+    result_count INTEGER NOT NULL DEFAULT 0,
+    latency_ms INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Table: search_clicks
+CREATE TABLE IF NOT EXISTS search_clicks (
+    id BIGSERIAL PRIMARY KEY,
+    search_query_id BIGINT NOT NULL REFERENCES search_queries(id) ON DELETE CASCADE,
+    result_type VARCHAR(20) NOT NULL, -- 'product', 'blog', 'video', 'category'
+    result_id INTEGER NOT NULL,
+    position INTEGER, -- 1-based position of the clicked hit
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create indexes
+CREATE INDEX IF NOT EXISTS idx_search_queries_created_at ON search_queries(created_at);
+CREATE INDEX IF NOT EXISTS idx_search_queries_query ON search_queries(query);
+CREATE INDEX IF NOT EXISTS idx_search_clicks_search_query ON search_clicks(search_query_id);
+CREATE INDEX IF NOT EXISTS idx_search_clicks_created_at ON search_clicks(created_at);
@@ -0,0 +1,7 @@
+-- Migration 007 (down): Remove assistant support
+-- Created for BizGenie Product Website
+
+DROP TABLE IF EXISTS assistant_conversations;
+DROP TABLE IF EXISTS faqs;
+
+ALTER TABLE blog_posts DROP COLUMN IF EXISTS embedding;
@@ -0,0 +1,47 @@
+-- Migration 007: Add Q&A assistant support
+-- Created for BizGenie Product Website
+
+-- Add embedding column to blog_posts so posts can be retrieved by the assistant
+ALTER TABLE blog_posts
+ADD COLUMN IF NOT EXISTS embedding vector(1536);
+
+COMMENT ON COLUMN blog_posts.embedding IS 'Vector embedding for assistant retrieval using pgvector';
+
+-- Table: faqs
+CREATE TABLE IF NOT EXISTS faqs (
+    id SERIAL PRIMARY KEY,
+    question TEXT NOT NULL,
+    answer TEXT NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'draft', -- 'draft', 'published'
+    "order" INTEGER DEFAULT 0, -- Display order
+    embedding vector(1536),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Table: assistant_conversations
+-- One row per question asked to the assistant, kept for review in admin
+CREATE TABLE IF NOT EXISTS assistant_conversations (
+    id BIGSERIAL PRIMARY KEY,
+    client_hash VARCHAR(64) NOT NULL, -- SHA-256 of the client IP, used for quotas
+    question TEXT NOT NULL,
+    answer TEXT NOT NULL DEFAULT '',
+    sources JSONB, -- Cited sources: [{type, id, title, url, score}]
+    status VARCHAR(20) NOT NULL DEFAULT 'completed', -- 'completed', 'failed'
+    error TEXT,
+    provider VARCHAR(50) NOT NULL,
+    model VARCHAR(100) NOT NULL,
+    latency_ms INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create indexes
+CREATE INDEX IF NOT EXISTS idx_faqs_status ON faqs(status);
+CREATE INDEX IF NOT EXISTS idx_faqs_order ON faqs("order");
+CREATE INDEX IF NOT EXISTS idx_assistant_conversations_client ON assistant_conversations(client_hash, created_at);
+CREATE INDEX IF NOT EXISTS idx_assistant_conversations_created_at ON assistant_conversations(created_at);
+
+-- Create trigger for updated_at
+DROP TRIGGER IF EXISTS update_faqs_updated_at ON faqs;
+CREATE TRIGGER update_faqs_updated_at BEFORE UPDATE ON faqs
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,10 @@
+-- Migration 008 (down): Remove content chunks
+-- Created for BizGenie Product Website
+
+DROP TRIGGER IF EXISTS delete_products_chunks ON products;
+DROP TRIGGER IF EXISTS delete_blog_posts_chunks ON blog_posts;
+
+DROP FUNCTION IF EXISTS delete_product_chunks();
+DROP FUNCTION IF EXISTS delete_blog_post_chunks();
+
+DROP TABLE IF EXISTS content_chunks;
@@ -0,0 +1,48 @@
+-- Migration 008: Add content chunks for passage-level retrieval
+-- Created for BizGenie Product Website
+
+-- Table: content_chunks
+-- Cleaned, section-aware passages of products and blog posts, each with its own embedding
+CREATE TABLE IF NOT EXISTS content_chunks (
+    id BIGSERIAL PRIMARY KEY,
+    source_type VARCHAR(20) NOT NULL, -- 'product', 'blog'
+    source_id INTEGER NOT NULL,
+    chunk_index INTEGER NOT NULL, -- Position of the chunk within the source
+    section VARCHAR(255), -- Heading the chunk belongs to, if any
+    content TEXT NOT NULL,
+    start_offset INTEGER NOT NULL, -- Character offsets into the cleaned source text
+    end_offset INTEGER NOT NULL,
+    content_hash VARCHAR(64) NOT NULL, -- SHA-256 of content, used to reuse embeddings of unchanged chunks
+    embedding vector(1536),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (source_type, source_id, chunk_index)
+);
+
+-- Create indexes
+CREATE INDEX IF NOT EXISTS idx_content_chunks_source ON content_chunks(source_type, source_id);
+CREATE INDEX IF NOT EXISTS idx_content_chunks_hash ON content_chunks(content_hash);
+
+-- Remove chunks when their source is deleted (source_id cannot carry a foreign key)
+CREATE OR REPLACE FUNCTION delete_product_chunks()
+RETURNS TRIGGER AS $$
+BEGIN
+    DELETE FROM content_chunks WHERE source_type = 'product' AND source_id = OLD.id;
+    RETURN OLD;
+END;
+$$ language 'plpgsql';
+
+CREATE OR REPLACE FUNCTION delete_blog_post_chunks()
+RETURNS TRIGGER AS $$
+BEGIN
+    DELETE FROM content_chunks WHERE source_type = 'blog' AND source_id = OLD.id;
+    RETURN OLD;
+END;
+$$ language 'plpgsql';
+
+DROP TRIGGER IF EXISTS delete_products_chunks ON products;
+CREATE TRIGGER delete_products_chunks AFTER DELETE ON products
+    FOR EACH ROW EXECUTE FUNCTION delete_product_chunks();
+
+DROP TRIGGER IF EXISTS delete_blog_posts_chunks ON blog_posts;
+CREATE TRIGGER delete_blog_posts_chunks AFTER DELETE ON blog_posts
+    FOR EACH ROW EXECUTE FUNCTION delete_blog_post_chunks();
@@ -0,0 +1,5 @@
+-- Migration 009 (down): Remove GIN indexes for product attribute filtering
+-- Created for BizGenie Product Website
+
+DROP INDEX IF EXISTS idx_products_specifications;
+DROP INDEX IF EXISTS idx_products_features;
@@ -0,0 +1,7 @@
+-- Migration 009: Add GIN indexes for product attribute filtering
+-- Created for BizGenie Product Website
+
+-- jsonb_path_ops indexes support the @> containment queries used by the
+-- ?spec.<key>=<value> and ?feature=<value> product filters
+CREATE INDEX IF NOT EXISTS idx_products_specifications ON products USING GIN (specifications jsonb_path_ops);
+CREATE INDEX IF NOT EXISTS idx_products_features ON products USING GIN (features jsonb_path_ops);
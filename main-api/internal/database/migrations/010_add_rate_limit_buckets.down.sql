@@ -0,0 +1,4 @@
+-- Migration 010 (down): Remove rate limit buckets
+-- Created for BizGenie Product Website
+
+DROP TABLE IF EXISTS rate_limit_buckets;
@@ -0,0 +1,14 @@
+-- Migration 010: Add rate limit buckets
+-- Created for BizGenie Product Website
+
+-- Token buckets of the rate limiter, shared by every replica. The table is unlogged: the
+-- buckets refill within minutes, so losing them on a crash is cheaper than WAL traffic on
+-- every throttled request. key is a hash of the route and the client.
+CREATE UNLOGGED TABLE IF NOT EXISTS rate_limit_buckets (
+    key VARCHAR(64) PRIMARY KEY,
+    tokens DOUBLE PRECISION NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- Idle buckets are full again and are deleted periodically
+CREATE INDEX IF NOT EXISTS idx_rate_limit_buckets_updated_at ON rate_limit_buckets(updated_at);
@@ -0,0 +1,4 @@
+-- Migration 011 (down): Remove the client address of contact requests
+-- Created for BizGenie Product Website
+
+ALTER TABLE contacts DROP COLUMN IF EXISTS client_ip;
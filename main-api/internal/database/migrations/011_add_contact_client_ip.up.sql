@@ -0,0 +1,5 @@
+-- Migration 011: Record the client address of contact requests
+-- Created for BizGenie Product Website
+
+-- Address of the client behind the trusted proxies, NULL for contacts created earlier
+ALTER TABLE contacts ADD COLUMN IF NOT EXISTS client_ip INET;
@@ -0,0 +1,4 @@
+-- Migration 012 (down): Remove cache versions
+-- Created for BizGenie Product Website
+
+DROP TABLE IF EXISTS cache_versions;
@@ -0,0 +1,12 @@
+-- Migration 012: Add cache versions
+-- Created for BizGenie Product Website
+
+-- Version of each in-memory cache of the API replicas. A replica that changes the cached
+-- data bumps the version, and every replica drops its copy when it sees a newer one.
+CREATE TABLE IF NOT EXISTS cache_versions (
+    name VARCHAR(50) PRIMARY KEY,
+    version BIGINT NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+INSERT INTO cache_versions (name) VALUES ('recommendations') ON CONFLICT (name) DO NOTHING;
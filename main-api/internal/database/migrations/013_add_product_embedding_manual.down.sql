@@ -0,0 +1,4 @@
+-- Migration 013 (down): Remove the marker of admin-supplied product embeddings
+-- Created for BizGenie Product Website
+
+ALTER TABLE products DROP COLUMN IF EXISTS embedding_manual;
@@ -0,0 +1,7 @@
+-- Migration 013: Mark product embeddings supplied by admins
+-- Created for BizGenie Product Website
+
+-- TRUE when the embedding was set through POST /api/admin/products/:id/embedding. The
+-- assistant only recomputes embeddings it generated itself, so these are never replaced.
+-- Embeddings stored before this migration cannot be told apart and count as generated.
+ALTER TABLE products ADD COLUMN IF NOT EXISTS embedding_manual BOOLEAN NOT NULL DEFAULT FALSE;
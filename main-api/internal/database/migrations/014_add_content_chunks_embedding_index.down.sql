@@ -0,0 +1,4 @@
+-- Migration 014 (down): Remove the ANN index on content chunk embeddings
+-- Created for BizGenie Product Website
+
+DROP INDEX IF EXISTS idx_content_chunks_embedding;
@@ -0,0 +1,8 @@
+-- Migration 014: Add an ANN index on content chunk embeddings
+-- Created for BizGenie Product Website
+
+-- Passage retrieval orders every chunk by distance, so it needs an index once the
+-- catalogue grows. HNSW can be built on an empty table; POST /api/admin/vector-index/rebuild
+-- with "table": "content_chunks" resizes it to the current number of chunks.
+CREATE INDEX IF NOT EXISTS idx_content_chunks_embedding ON content_chunks
+    USING hnsw (embedding vector_cosine_ops) WITH (m = 16, ef_construction = 64);
@@ -0,0 +1,7 @@
+-- Migration 015 (down): Restore the blog post embedding column
+-- Created for BizGenie Product Website
+
+ALTER TABLE blog_posts
+ADD COLUMN IF NOT EXISTS embedding vector(1536);
+
+COMMENT ON COLUMN blog_posts.embedding IS 'Vector embedding for assistant retrieval using pgvector';
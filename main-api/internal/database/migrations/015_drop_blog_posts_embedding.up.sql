@@ -0,0 +1,6 @@
+-- Migration 015: Drop the unused blog post embedding
+-- Created for BizGenie Product Website
+
+-- Blog posts are retrieved through their content chunks, so the per-post vector added
+-- in migration 007 was never written or read
+ALTER TABLE blog_posts DROP COLUMN IF EXISTS embedding;
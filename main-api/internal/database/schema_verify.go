@@ -0,0 +1,172 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// verifySchemaName is the scratch schema the applied migrations are replayed into.
+// It only exists inside a transaction that is always rolled back.
+const verifySchemaName = "migrate_verify"
+
+// SchemaDiff lists the differences between the live public schema and the schema the
+// applied migrations produce
+type SchemaDiff struct {
+	Missing    []string // Expected objects absent from the live schema
+	Changed    []string // Objects whose definition differs
+	Unexpected []string // Live objects no migration creates, e.g. indexes built at runtime
+	Modified   []string // Applied migrations whose file changed since
+}
+
+// HasErrors reports whether the live schema is missing or differs from expected objects.
+// Unexpected objects alone are not errors.
+func (d *SchemaDiff) HasErrors() bool {
+	return len(d.Missing) > 0 || len(d.Changed) > 0 || len(d.Modified) > 0
+}
+
+// Verify replays the applied migrations into a scratch schema and compares its tables,
+// columns, constraints, indexes and triggers with the live public schema
+func (m *Migrator) Verify() (*SchemaDiff, error) {
+	diff := &SchemaDiff{}
+
+	err := m.withLock(func(ctx context.Context, conn *sql.Conn) error {
+		applied, err := appliedMigrations(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		tx, err := conn.BeginTx(ctx, nil)
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		actual, err := schemaSnapshot(ctx, tx, "public")
+		if err != nil {
+			return fmt.Errorf("failed to read live schema: %w", err)
+		}
+		// The tracking table is created by the runner, not by a migration
+		for key := range actual {
+			if strings.Contains(key, "schema_migrations") {
+				delete(actual, key)
+			}
+		}
+
+		if _, err := tx.ExecContext(ctx, "CREATE SCHEMA "+verifySchemaName); err != nil {
+			return err
+		}
+		// Extensions stay in public; unqualified names in migrations resolve to the scratch schema first
+		if _, err := tx.ExecContext(ctx, fmt.Sprintf("SET LOCAL search_path TO %s, public", verifySchemaName)); err != nil {
+			return err
+		}
+
+		for _, migration := range m.migrations {
+			record, ok := applied[migration.Name]
+			if !ok {
+				continue
+			}
+			if record.checksum != nil && *record.checksum != migration.Checksum {
+				diff.Modified = append(diff.Modified, migration.Name)
+			}
+			if _, err := tx.ExecContext(ctx, migration.UpSQL); err != nil {
+				return fmt.Errorf("failed to replay migration %s: %w", migration.Name, err)
+			}
+		}
+
+		expected, err := schemaSnapshot(ctx, tx, verifySchemaName)
+		if err != nil {
+			return fmt.Errorf("failed to read expected schema: %w", err)
+		}
+
+		for key, def := range expected {
+			actualDef, ok := actual[key]
+			switch {
+			case !ok:
+				diff.Missing = append(diff.Missing, key)
+			case actualDef != def:
+				diff.Changed = append(diff.Changed, fmt.Sprintf("%s: expected %q, found %q", key, def, actualDef))
+			}
+		}
+		for key := range actual {
+			if _, ok := expected[key]; !ok {
+				diff.Unexpected = append(diff.Unexpected, key)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Strings(diff.Missing)
+	sort.Strings(diff.Changed)
+	sort.Strings(diff.Unexpected)
+	return diff, nil
+}
+
+// schemaSnapshot describes the objects of one schema as "kind name" -> definition, with
+// the schema name stripped so snapshots of different schemas can be compared.
+// Objects owned by extensions are left out.
+func schemaSnapshot(ctx context.Context, tx *sql.Tx, schema string) (map[string]string, error) {
+	queries := []string{
+		// Columns with their type and nullability
+		`SELECT 'column ' || c.relname || '.' || a.attname,
+		        format_type(a.atttypid, a.atttypmod) || CASE WHEN a.attnotnull THEN ' NOT NULL' ELSE '' END
+		 FROM pg_attribute a
+		 JOIN pg_class c ON c.oid = a.attrelid
+		 JOIN pg_namespace n ON n.oid = c.relnamespace
+		 WHERE n.nspname = $1 AND c.relkind = 'r' AND a.attnum > 0 AND NOT a.attisdropped`,
+		// Primary keys, unique, foreign key and check constraints
+		`SELECT 'constraint ' || c.relname || '.' || con.conname, pg_get_constraintdef(con.oid)
+		 FROM pg_constraint con
+		 JOIN pg_class c ON c.oid = con.conrelid
+		 JOIN pg_namespace n ON n.oid = c.relnamespace
+		 WHERE n.nspname = $1`,
+		// Indexes not backing a constraint
+		`SELECT 'index ' || i.relname, pg_get_indexdef(i.oid)
+		 FROM pg_index ix
+		 JOIN pg_class i ON i.oid = ix.indexrelid
+		 JOIN pg_namespace n ON n.oid = i.relnamespace
+		 WHERE n.nspname = $1
+		   AND NOT EXISTS (SELECT 1 FROM pg_constraint con WHERE con.conindid = i.oid)`,
+		// User triggers
+		`SELECT 'trigger ' || c.relname || '.' || t.tgname, pg_get_triggerdef(t.oid)
+		 FROM pg_trigger t
+		 JOIN pg_class c ON c.oid = t.tgrelid
+		 JOIN pg_namespace n ON n.oid = c.relnamespace
+		 WHERE n.nspname = $1 AND NOT t.tgisinternal`,
+		// Functions, compared by signature only
+		`SELECT 'function ' || p.proname || '(' || pg_get_function_identity_arguments(p.oid) || ')', ''
+		 FROM pg_proc p
+		 JOIN pg_namespace n ON n.oid = p.pronamespace
+		 WHERE n.nspname = $1
+		   AND NOT EXISTS (SELECT 1 FROM pg_depend d WHERE d.objid = p.oid AND d.deptype = 'e')`,
+	}
+
+	snapshot := map[string]string{}
+	for _, query := range queries {
+		rows, err := tx.QueryContext(ctx, query, schema)
+		if err != nil {
+			return nil, err
+		}
+
+		for rows.Next() {
+			var key, def string
+			if err := rows.Scan(&key, &def); err != nil {
+				rows.Close()
+				return nil, err
+			}
+			snapshot[key] = strings.ReplaceAll(def, schema+".", "")
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+	}
+
+	return snapshot, nil
+}
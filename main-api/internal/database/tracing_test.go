@@ -0,0 +1,105 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"testing"
+
+	"bizgenie-api/internal/tracing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestSanitizeStatement(t *testing.T) {
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{"SELECT * FROM products WHERE id = $1", "SELECT * FROM products WHERE id = $1"},
+		{"SELECT id FROM users WHERE name = 'O''Brien' AND age > 30", "SELECT id FROM users WHERE name = ? AND age > ?"},
+		{"\n\t\tSELECT 1\n\t\tFROM t\n\t", "SELECT ? FROM t"},
+		{"SELECT * FROM t LIMIT 10 OFFSET 2.5", "SELECT * FROM t LIMIT ? OFFSET ?"},
+		{"SELECT col1, t2.x FROM idx_3 WHERE $12 > 0", "SELECT col1, t2.x FROM idx_3 WHERE $12 > ?"},
+		{"SELECT pg_advisory_lock(727001)", "SELECT pg_advisory_lock(?)"},
+		{"UPDATE t SET x = -5", "UPDATE t SET x = -?"},
+		{"SELECT 'Máy lọc nước', tên FROM sản_phẩm1", "SELECT ?, tên FROM sản_phẩm1"},
+		{"WHERE a = 'unterminated", "WHERE a = ?"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := SanitizeStatement(tt.query); got != tt.want {
+			t.Errorf("SanitizeStatement(%q) = %q, want %q", tt.query, got, tt.want)
+		}
+	}
+}
+
+// dsnConnector opens connections of a registered driver, standing in for the pq connector
+type dsnConnector struct {
+	dsn    string
+	driver driver.Driver
+}
+
+func (c dsnConnector) Connect(context.Context) (driver.Conn, error) { return c.driver.Open(c.dsn) }
+func (c dsnConnector) Driver() driver.Driver                        { return c.driver }
+
+func TestTracedQuery(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := tracing.Install(sdktrace.WithSyncer(exporter))
+	t.Cleanup(func() { provider.Shutdown(context.Background()) })
+
+	mockDB, mock, err := sqlmock.NewWithDSN("traced_query")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mockDB.Close()
+	db := sql.OpenDB(tracedConnector{Connector: dsnConnector{dsn: "traced_query", driver: mockDB.Driver()}})
+	defer db.Close()
+
+	query := `SELECT name FROM products WHERE status = 'published' AND id = $1`
+	mock.ExpectQuery(`SELECT name FROM products`).WithArgs(7).WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow("Máy lọc"))
+	mock.ExpectQuery(`SELECT name FROM products`).WithArgs(8).WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow("Lõi RO"))
+
+	ctx, parent := tracing.Tracer().Start(context.Background(), "request")
+	var name string
+	if err := db.QueryRowContext(ctx, query, 7).Scan(&name); err != nil {
+		t.Fatal(err)
+	}
+	parent.End()
+	// Without a span in the context the query is not traced
+	if err := db.QueryRowContext(context.Background(), query, 8).Scan(&name); err != nil {
+		t.Fatal(err)
+	}
+
+	spans := exporter.GetSpans()
+	if len(spans) != 2 {
+		t.Fatalf("got %d spans, want the query span and its parent", len(spans))
+	}
+	span := spans[0]
+	if span.Name != "SELECT" || span.SpanKind != trace.SpanKindClient {
+		t.Errorf("span = %q (kind %v), want a client span named SELECT", span.Name, span.SpanKind)
+	}
+	if span.Parent.SpanID() != parent.SpanContext().SpanID() {
+		t.Errorf("query span parent = %s, want %s", span.Parent.SpanID(), parent.SpanContext().SpanID())
+	}
+	want := attribute.String("db.query.text", "SELECT name FROM products WHERE status = ? AND id = $1")
+	found := false
+	for _, attr := range span.Attributes {
+		if attr.Key == want.Key {
+			found = true
+			if attr.Value.AsString() != want.Value.AsString() {
+				t.Errorf("%s = %q, want %q", attr.Key, attr.Value.AsString(), want.Value.AsString())
+			}
+		}
+	}
+	if !found {
+		t.Errorf("span has no %s attribute", want.Key)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
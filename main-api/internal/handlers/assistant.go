@@ -0,0 +1,169 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"bizgenie-api/internal/apperror"
+	"bizgenie-api/internal/clientip"
+	"bizgenie-api/internal/logger"
+	"bizgenie-api/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+const maxAssistantQuestionLength = 500
+
+// AskAssistant answers a question from our own content, streamed as server-sent events:
+// "sources" with the cited documents, "delta" for each piece of the answer, then "done" or "error"
+func (h *Handlers) AskAssistant(c *gin.Context) {
+	var req struct {
+		Question string `json:"question" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(bindError(err))
+		return
+	}
+
+	question := strings.TrimSpace(req.Question)
+	if question == "" {
+		c.Error(apperror.Invalid(apperror.FieldError{Field: "question", Code: "required"}))
+		return
+	}
+	if utf8.RuneCountInString(question) > maxAssistantQuestionLength {
+		c.Error(apperror.Invalid(apperror.FieldError{Field: "question", Code: "too_long", Param: strconv.Itoa(maxAssistantQuestionLength)}))
+		return
+	}
+
+	conv, err := h.assistantService.Reserve(c.Request.Context(), clientip.FromContext(c.Request.Context()), question)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no") // Disable nginx response buffering
+	c.Status(http.StatusOK)
+
+	send := func(event string, data interface{}) error {
+		c.SSEvent(event, data)
+		c.Writer.Flush()
+		return c.Request.Context().Err()
+	}
+
+	onSources := func(sources []models.AssistantSource) error {
+		return send("sources", sources)
+	}
+	onDelta := func(text string) error {
+		return send("delta", gin.H{"text": text})
+	}
+
+	if err := h.assistantService.Ask(c.Request.Context(), conv, onSources, onDelta); err != nil {
+		logger.ErrorWithErrContext(c.Request.Context(), "Assistant failed to answer", err)
+		send("error", gin.H{"error": "The assistant could not answer this question"})
+		return
+	}
+
+	send("done", gin.H{"conversation_id": conv.ID, "answer": conv.Answer, "sources": conv.Sources})
+}
+
+// GetAssistantConversations returns logged assistant conversations for review (admin only)
+func (h *Handlers) GetAssistantConversations(c *gin.Context) {
+	status := c.Query("status")
+
+	limit := 50
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 {
+			limit = l
+		}
+	}
+
+	offset := 0
+	if offsetStr := c.Query("offset"); offsetStr != "" {
+		if o, err := strconv.Atoi(offsetStr); err == nil && o >= 0 {
+			offset = o
+		}
+	}
+
+	conversations, err := h.assistantService.GetConversations(c.Request.Context(), status, limit, offset)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": conversations})
+}
+
+// GetAssistantConversationByID returns a single logged assistant conversation (admin only)
+func (h *Handlers) GetAssistantConversationByID(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.Error(invalidID())
+		return
+	}
+
+	conv, err := h.assistantService.GetConversationByID(c.Request.Context(), id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": conv})
+}
+
+// ReindexAssistantContent computes embeddings for products and FAQs and rebuilds the
+// content chunks of products and blog posts (admin only). Only rows without an embedding
+// are embedded and unchanged chunks keep theirs unless ?all=true.
+func (h *Handlers) ReindexAssistantContent(c *gin.Context) {
+	all := c.Query("all") == "true"
+
+	counts, err := h.assistantService.EmbedMissing(c.Request.Context(), all)
+	if err != nil {
+		c.Error(fmt.Errorf("embedding stopped after %v: %w", counts, err))
+		return
+	}
+
+	chunks, err := h.chunkService.SyncAll(c.Request.Context(), all)
+	if err != nil {
+		c.Error(fmt.Errorf("rebuilding chunks stopped after embedding %v and chunking %v: %w", counts, chunks, err))
+		return
+	}
+
+	logger.InfoContext(c.Request.Context(), "Assistant content reindexed: embedded=%v chunks=%v", counts, chunks)
+
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{"embedded": counts, "chunks": chunks}})
+}
+
+// refreshEmbeddingAsync recomputes the embedding of a content item in the background
+// so admin writes do not wait on the embedding provider. The work is not canceled with
+// the request but stays part of its trace.
+func (h *Handlers) refreshEmbeddingAsync(ctx context.Context, kind string, id int) {
+	if !h.assistantService.Enabled() {
+		return
+	}
+	ctx = context.WithoutCancel(ctx)
+	h.tasks.Go(func() {
+		if err := h.assistantService.RefreshEmbedding(ctx, kind, id); err != nil {
+			logger.WarnContext(ctx, "Failed to refresh %s embedding for ID=%d: %v", kind, id, err)
+		}
+	})
+}
+
+// syncChunksAsync rebuilds the content chunks of a product or blog post in the background
+func (h *Handlers) syncChunksAsync(ctx context.Context, sourceType string, id int) {
+	if !h.chunkService.Enabled() {
+		return
+	}
+	ctx = context.WithoutCancel(ctx)
+	h.tasks.Go(func() {
+		if _, err := h.chunkService.Sync(ctx, sourceType, id, false); err != nil {
+			logger.WarnContext(ctx, "Failed to sync %s chunks for ID=%d: %v", sourceType, id, err)
+		}
+	})
+}
@@ -1,15 +1,17 @@
 package handlers
 
 import (
+	"fmt"
 	"net/http"
 
+	"bizgenie-api/internal/apperror"
 	"bizgenie-api/internal/logger"
 
 	"github.com/gin-gonic/gin"
 )
 
 func (h *Handlers) Login(c *gin.Context) {
-	logger.Debug("Login request received")
+	logger.DebugContext(c.Request.Context(), "Login request received")
 	
 	var req struct {
 		Username string `json:"username" binding:"required"`
@@ -17,41 +19,44 @@ func (h *Handlers) Login(c *gin.Context) {
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
-		logger.Warn("Invalid JSON in Login request: %v", err)
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		logger.WarnContext(c.Request.Context(), "Invalid JSON in Login request: %v", err)
+		c.Error(bindError(err))
 		return
 	}
 
-	logger.Debug("Login attempt for username: %s", req.Username)
+	logger.DebugContext(c.Request.Context(), "Login attempt for username: %s", req.Username)
 
 	// Get user by username
-	user, err := h.userService.GetUserByUsername(req.Username)
+	user, err := h.userService.GetUserByUsername(c.Request.Context(), req.Username)
 	if err != nil {
-		logger.Warn("Login failed: user not found - username: %s", req.Username)
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid credentials"})
+		if apperror.KindOf(err) != apperror.KindNotFound {
+			c.Error(err)
+			return
+		}
+		logger.WarnContext(c.Request.Context(), "Login failed: user not found - username: %s", req.Username)
+		c.Error(apperror.Unauthorized("invalid_credentials"))
 		return
 	}
 
-	logger.Debug("User found, checking password for user_id: %d", user.ID)
+	logger.DebugContext(c.Request.Context(), "User found, checking password for user_id: %d", user.ID)
 
 	// Check password
 	if !h.authService.CheckPassword(req.Password, user.PasswordHash) {
-		logger.Warn("Login failed: invalid password - username: %s, user_id: %d", req.Username, user.ID)
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid credentials"})
+		logger.WarnContext(c.Request.Context(), "Login failed: invalid password - username: %s, user_id: %d", req.Username, user.ID)
+		c.Error(apperror.Unauthorized("invalid_credentials"))
 		return
 	}
 
-	logger.Debug("Password verified, generating token for user_id: %d", user.ID)
+	logger.DebugContext(c.Request.Context(), "Password verified, generating token for user_id: %d", user.ID)
 
 	// Generate token
 	token, err := h.authService.GenerateToken(user)
 	if err != nil {
-		logger.ErrorWithErr("Failed to generate token for user", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate token"})
+		c.Error(fmt.Errorf("generating token: %w", err))
 		return
 	}
 
-	logger.Info("User logged in successfully - username: %s, user_id: %d", user.Username, user.ID)
+	logger.InfoContext(c.Request.Context(), "User logged in successfully - username: %s, user_id: %d", user.Username, user.ID)
 
 	c.JSON(http.StatusOK, gin.H{
 		"token": token,
@@ -65,41 +70,44 @@ func (h *Handlers) Login(c *gin.Context) {
 }
 
 func (h *Handlers) RefreshToken(c *gin.Context) {
-	logger.Debug("Refresh token request received")
+	logger.DebugContext(c.Request.Context(), "Refresh token request received")
 	
 	// Get user from context (should be set by middleware)
 	userID, exists := c.Get("user_id")
 	if !exists {
-		logger.Warn("Refresh token failed: user_id not found in context")
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		logger.WarnContext(c.Request.Context(), "Refresh token failed: user_id not found in context")
+		c.Error(apperror.Unauthorized("invalid_token"))
 		return
 	}
 
 	// Get user by ID
 	uid, ok := userID.(float64)
 	if !ok {
-		logger.Warn("Refresh token failed: invalid user_id type in context")
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid user ID"})
+		logger.WarnContext(c.Request.Context(), "Refresh token failed: invalid user_id type in context")
+		c.Error(apperror.Unauthorized("invalid_token"))
 		return
 	}
 
-	logger.Debug("Refreshing token for user_id: %d", int(uid))
-	user, err := h.userService.GetUserByID(int(uid))
+	logger.DebugContext(c.Request.Context(), "Refreshing token for user_id: %d", int(uid))
+	user, err := h.userService.GetUserByID(c.Request.Context(), int(uid))
 	if err != nil {
-		logger.ErrorWithErr("Refresh token failed: user not found", err)
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		if apperror.KindOf(err) != apperror.KindNotFound {
+			c.Error(err)
+			return
+		}
+		logger.WarnContext(c.Request.Context(), "Refresh token failed: user not found - user_id: %d", int(uid))
+		c.Error(apperror.Unauthorized("user_not_found").Wrap(err))
 		return
 	}
 
 	// Generate new token
 	token, err := h.authService.GenerateToken(user)
 	if err != nil {
-		logger.ErrorWithErr("Failed to generate refresh token", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate token"})
+		c.Error(fmt.Errorf("generating refresh token: %w", err))
 		return
 	}
 
-	logger.Info("Token refreshed successfully - user_id: %d", user.ID)
+	logger.InfoContext(c.Request.Context(), "Token refreshed successfully - user_id: %d", user.ID)
 
 	c.JSON(http.StatusOK, gin.H{
 		"token": token,
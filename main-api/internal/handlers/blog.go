@@ -29,9 +29,9 @@ func (h *Handlers) GetBlogPosts(c *gin.Context) {
 		}
 	}
 
-	posts, err := h.blogService.GetBlogPosts(status, limit, offset)
+	posts, err := h.blogService.GetBlogPosts(c.Request.Context(), status, limit, offset)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.Error(err)
 		return
 	}
 
@@ -41,17 +41,13 @@ func (h *Handlers) GetBlogPosts(c *gin.Context) {
 func (h *Handlers) GetBlogPostByID(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid blog post ID"})
+		c.Error(invalidID())
 		return
 	}
 
-	post, err := h.blogService.GetBlogPostByID(id)
+	post, err := h.blogService.GetBlogPostByID(c.Request.Context(), id)
 	if err != nil {
-		if err.Error() == "sql: no rows in result set" {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Blog post not found"})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.Error(err)
 		return
 	}
 
@@ -61,13 +57,9 @@ func (h *Handlers) GetBlogPostByID(c *gin.Context) {
 func (h *Handlers) GetBlogPostBySlug(c *gin.Context) {
 	slug := c.Param("slug")
 
-	post, err := h.blogService.GetBlogPostBySlug(slug)
+	post, err := h.blogService.GetBlogPostBySlug(c.Request.Context(), slug)
 	if err != nil {
-		if err.Error() == "sql: no rows in result set" {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Blog post not found"})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.Error(err)
 		return
 	}
 
@@ -77,7 +69,7 @@ func (h *Handlers) GetBlogPostBySlug(c *gin.Context) {
 func (h *Handlers) CreateBlogPost(c *gin.Context) {
 	var post models.BlogPost
 	if err := c.ShouldBindJSON(&post); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.Error(bindError(err))
 		return
 	}
 
@@ -89,50 +81,82 @@ func (h *Handlers) CreateBlogPost(c *gin.Context) {
 		}
 	}
 
-	if err := h.blogService.CreateBlogPost(&post); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.blogService.CreateBlogPost(c.Request.Context(), &post); err != nil {
+		c.Error(err)
 		return
 	}
 
+	h.recommendations.Invalidate(c.Request.Context())
+	h.syncChunksAsync(c.Request.Context(), "blog", post.ID)
+
 	c.JSON(http.StatusCreated, gin.H{"data": post})
 }
 
 func (h *Handlers) UpdateBlogPost(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid blog post ID"})
+		c.Error(invalidID())
 		return
 	}
 
 	var post models.BlogPost
 	if err := c.ShouldBindJSON(&post); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.Error(bindError(err))
 		return
 	}
 
-	if err := h.blogService.UpdateBlogPost(id, &post); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.blogService.UpdateBlogPost(c.Request.Context(), id, &post); err != nil {
+		c.Error(err)
 		return
 	}
 
+	h.recommendations.Invalidate(c.Request.Context())
+	h.syncChunksAsync(c.Request.Context(), "blog", id)
+
 	c.JSON(http.StatusOK, gin.H{"data": post})
 }
 
 func (h *Handlers) DeleteBlogPost(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid blog post ID"})
+		c.Error(invalidID())
 		return
 	}
 
-	if err := h.blogService.DeleteBlogPost(id); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.blogService.DeleteBlogPost(c.Request.Context(), id); err != nil {
+		c.Error(err)
 		return
 	}
 
+	h.recommendations.Invalidate(c.Request.Context())
+
 	c.JSON(http.StatusOK, gin.H{"message": "Blog post deleted successfully"})
 }
 
+// GetRelatedBlogPosts returns similar blog posts and relevant products for a blog post
+func (h *Handlers) GetRelatedBlogPosts(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Error(invalidID())
+		return
+	}
+
+	limit := 4
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 && l <= 20 {
+			limit = l
+		}
+	}
+
+	related, err := h.recommendations.GetRelatedForBlogPost(c.Request.Context(), id, limit)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": related})
+}
+
 // GetAdminBlogPosts returns all blog posts including draft for admin panel
 func (h *Handlers) GetAdminBlogPosts(c *gin.Context) {
 	status := c.Query("status") // Optional filter, if empty returns all
@@ -151,9 +175,9 @@ func (h *Handlers) GetAdminBlogPosts(c *gin.Context) {
 		}
 	}
 
-	posts, err := h.blogService.GetBlogPosts(status, limit, offset)
+	posts, err := h.blogService.GetBlogPosts(c.Request.Context(), status, limit, offset)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.Error(err)
 		return
 	}
 
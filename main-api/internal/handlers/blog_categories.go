@@ -10,9 +10,9 @@ import (
 )
 
 func (h *Handlers) GetBlogCategories(c *gin.Context) {
-	categories, err := h.blogCategoryService.GetBlogCategories()
+	categories, err := h.blogCategoryService.GetBlogCategories(c.Request.Context())
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.Error(err)
 		return
 	}
 
@@ -22,17 +22,13 @@ func (h *Handlers) GetBlogCategories(c *gin.Context) {
 func (h *Handlers) GetBlogCategoryByID(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid blog category ID"})
+		c.Error(invalidID())
 		return
 	}
 
-	category, err := h.blogCategoryService.GetBlogCategoryByID(id)
+	category, err := h.blogCategoryService.GetBlogCategoryByID(c.Request.Context(), id)
 	if err != nil {
-		if err.Error() == "sql: no rows in result set" {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Blog category not found"})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.Error(err)
 		return
 	}
 
@@ -42,13 +38,9 @@ func (h *Handlers) GetBlogCategoryByID(c *gin.Context) {
 func (h *Handlers) GetBlogCategoryBySlug(c *gin.Context) {
 	slug := c.Param("slug")
 
-	category, err := h.blogCategoryService.GetBlogCategoryBySlug(slug)
+	category, err := h.blogCategoryService.GetBlogCategoryBySlug(c.Request.Context(), slug)
 	if err != nil {
-		if err.Error() == "sql: no rows in result set" {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Blog category not found"})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.Error(err)
 		return
 	}
 
@@ -58,12 +50,12 @@ func (h *Handlers) GetBlogCategoryBySlug(c *gin.Context) {
 func (h *Handlers) CreateBlogCategory(c *gin.Context) {
 	var category models.BlogCategory
 	if err := c.ShouldBindJSON(&category); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.Error(bindError(err))
 		return
 	}
 
-	if err := h.blogCategoryService.CreateBlogCategory(&category); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.blogCategoryService.CreateBlogCategory(c.Request.Context(), &category); err != nil {
+		c.Error(err)
 		return
 	}
 
@@ -73,18 +65,18 @@ func (h *Handlers) CreateBlogCategory(c *gin.Context) {
 func (h *Handlers) UpdateBlogCategory(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid blog category ID"})
+		c.Error(invalidID())
 		return
 	}
 
 	var category models.BlogCategory
 	if err := c.ShouldBindJSON(&category); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.Error(bindError(err))
 		return
 	}
 
-	if err := h.blogCategoryService.UpdateBlogCategory(id, &category); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.blogCategoryService.UpdateBlogCategory(c.Request.Context(), id, &category); err != nil {
+		c.Error(err)
 		return
 	}
 
@@ -94,12 +86,12 @@ func (h *Handlers) UpdateBlogCategory(c *gin.Context) {
 func (h *Handlers) DeleteBlogCategory(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid blog category ID"})
+		c.Error(invalidID())
 		return
 	}
 
-	if err := h.blogCategoryService.DeleteBlogCategory(id); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.blogCategoryService.DeleteBlogCategory(c.Request.Context(), id); err != nil {
+		c.Error(err)
 		return
 	}
 
@@ -19,9 +19,9 @@ func (h *Handlers) GetCategories(c *gin.Context) {
 		}
 	}
 
-	categories, err := h.categoryService.GetCategories(parentID)
+	categories, err := h.categoryService.GetCategories(c.Request.Context(), parentID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.Error(err)
 		return
 	}
 
@@ -31,17 +31,13 @@ func (h *Handlers) GetCategories(c *gin.Context) {
 func (h *Handlers) GetCategoryByID(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid category ID"})
+		c.Error(invalidID())
 		return
 	}
 
-	category, err := h.categoryService.GetCategoryByID(id)
+	category, err := h.categoryService.GetCategoryByID(c.Request.Context(), id)
 	if err != nil {
-		if err.Error() == "sql: no rows in result set" {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Category not found"})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.Error(err)
 		return
 	}
 
@@ -51,13 +47,9 @@ func (h *Handlers) GetCategoryByID(c *gin.Context) {
 func (h *Handlers) GetCategoryBySlug(c *gin.Context) {
 	slug := c.Param("slug")
 
-	category, err := h.categoryService.GetCategoryBySlug(slug)
+	category, err := h.categoryService.GetCategoryBySlug(c.Request.Context(), slug)
 	if err != nil {
-		if err.Error() == "sql: no rows in result set" {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Category not found"})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.Error(err)
 		return
 	}
 
@@ -67,12 +59,12 @@ func (h *Handlers) GetCategoryBySlug(c *gin.Context) {
 func (h *Handlers) CreateCategory(c *gin.Context) {
 	var category models.Category
 	if err := c.ShouldBindJSON(&category); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.Error(bindError(err))
 		return
 	}
 
-	if err := h.categoryService.CreateCategory(&category); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.categoryService.CreateCategory(c.Request.Context(), &category); err != nil {
+		c.Error(err)
 		return
 	}
 
@@ -82,18 +74,18 @@ func (h *Handlers) CreateCategory(c *gin.Context) {
 func (h *Handlers) UpdateCategory(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid category ID"})
+		c.Error(invalidID())
 		return
 	}
 
 	var category models.Category
 	if err := c.ShouldBindJSON(&category); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.Error(bindError(err))
 		return
 	}
 
-	if err := h.categoryService.UpdateCategory(id, &category); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.categoryService.UpdateCategory(c.Request.Context(), id, &category); err != nil {
+		c.Error(err)
 		return
 	}
 
@@ -103,12 +95,12 @@ func (h *Handlers) UpdateCategory(c *gin.Context) {
 func (h *Handlers) DeleteCategory(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid category ID"})
+		c.Error(invalidID())
 		return
 	}
 
-	if err := h.categoryService.DeleteCategory(id); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.categoryService.DeleteCategory(c.Request.Context(), id); err != nil {
+		c.Error(err)
 		return
 	}
 
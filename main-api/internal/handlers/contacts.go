@@ -1,11 +1,13 @@
 package handlers
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"strconv"
 	"strings"
 
+	"bizgenie-api/internal/clientip"
 	"bizgenie-api/internal/logger"
 	"bizgenie-api/internal/models"
 
@@ -25,12 +27,12 @@ type CreateContactRequest struct {
 func (h *Handlers) CreateContact(c *gin.Context) {
 	var req CreateContactRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		logger.Error("Failed to bind contact request: %v", err)
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		logger.ErrorContext(c.Request.Context(), "Failed to bind contact request: %v", err)
+		c.Error(bindError(err))
 		return
 	}
 
-	logger.Info("Received contact request: Name=%s, Email=%s, Product=%s", req.Name, req.Email, req.Product)
+	logger.InfoContext(c.Request.Context(), "Received contact request: Name=%s, Email=%s, Product=%s", req.Name, req.Email, req.Product)
 
 	// Build message from needs and notes
 	var messageParts []string
@@ -54,43 +56,46 @@ func (h *Handlers) CreateContact(c *gin.Context) {
 		Message: message,
 		Status:  "new",
 	}
+	if ip := clientip.FromContext(c.Request.Context()); ip != "" {
+		contact.ClientIP = &ip
+	}
 
-	if err := h.contactService.CreateContact(&contact); err != nil {
-		logger.ErrorWithErr("Failed to create contact", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.contactService.CreateContact(c.Request.Context(), &contact); err != nil {
+		c.Error(err)
 		return
 	}
 
-	logger.Info("Contact created successfully: ID=%d, Name=%s, Email=%s", contact.ID, contact.Name, contact.Email)
+	logger.InfoContext(c.Request.Context(), "Contact created successfully: ID=%d, Name=%s, Email=%s", contact.ID, contact.Name, contact.Email)
 
 	// Get product name if product ID is provided
 	productName := ""
 	if req.Product != "" && req.Product != "all" {
 		productID, err := strconv.Atoi(req.Product)
 		if err == nil {
-			product, err := h.productService.GetProductByID(productID)
+			product, err := h.productService.GetProductByID(c.Request.Context(), productID)
 			if err == nil && product != nil {
 				productName = product.Name
-				logger.Info("Found product: ID=%d, Name=%s", productID, productName)
+				logger.InfoContext(c.Request.Context(), "Found product: ID=%d, Name=%s", productID, productName)
 			} else {
-				logger.Warn("Product not found: ID=%d, Error=%v", productID, err)
+				logger.WarnContext(c.Request.Context(), "Product not found: ID=%d, Error=%v", productID, err)
 			}
 		} else {
-			logger.Warn("Invalid product ID format: %s", req.Product)
+			logger.WarnContext(c.Request.Context(), "Invalid product ID format: %s", req.Product)
 		}
 	} else if req.Product == "all" {
 		productName = "Tất cả sản phẩm"
 	}
 
 	// Send Slack notification (non-blocking, log error if fails)
-	logger.Info("Sending Slack notification for contact ID=%d, Product=%s", contact.ID, productName)
-	go func() {
-		if err := h.slackService.SendContactNotification(&contact, productName); err != nil {
-			logger.ErrorWithErr("Failed to send Slack notification", err)
+	logger.InfoContext(c.Request.Context(), "Sending Slack notification for contact ID=%d, Product=%s", contact.ID, productName)
+	notifyCtx := context.WithoutCancel(c.Request.Context())
+	h.tasks.Go(func() {
+		if err := h.slackService.SendContactNotification(notifyCtx, &contact, productName); err != nil {
+			logger.ErrorWithErrContext(notifyCtx, "Failed to send Slack notification", err)
 		} else {
-			logger.Info("Slack notification sent successfully for contact ID=%d", contact.ID)
+			logger.InfoContext(notifyCtx, "Slack notification sent successfully for contact ID=%d", contact.ID)
 		}
-	}()
+	})
 
 	c.JSON(http.StatusCreated, gin.H{"data": contact})
 }
@@ -112,9 +117,9 @@ func (h *Handlers) GetContacts(c *gin.Context) {
 		}
 	}
 
-	contacts, err := h.contactService.GetContacts(status, limit, offset)
+	contacts, err := h.contactService.GetContacts(c.Request.Context(), status, limit, offset)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.Error(err)
 		return
 	}
 
@@ -124,17 +129,13 @@ func (h *Handlers) GetContacts(c *gin.Context) {
 func (h *Handlers) GetContactByID(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid contact ID"})
+		c.Error(invalidID())
 		return
 	}
 
-	contact, err := h.contactService.GetContactByID(id)
+	contact, err := h.contactService.GetContactByID(c.Request.Context(), id)
 	if err != nil {
-		if err.Error() == "sql: no rows in result set" {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Contact not found"})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.Error(err)
 		return
 	}
 
@@ -144,7 +145,7 @@ func (h *Handlers) GetContactByID(c *gin.Context) {
 func (h *Handlers) UpdateContactStatus(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid contact ID"})
+		c.Error(invalidID())
 		return
 	}
 
@@ -152,12 +153,12 @@ func (h *Handlers) UpdateContactStatus(c *gin.Context) {
 		Status string `json:"status"`
 	}
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.Error(bindError(err))
 		return
 	}
 
-	if err := h.contactService.UpdateContactStatus(id, req.Status); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.contactService.UpdateContactStatus(c.Request.Context(), id, req.Status); err != nil {
+		c.Error(err)
 		return
 	}
 
@@ -167,12 +168,12 @@ func (h *Handlers) UpdateContactStatus(c *gin.Context) {
 func (h *Handlers) DeleteContact(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid contact ID"})
+		c.Error(invalidID())
 		return
 	}
 
-	if err := h.contactService.DeleteContact(id); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.contactService.DeleteContact(c.Request.Context(), id); err != nil {
+		c.Error(err)
 		return
 	}
 
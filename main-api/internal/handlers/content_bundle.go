@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"bizgenie-api/internal/logger"
+	"bizgenie-api/internal/models"
+	"bizgenie-api/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ExportContent downloads a content bundle. Each section is selected with a query parameter
+// listing its keys (slugs, video URLs or platforms), comma separated, or "all";
+// without any section parameter everything is exported.
+func (h *Handlers) ExportContent(c *gin.Context) {
+	selection := map[string][]string{}
+	for _, section := range services.ContentBundleSections {
+		value, ok := c.GetQuery(section)
+		if !ok {
+			continue
+		}
+		var keys []string
+		for _, key := range strings.Split(value, ",") {
+			if key = strings.TrimSpace(key); key == "all" {
+				keys = append(keys, "*")
+			} else if key != "" {
+				keys = append(keys, key)
+			}
+		}
+		selection[section] = keys
+	}
+	if len(selection) == 0 {
+		for _, section := range services.ContentBundleSections {
+			selection[section] = []string{"*"}
+		}
+	}
+
+	bundle, err := h.contentBundleService.Export(c.Request.Context(), selection)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	// The bundle is served as-is so the downloaded file can be posted to the import endpoint
+	filename := fmt.Sprintf("bizgenie-content-%s.json", bundle.ExportedAt.Format("20060102T150405Z"))
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	c.JSON(http.StatusOK, bundle)
+}
+
+// ImportContent upserts a content bundle by slug. With ?dry_run=true nothing is written
+// and the response previews what would be created or updated.
+func (h *Handlers) ImportContent(c *gin.Context) {
+	var bundle models.ContentBundle
+	if err := c.ShouldBindJSON(&bundle); err != nil {
+		c.Error(bindError(err))
+		return
+	}
+	dryRun := c.Query("dry_run") == "true"
+
+	start := time.Now()
+	result, err := h.contentBundleService.Import(c.Request.Context(), &bundle, dryRun)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	if !dryRun {
+		// Same follow-up work as the product and blog post CRUD handlers
+		if len(result.ProductIDs) > 0 || len(result.BlogPostIDs) > 0 {
+			h.recommendations.Invalidate(c.Request.Context())
+		}
+		for _, id := range result.ProductIDs {
+			h.refreshEmbeddingAsync(c.Request.Context(), "product", id)
+			h.syncChunksAsync(c.Request.Context(), "product", id)
+		}
+		for _, id := range result.BlogPostIDs {
+			h.syncChunksAsync(c.Request.Context(), "blog", id)
+		}
+		logger.InfoContext(c.Request.Context(), "Content bundle imported in %s: %d created, %d updated, %d unchanged",
+			time.Since(start), result.Created, result.Updated, result.Unchanged)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": result})
+}
@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+
+	"bizgenie-api/internal/apperror"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// Validation errors name fields as clients send them, by their JSON name
+func init() {
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		v.RegisterTagNameFunc(func(field reflect.StructField) string {
+			name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+			switch name {
+			case "-":
+				return ""
+			case "":
+				return field.Name
+			}
+			return name
+		})
+	}
+}
+
+// bindError turns an error from binding a request body into a validation error listing
+// the fields that failed
+func bindError(err error) error {
+	var validationErrs validator.ValidationErrors
+	var typeErr *json.UnmarshalTypeError
+	var syntaxErr *json.SyntaxError
+	switch {
+	case errors.As(err, &validationErrs):
+		fields := make([]apperror.FieldError, len(validationErrs))
+		for i, fe := range validationErrs {
+			fields[i] = validationField(fe)
+		}
+		return apperror.Invalid(fields...).Wrap(err)
+	case errors.As(err, &typeErr) && typeErr.Field != "":
+		return apperror.Invalid(apperror.FieldError{Field: typeErr.Field, Code: "invalid_type", Param: jsonType(typeErr.Type)}).Wrap(err)
+	case errors.As(err, &typeErr), errors.As(err, &syntaxErr), errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
+		return apperror.Validation("invalid_json").Wrap(err)
+	}
+	return apperror.Invalid().Wrap(err)
+}
+
+// validationField maps a failed binding tag to a field error
+func validationField(fe validator.FieldError) apperror.FieldError {
+	// The namespace starts with the name of the bound struct type
+	field := fe.Namespace()
+	if _, rest, ok := strings.Cut(field, "."); ok {
+		field = rest
+	}
+
+	code := "invalid"
+	switch fe.Tag() {
+	case "required":
+		code = "required"
+	case "email":
+		code = "invalid_email"
+	case "url", "uri", "http_url":
+		code = "invalid_url"
+	case "oneof":
+		code = "one_of"
+	case "min", "gte":
+		code = "too_small"
+		if fe.Kind() == reflect.String {
+			code = "too_short"
+		}
+	case "max", "lte":
+		code = "too_large"
+		if fe.Kind() == reflect.String {
+			code = "too_long"
+		}
+	}
+	return apperror.FieldError{Field: field, Code: code, Param: fe.Param()}
+}
+
+// jsonType names the JSON type a Go type is decoded from
+func jsonType(t reflect.Type) string {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "integer"
+	case reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.Slice, reflect.Array:
+		return "array"
+	case reflect.Map, reflect.Struct:
+		return "object"
+	}
+	return t.String()
+}
+
+// invalidID is the error for an id path parameter that is not a number
+func invalidID() error {
+	return apperror.Invalid(apperror.FieldError{Field: "id", Code: "invalid_type", Param: "integer"})
+}
+
+// requiredQuery is the error for a missing query parameter
+func requiredQuery(name string) error {
+	return apperror.Invalid(apperror.FieldError{Field: name, Code: "required"})
+}
@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"bizgenie-api/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetFAQs returns published FAQs (public)
+func (h *Handlers) GetFAQs(c *gin.Context) {
+	faqs, err := h.faqService.GetFAQs(c.Request.Context(), "published")
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": faqs})
+}
+
+// GetAdminFAQs returns all FAQs including draft for admin panel
+func (h *Handlers) GetAdminFAQs(c *gin.Context) {
+	faqs, err := h.faqService.GetFAQs(c.Request.Context(), c.Query("status"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": faqs})
+}
+
+func (h *Handlers) GetFAQByID(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Error(invalidID())
+		return
+	}
+
+	faq, err := h.faqService.GetFAQByID(c.Request.Context(), id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": faq})
+}
+
+func (h *Handlers) CreateFAQ(c *gin.Context) {
+	var faq models.FAQ
+	if err := c.ShouldBindJSON(&faq); err != nil {
+		c.Error(bindError(err))
+		return
+	}
+
+	if faq.Status == "" {
+		faq.Status = "draft"
+	}
+
+	if err := h.faqService.CreateFAQ(c.Request.Context(), &faq); err != nil {
+		c.Error(err)
+		return
+	}
+
+	h.refreshEmbeddingAsync(c.Request.Context(), "faq", faq.ID)
+
+	c.JSON(http.StatusCreated, gin.H{"data": faq})
+}
+
+func (h *Handlers) UpdateFAQ(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Error(invalidID())
+		return
+	}
+
+	var faq models.FAQ
+	if err := c.ShouldBindJSON(&faq); err != nil {
+		c.Error(bindError(err))
+		return
+	}
+
+	if err := h.faqService.UpdateFAQ(c.Request.Context(), id, &faq); err != nil {
+		c.Error(err)
+		return
+	}
+
+	h.refreshEmbeddingAsync(c.Request.Context(), "faq", id)
+
+	c.JSON(http.StatusOK, gin.H{"data": faq})
+}
+
+func (h *Handlers) DeleteFAQ(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Error(invalidID())
+		return
+	}
+
+	if err := h.faqService.DeleteFAQ(c.Request.Context(), id); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "FAQ deleted successfully"})
+}
@@ -2,8 +2,11 @@ package handlers
 
 import (
 	"database/sql"
+	"sync/atomic"
 
 	"bizgenie-api/internal/config"
+	"bizgenie-api/internal/database"
+	"bizgenie-api/internal/logger"
 	"bizgenie-api/internal/services"
 )
 
@@ -17,13 +20,60 @@ type Handlers struct {
 	contactService      *services.ContactService
 	authService         *services.AuthService
 	searchService       *services.SearchService
+	searchAnalytics     *services.SearchAnalyticsService
+	vectorIndexService  *services.VectorIndexService
+	recommendations     *services.RecommendationService
+	faqService          *services.FAQService
+	chunkService        *services.ChunkService
+	assistantService    *services.AssistantService
 	userService         *services.UserService
 	slackService        *services.SlackService
 	videoDemoService    *services.VideoDemoService
 	socialMediaService  *services.SocialMediaService
+	contentBundleService *services.ContentBundleService
+	healthService        *services.HealthService
+	// Goroutines started by requests, waited for on shutdown
+	tasks *services.BackgroundTasks
+	// Set once shutdown begins so health checks take the replica out of rotation
+	shuttingDown atomic.Bool
 }
 
-func New(db *sql.DB, cfg *config.Config) *Handlers {
+// LLMProviders returns the configured chat and embedding providers. ASSISTANT_PROVIDER
+// defaults to the OpenAI-compatible provider when an API key is configured. The offline
+// fake is only used when asked for by name: its vectors are written into the content
+// tables and answer real visitors, so it must never be picked up by accident.
+// With neither set both providers are nil and the assistant is disabled.
+func LLMProviders(cfg *config.Config) (services.ChatProvider, services.EmbeddingProvider, error) {
+	provider := cfg.AssistantProvider
+	if provider == "" {
+		if cfg.AssistantAPIKey == "" {
+			return nil, nil, nil
+		}
+		provider = "openai"
+	}
+	return services.NewLLMProviders(provider, cfg.AssistantAPIBaseURL, cfg.AssistantAPIKey, cfg.AssistantChatModel, cfg.AssistantEmbeddingModel)
+}
+
+func New(db *sql.DB, cfg *config.Config, tasks *services.BackgroundTasks) (*Handlers, error) {
+	chat, embedder, err := LLMProviders(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if chat == nil {
+		logger.Warn("No assistant provider configured, the assistant is disabled: set ASSISTANT_API_KEY, or ASSISTANT_PROVIDER=fake for development")
+	}
+	assistantOpts := services.AssistantOptions{
+		TopK:        cfg.AssistantTopK,
+		HourlyQuota: cfg.AssistantHourlyQuota,
+		EfSearch:    cfg.VectorEfSearch,
+		Probes:      cfg.VectorProbes,
+	}
+	chunkService := services.NewChunkService(db, embedder)
+	migrator, err := database.NewMigrator(db, database.MigratorOptions{})
+	if err != nil {
+		logger.Warn("Failed to load migrations for health checks: %v", err)
+	}
+
 	return &Handlers{
 		db:                  db,
 		cfg:                 cfg,
@@ -33,10 +83,24 @@ func New(db *sql.DB, cfg *config.Config) *Handlers {
 		blogCategoryService:  services.NewBlogCategoryService(db),
 		contactService:      services.NewContactService(db),
 		authService:         services.NewAuthService(cfg.JWTSecret),
-		searchService:       services.NewSearchService(db),
+		searchService:       services.NewSearchService(db, cfg.VectorEfSearch, cfg.VectorProbes),
+		searchAnalytics:     services.NewSearchAnalyticsService(db),
+		vectorIndexService:  services.NewVectorIndexService(db, cfg.VectorEfSearch, cfg.VectorProbes),
+		recommendations:     services.NewRecommendationService(db),
+		faqService:          services.NewFAQService(db),
+		chunkService:        chunkService,
+		assistantService:    services.NewAssistantService(db, chat, embedder, chunkService, assistantOpts),
 		userService:         services.NewUserService(db),
 		slackService:        services.NewSlackService(cfg.SlackWebhookURL),
 		videoDemoService:    services.NewVideoDemoService(db),
 		socialMediaService:  services.NewSocialMediaService(db),
-	}
+		contentBundleService: services.NewContentBundleService(db),
+		healthService:       services.NewHealthService(db, migrator, cfg.SlackWebhookURL, cfg.HealthCheckTimeout),
+		tasks:               tasks,
+	}, nil
+}
+
+// BeginShutdown makes the health check report the replica as unavailable
+func (h *Handlers) BeginShutdown() {
+	h.shuttingDown.Store(true)
 }
@@ -0,0 +1,32 @@
+package handlers
+
+import (
+	"net/http"
+
+	"bizgenie-api/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Live reports that the process is up and serving requests. It does not check
+// dependencies, so an outage of Postgres does not get healthy replicas restarted.
+func (h *Handlers) Live(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
+
+// Ready reports whether the replica can serve traffic, with the status and latency of
+// each dependency. It returns 503 when a critical dependency fails and as soon as
+// shutdown begins, so load balancers stop routing to it before connections are drained.
+func (h *Handlers) Ready(c *gin.Context) {
+	if h.shuttingDown.Load() {
+		c.JSON(http.StatusServiceUnavailable, models.HealthReport{Status: "shutting_down"})
+		return
+	}
+
+	report := h.healthService.Ready(c.Request.Context())
+	status := http.StatusOK
+	if report.Status == "fail" {
+		status = http.StatusServiceUnavailable
+	}
+	c.JSON(status, report)
+}
@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"net/http"
+
+	"bizgenie-api/internal/apperror"
+	"bizgenie-api/internal/logger"
+	"bizgenie-api/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// logLevelNames lists the levels accepted by logger.ParseLevel
+const logLevelNames = "debug, info, warn, error"
+
+// GetLogLevels returns the default log level and the per-package overrides (admin only)
+func (h *Handlers) GetLogLevels(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"data": currentLogLevels()})
+}
+
+// UpdateLogLevels changes log levels at runtime without a restart (admin role only). An
+// empty level removes the override of a package. Changes apply to this replica only and
+// are lost on restart; LOG_LEVEL and LOG_PACKAGE_LEVELS set the levels at startup.
+func (h *Handlers) UpdateLogLevels(c *gin.Context) {
+	if c.GetString("role") != "admin" {
+		c.Error(apperror.Forbidden("admin_required"))
+		return
+	}
+
+	var req models.LogLevels
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(bindError(err))
+		return
+	}
+
+	// Validate everything before applying anything
+	var defaultLevel logger.LogLevel
+	if req.Default != "" {
+		level, err := logger.ParseLevel(req.Default)
+		if err != nil {
+			c.Error(apperror.Invalid(apperror.FieldError{Field: "default", Code: "one_of", Param: logLevelNames}).Wrap(err))
+			return
+		}
+		defaultLevel = level
+	}
+	packages := map[string]logger.LogLevel{}
+	for pkg, name := range req.Packages {
+		if name == "" {
+			continue
+		}
+		level, err := logger.ParseLevel(name)
+		if err != nil {
+			c.Error(apperror.Invalid(apperror.FieldError{Field: "packages." + pkg, Code: "one_of", Param: logLevelNames}).Wrap(err))
+			return
+		}
+		packages[pkg] = level
+	}
+
+	if req.Default != "" {
+		logger.SetLevel(defaultLevel)
+	}
+	for pkg, name := range req.Packages {
+		if name == "" {
+			logger.ResetPackageLevel(pkg)
+		} else {
+			logger.SetPackageLevel(pkg, packages[pkg])
+		}
+	}
+
+	levels := currentLogLevels()
+	logger.InfoContext(c.Request.Context(), "Log levels changed: default=%s, packages=%v", levels.Default, levels.Packages)
+	c.JSON(http.StatusOK, gin.H{"data": levels})
+}
+
+func currentLogLevels() models.LogLevels {
+	levels := models.LogLevels{Default: logger.GetLevel().String(), Packages: map[string]string{}}
+	for pkg, level := range logger.PackageLevels() {
+		levels.Packages[pkg] = level.String()
+	}
+	return levels
+}
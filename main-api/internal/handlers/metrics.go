@@ -0,0 +1,28 @@
+package handlers
+
+import (
+	"context"
+	"time"
+
+	"bizgenie-api/internal/logger"
+	"bizgenie-api/internal/metrics"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Metrics serves every metric in the Prometheus text format. Gauges that need a query,
+// like the embedding backlog, are refreshed on each scrape.
+func (h *Handlers) Metrics(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 2*time.Second)
+	defer cancel()
+	if backlog, err := h.assistantService.EmbeddingBacklog(ctx); err != nil {
+		logger.WarnContext(c.Request.Context(), "Failed to count embedding backlog for metrics: %v", err)
+	} else {
+		for kind, count := range backlog {
+			metrics.EmbeddingBacklog.Set(float64(count), kind)
+		}
+	}
+
+	c.Header("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	metrics.WriteAll(c.Writer)
+}
@@ -1,29 +1,29 @@
 package handlers
 
 import (
-	"database/sql"
 	"net/http"
 	"strconv"
+	"strings"
 
+	"bizgenie-api/internal/apperror"
 	"bizgenie-api/internal/logger"
 	"bizgenie-api/internal/models"
 
 	"github.com/gin-gonic/gin"
 )
 
+const maxProductFilterKeyLength = 100
+
+// GetProducts lists products, optionally filtered by specification values
+// (?spec.<key>=<value>, repeatable) and features (?feature=<value>, repeatable)
 func (h *Handlers) GetProducts(c *gin.Context) {
-	status := c.Query("status")
-	if status == "" {
-		status = "published"
+	filter, err := parseProductFilter(c)
+	if err != nil {
+		c.Error(err)
+		return
 	}
-
-	categoryIDStr := c.Query("category_id")
-	var categoryID *int
-	if categoryIDStr != "" {
-		id, err := strconv.Atoi(categoryIDStr)
-		if err == nil {
-			categoryID = &id
-		}
+	if filter.Status == "" {
+		filter.Status = "published"
 	}
 
 	limit := 20
@@ -40,10 +40,9 @@ func (h *Handlers) GetProducts(c *gin.Context) {
 		}
 	}
 
-	products, err := h.productService.GetProducts(status, categoryID, limit, offset)
+	products, err := h.productService.GetProducts(c.Request.Context(), filter, limit, offset)
 	if err != nil {
-		logger.ErrorWithErr("Failed to get products", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.Error(err)
 		return
 	}
 
@@ -53,19 +52,13 @@ func (h *Handlers) GetProducts(c *gin.Context) {
 func (h *Handlers) GetProductByID(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid product ID"})
+		c.Error(invalidID())
 		return
 	}
 
-	product, err := h.productService.GetProductByID(id)
+	product, err := h.productService.GetProductByID(c.Request.Context(), id)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			logger.Debug("Product not found with ID: %d", id)
-			c.JSON(http.StatusNotFound, gin.H{"error": "Product not found"})
-			return
-		}
-		logger.ErrorWithErr("Failed to get product by ID", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.Error(err)
 		return
 	}
 
@@ -75,15 +68,9 @@ func (h *Handlers) GetProductByID(c *gin.Context) {
 func (h *Handlers) GetProductBySlug(c *gin.Context) {
 	slug := c.Param("slug")
 
-	product, err := h.productService.GetProductBySlug(slug)
+	product, err := h.productService.GetProductBySlug(c.Request.Context(), slug)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			logger.Debug("Product not found with slug: %s", slug)
-			c.JSON(http.StatusNotFound, gin.H{"error": "Product not found"})
-			return
-		}
-		logger.ErrorWithErr("Failed to get product by slug", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.Error(err)
 		return
 	}
 
@@ -93,18 +80,19 @@ func (h *Handlers) GetProductBySlug(c *gin.Context) {
 func (h *Handlers) CreateProduct(c *gin.Context) {
 	var product models.Product
 	if err := c.ShouldBindJSON(&product); err != nil {
-		logger.Warn("Invalid JSON in CreateProduct request: %v", err)
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		logger.WarnContext(c.Request.Context(), "Invalid JSON in CreateProduct request: %v", err)
+		c.Error(bindError(err))
 		return
 	}
 
-	if err := h.productService.CreateProduct(&product); err != nil {
-		logger.ErrorWithErr("Failed to create product", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.productService.CreateProduct(c.Request.Context(), &product); err != nil {
+		c.Error(err)
 		return
 	}
 
-	logger.Info("Product created successfully with ID: %d", product.ID)
+	h.recommendations.Invalidate(c.Request.Context())
+	h.syncChunksAsync(c.Request.Context(), "product", product.ID)
+	logger.InfoContext(c.Request.Context(), "Product created successfully with ID: %d", product.ID)
 
 	c.JSON(http.StatusCreated, gin.H{"data": product})
 }
@@ -112,23 +100,24 @@ func (h *Handlers) CreateProduct(c *gin.Context) {
 func (h *Handlers) UpdateProduct(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid product ID"})
+		c.Error(invalidID())
 		return
 	}
 
 	var product models.Product
 	if err := c.ShouldBindJSON(&product); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.Error(bindError(err))
 		return
 	}
 
-	if err := h.productService.UpdateProduct(id, &product); err != nil {
-		logger.ErrorWithErr("Failed to update product", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.productService.UpdateProduct(c.Request.Context(), id, &product); err != nil {
+		c.Error(err)
 		return
 	}
 
-	logger.Info("Product updated successfully with ID: %d", id)
+	h.recommendations.Invalidate(c.Request.Context())
+	h.syncChunksAsync(c.Request.Context(), "product", id)
+	logger.InfoContext(c.Request.Context(), "Product updated successfully with ID: %d", id)
 
 	c.JSON(http.StatusOK, gin.H{"data": product})
 }
@@ -136,17 +125,17 @@ func (h *Handlers) UpdateProduct(c *gin.Context) {
 func (h *Handlers) DeleteProduct(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid product ID"})
+		c.Error(invalidID())
 		return
 	}
 
-	if err := h.productService.DeleteProduct(id); err != nil {
-		logger.ErrorWithErr("Failed to delete product", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.productService.DeleteProduct(c.Request.Context(), id); err != nil {
+		c.Error(err)
 		return
 	}
 
-	logger.Info("Product deleted successfully with ID: %d", id)
+	h.recommendations.Invalidate(c.Request.Context())
+	logger.InfoContext(c.Request.Context(), "Product deleted successfully with ID: %d", id)
 
 	c.JSON(http.StatusOK, gin.H{"message": "Product deleted successfully"})
 }
@@ -154,7 +143,7 @@ func (h *Handlers) DeleteProduct(c *gin.Context) {
 func (h *Handlers) UpdateProductEmbedding(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid product ID"})
+		c.Error(invalidID())
 		return
 	}
 
@@ -162,29 +151,51 @@ func (h *Handlers) UpdateProductEmbedding(c *gin.Context) {
 		Embedding []float32 `json:"embedding"`
 	}
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.Error(bindError(err))
 		return
 	}
 
-	if err := h.productService.UpdateProductEmbedding(id, req.Embedding); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.productService.UpdateProductEmbedding(c.Request.Context(), id, req.Embedding); err != nil {
+		c.Error(err)
 		return
 	}
 
+	h.recommendations.Invalidate(c.Request.Context())
+
 	c.JSON(http.StatusOK, gin.H{"message": "Embedding updated successfully"})
 }
 
+// GetRelatedProducts returns similar products and relevant blog posts for a product
+func (h *Handlers) GetRelatedProducts(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Error(invalidID())
+		return
+	}
+
+	limit := 4
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 && l <= 20 {
+			limit = l
+		}
+	}
+
+	related, err := h.recommendations.GetRelatedForProduct(c.Request.Context(), id, limit)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": related})
+}
+
 // GetAdminProducts returns all products including draft for admin panel
 func (h *Handlers) GetAdminProducts(c *gin.Context) {
-	status := c.Query("status") // Optional filter, if empty returns all
-
-	categoryIDStr := c.Query("category_id")
-	var categoryID *int
-	if categoryIDStr != "" {
-		id, err := strconv.Atoi(categoryIDStr)
-		if err == nil {
-			categoryID = &id
-		}
+	// Status is optional here, if empty returns all
+	filter, err := parseProductFilter(c)
+	if err != nil {
+		c.Error(err)
+		return
 	}
 
 	limit := 100 // Default higher limit for admin
@@ -201,12 +212,69 @@ func (h *Handlers) GetAdminProducts(c *gin.Context) {
 		}
 	}
 
-	products, err := h.productService.GetProducts(status, categoryID, limit, offset)
+	products, err := h.productService.GetProducts(c.Request.Context(), filter, limit, offset)
 	if err != nil {
-		logger.ErrorWithErr("Failed to get admin products", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.Error(err)
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"data": products})
 }
+
+// GetProductFacets returns specification values and features with product counts for
+// the current filter set, for building the product sidebar filters
+func (h *Handlers) GetProductFacets(c *gin.Context) {
+	filter, err := parseProductFilter(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	if filter.Status == "" {
+		filter.Status = "published"
+	}
+
+	facets, err := h.productService.GetProductFacets(c.Request.Context(), filter)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": facets})
+}
+
+// parseProductFilter reads status, category_id, spec.<key> and feature query parameters
+func parseProductFilter(c *gin.Context) (models.ProductFilter, error) {
+	filter := models.ProductFilter{
+		Status: c.Query("status"),
+		Specs:  map[string][]string{},
+	}
+
+	if categoryIDStr := c.Query("category_id"); categoryIDStr != "" {
+		if id, err := strconv.Atoi(categoryIDStr); err == nil {
+			filter.CategoryID = &id
+		}
+	}
+
+	for param, values := range c.Request.URL.Query() {
+		if !strings.HasPrefix(param, "spec.") {
+			continue
+		}
+		key := strings.TrimPrefix(param, "spec.")
+		if key == "" || len(key) > maxProductFilterKeyLength {
+			return filter, apperror.Invalid(apperror.FieldError{Field: param, Code: "invalid"})
+		}
+		for _, v := range values {
+			if v = strings.TrimSpace(v); v != "" {
+				filter.Specs[key] = append(filter.Specs[key], v)
+			}
+		}
+	}
+
+	for _, f := range c.QueryArray("feature") {
+		if f = strings.TrimSpace(f); f != "" {
+			filter.Features = append(filter.Features, f)
+		}
+	}
+
+	return filter, nil
+}
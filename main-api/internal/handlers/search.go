@@ -1,8 +1,15 @@
 package handlers
 
 import (
+	"context"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
+
+	"bizgenie-api/internal/apperror"
+	"bizgenie-api/internal/logger"
+	"bizgenie-api/internal/services"
 
 	"github.com/gin-gonic/gin"
 )
@@ -24,9 +31,9 @@ func (h *Handlers) SearchProducts(c *gin.Context) {
 		// Parse embedding from query string (would need proper parsing)
 		// For now, use text search as fallback
 		// threshold would be used for semantic search: threshold := 0.7
-		products, err := h.searchService.SearchProductsByText(query, limit)
+		products, err := h.searchService.SearchProductsByText(c.Request.Context(), query, limit)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			c.Error(err)
 			return
 		}
 		c.JSON(http.StatusOK, gin.H{"data": products})
@@ -35,15 +42,230 @@ func (h *Handlers) SearchProducts(c *gin.Context) {
 
 	// Use text search
 	if query == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Query parameter 'q' is required"})
+		c.Error(requiredQuery("q"))
 		return
 	}
 
-	products, err := h.searchService.SearchProductsByText(query, limit)
+	start := time.Now()
+	products, err := h.searchService.SearchProductsByText(c.Request.Context(), query, limit)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.Error(err)
+		return
+	}
+
+	response := gin.H{"data": products}
+	if searchID := h.logSearchQuery(c.Request.Context(), query, "products", len(products), time.Since(start)); searchID != nil {
+		response["search_id"] = *searchID
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// Search performs a site-wide search across products, blog posts, video demos and categories
+func (h *Handlers) Search(c *gin.Context) {
+	query := strings.TrimSpace(c.Query("q"))
+	if query == "" {
+		c.Error(requiredQuery("q"))
+		return
+	}
+
+	// type accepts a comma-separated list, e.g. ?type=product,blog
+	var types []string
+	if typeStr := c.Query("type"); typeStr != "" {
+		for _, t := range strings.Split(typeStr, ",") {
+			t = strings.TrimSpace(t)
+			if t == "" {
+				continue
+			}
+			if !isSearchType(t) {
+				c.Error(invalidSearchType())
+				return
+			}
+			types = append(types, t)
+		}
+	}
+
+	// category takes a category facet value, prefixed with the kind of category
+	category := c.Query("category")
+	if category != "" && !strings.HasPrefix(category, "product:") && !strings.HasPrefix(category, "blog:") {
+		c.Error(apperror.Invalid(apperror.FieldError{Field: "category", Code: "invalid"}))
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"data": products})
+	limit := 20
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 {
+			limit = l
+		}
+	}
+	if limit > 100 {
+		limit = 100
+	}
+
+	offset := 0
+	if offsetStr := c.Query("offset"); offsetStr != "" {
+		if o, err := strconv.Atoi(offsetStr); err == nil && o >= 0 {
+			offset = o
+		}
+	}
+
+	start := time.Now()
+	result, err := h.searchService.SiteSearch(c.Request.Context(), query, types, category, limit, offset)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	// Only the first page is a new search; later pages pass on the search_id of the first
+	// so their clicks count towards it
+	if offset == 0 {
+		result.SearchID = h.logSearchQuery(c.Request.Context(), query, "site", result.Total, time.Since(start))
+	} else if searchID, err := strconv.ParseInt(c.Query("search_id"), 10, 64); err == nil && searchID > 0 {
+		result.SearchID = &searchID
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": result})
+}
+
+// SearchPassages performs a semantic search over chunks of products and blog posts and
+// returns the best matching passage of each
+func (h *Handlers) SearchPassages(c *gin.Context) {
+	query := strings.TrimSpace(c.Query("q"))
+	if query == "" {
+		c.Error(requiredQuery("q"))
+		return
+	}
+
+	var types []string
+	if typeStr := c.Query("type"); typeStr != "" {
+		for _, t := range strings.Split(typeStr, ",") {
+			t = strings.TrimSpace(t)
+			if t == "" {
+				continue
+			}
+			if t != "product" && t != "blog" {
+				c.Error(apperror.Invalid(apperror.FieldError{Field: "type", Code: "one_of", Param: "product, blog"}))
+				return
+			}
+			types = append(types, t)
+		}
+	}
+
+	limit := 10
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 {
+			limit = l
+		}
+	}
+	if limit > 50 {
+		limit = 50
+	}
+
+	start := time.Now()
+	passages, err := h.chunkService.SearchPassages(c.Request.Context(), query, types, limit)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response := gin.H{"data": passages}
+	if searchID := h.logSearchQuery(c.Request.Context(), query, "passages", len(passages), time.Since(start)); searchID != nil {
+		response["search_id"] = *searchID
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// logSearchQuery records the query for analytics; failures never fail the search itself
+func (h *Handlers) logSearchQuery(ctx context.Context, query, source string, resultCount int, latency time.Duration) *int64 {
+	searchID, err := h.searchAnalytics.LogQuery(ctx, query, source, resultCount, latency)
+	if err != nil {
+		logger.WarnContext(ctx, "Failed to log search query: %v", err)
+		return nil
+	}
+	return &searchID
+}
+
+// TrackSearchClick records a click-through on a search result
+func (h *Handlers) TrackSearchClick(c *gin.Context) {
+	var req struct {
+		SearchID int64  `json:"search_id" binding:"required"`
+		Type     string `json:"type" binding:"required"`
+		ID       int    `json:"id" binding:"required"`
+		Position *int   `json:"position,omitempty"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(bindError(err))
+		return
+	}
+
+	if !isSearchType(req.Type) {
+		c.Error(invalidSearchType())
+		return
+	}
+
+	if err := h.searchAnalytics.LogClick(c.Request.Context(), req.SearchID, req.Type, req.ID, req.Position); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Search click recorded"})
+}
+
+// GetSearchAnalytics returns top queries, zero-result queries and click-through rate (admin only)
+// Accepts from/to as YYYY-MM-DD; defaults to the last 30 days, "to" is inclusive
+func (h *Handlers) GetSearchAnalytics(c *gin.Context) {
+	to := time.Now().Truncate(24 * time.Hour).Add(24 * time.Hour)
+	if toStr := c.Query("to"); toStr != "" {
+		t, err := time.Parse("2006-01-02", toStr)
+		if err != nil {
+			c.Error(apperror.Invalid(apperror.FieldError{Field: "to", Code: "invalid_date", Param: "YYYY-MM-DD"}))
+			return
+		}
+		to = t.Add(24 * time.Hour)
+	}
+
+	from := to.AddDate(0, 0, -30)
+	if fromStr := c.Query("from"); fromStr != "" {
+		f, err := time.Parse("2006-01-02", fromStr)
+		if err != nil {
+			c.Error(apperror.Invalid(apperror.FieldError{Field: "from", Code: "invalid_date", Param: "YYYY-MM-DD"}))
+			return
+		}
+		from = f
+	}
+
+	if !from.Before(to) {
+		c.Error(apperror.Invalid(apperror.FieldError{Field: "from", Code: "not_after", Param: "to"}))
+		return
+	}
+
+	limit := 20
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 {
+			limit = l
+		}
+	}
+
+	analytics, err := h.searchAnalytics.GetAnalytics(c.Request.Context(), from, to, limit)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": analytics})
+}
+
+// invalidSearchType is the error for a type that is not one of services.SearchTypes
+func invalidSearchType() error {
+	return apperror.Invalid(apperror.FieldError{Field: "type", Code: "one_of", Param: strings.Join(services.SearchTypes, ", ")})
+}
+
+func isSearchType(t string) bool {
+	for _, st := range services.SearchTypes {
+		if st == t {
+			return true
+		}
+	}
+	return false
 }
@@ -4,7 +4,6 @@ import (
 	"net/http"
 	"strconv"
 
-	"bizgenie-api/internal/logger"
 	"bizgenie-api/internal/models"
 
 	"github.com/gin-gonic/gin"
@@ -13,10 +12,9 @@ import (
 func (h *Handlers) GetSocialMediaLinks(c *gin.Context) {
 	activeOnly := c.Query("active_only") == "true"
 
-	links, err := h.socialMediaService.GetSocialMediaLinks(activeOnly)
+	links, err := h.socialMediaService.GetSocialMediaLinks(c.Request.Context(), activeOnly)
 	if err != nil {
-		logger.ErrorWithErr("Failed to get social media links", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.Error(err)
 		return
 	}
 
@@ -26,18 +24,13 @@ func (h *Handlers) GetSocialMediaLinks(c *gin.Context) {
 func (h *Handlers) GetSocialMediaLinkByID(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid social media link ID"})
+		c.Error(invalidID())
 		return
 	}
 
-	link, err := h.socialMediaService.GetSocialMediaLinkByID(id)
+	link, err := h.socialMediaService.GetSocialMediaLinkByID(c.Request.Context(), id)
 	if err != nil {
-		if err.Error() == "sql: no rows in result set" {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Social media link not found"})
-			return
-		}
-		logger.ErrorWithErr("Failed to get social media link", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.Error(err)
 		return
 	}
 
@@ -47,13 +40,12 @@ func (h *Handlers) GetSocialMediaLinkByID(c *gin.Context) {
 func (h *Handlers) CreateSocialMediaLink(c *gin.Context) {
 	var link models.SocialMediaLink
 	if err := c.ShouldBindJSON(&link); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.Error(bindError(err))
 		return
 	}
 
-	if err := h.socialMediaService.CreateSocialMediaLink(&link); err != nil {
-		logger.ErrorWithErr("Failed to create social media link", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.socialMediaService.CreateSocialMediaLink(c.Request.Context(), &link); err != nil {
+		c.Error(err)
 		return
 	}
 
@@ -63,19 +55,18 @@ func (h *Handlers) CreateSocialMediaLink(c *gin.Context) {
 func (h *Handlers) UpdateSocialMediaLink(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid social media link ID"})
+		c.Error(invalidID())
 		return
 	}
 
 	var link models.SocialMediaLink
 	if err := c.ShouldBindJSON(&link); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.Error(bindError(err))
 		return
 	}
 
-	if err := h.socialMediaService.UpdateSocialMediaLink(id, &link); err != nil {
-		logger.ErrorWithErr("Failed to update social media link", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.socialMediaService.UpdateSocialMediaLink(c.Request.Context(), id, &link); err != nil {
+		c.Error(err)
 		return
 	}
 
@@ -85,13 +76,12 @@ func (h *Handlers) UpdateSocialMediaLink(c *gin.Context) {
 func (h *Handlers) DeleteSocialMediaLink(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid social media link ID"})
+		c.Error(invalidID())
 		return
 	}
 
-	if err := h.socialMediaService.DeleteSocialMediaLink(id); err != nil {
-		logger.ErrorWithErr("Failed to delete social media link", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.socialMediaService.DeleteSocialMediaLink(c.Request.Context(), id); err != nil {
+		c.Error(err)
 		return
 	}
 
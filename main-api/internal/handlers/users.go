@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"fmt"
 	"net/http"
 	"strconv"
 
@@ -24,9 +25,9 @@ func (h *Handlers) GetUsers(c *gin.Context) {
 		}
 	}
 
-	users, err := h.userService.GetUsers(limit, offset)
+	users, err := h.userService.GetUsers(c.Request.Context(), limit, offset)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.Error(err)
 		return
 	}
 
@@ -42,7 +43,7 @@ func (h *Handlers) CreateUser(c *gin.Context) {
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.Error(bindError(err))
 		return
 	}
 
@@ -53,7 +54,7 @@ func (h *Handlers) CreateUser(c *gin.Context) {
 	// Hash password
 	passwordHash, err := h.authService.HashPassword(req.Password)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to hash password"})
+		c.Error(fmt.Errorf("hashing password: %w", err))
 		return
 	}
 
@@ -64,8 +65,8 @@ func (h *Handlers) CreateUser(c *gin.Context) {
 		Role:         req.Role,
 	}
 
-	if err := h.userService.CreateUser(user, passwordHash); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.userService.CreateUser(c.Request.Context(), user, passwordHash); err != nil {
+		c.Error(err)
 		return
 	}
 
@@ -78,7 +79,7 @@ func (h *Handlers) CreateUser(c *gin.Context) {
 func (h *Handlers) UpdateUser(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		c.Error(invalidID())
 		return
 	}
 
@@ -89,7 +90,7 @@ func (h *Handlers) UpdateUser(c *gin.Context) {
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.Error(bindError(err))
 		return
 	}
 
@@ -99,8 +100,8 @@ func (h *Handlers) UpdateUser(c *gin.Context) {
 		Role:     req.Role,
 	}
 
-	if err := h.userService.UpdateUser(id, user); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.userService.UpdateUser(c.Request.Context(), id, user); err != nil {
+		c.Error(err)
 		return
 	}
 
@@ -110,12 +111,12 @@ func (h *Handlers) UpdateUser(c *gin.Context) {
 func (h *Handlers) DeleteUser(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		c.Error(invalidID())
 		return
 	}
 
-	if err := h.userService.DeleteUser(id); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.userService.DeleteUser(c.Request.Context(), id); err != nil {
+		c.Error(err)
 		return
 	}
 
@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"bizgenie-api/internal/apperror"
+	"bizgenie-api/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// vectorIndexTable returns the table named by value, products by default
+func vectorIndexTable(value string) (string, error) {
+	if value == "" {
+		return "products", nil
+	}
+	for _, table := range services.VectorIndexTables {
+		if value == table {
+			return table, nil
+		}
+	}
+	return "", apperror.Invalid(apperror.FieldError{Field: "table", Code: "one_of", Param: strings.Join(services.VectorIndexTables, ", ")})
+}
+
+// GetVectorIndexStatus returns embedding coverage and ANN index state (admin only)
+func (h *Handlers) GetVectorIndexStatus(c *gin.Context) {
+	table, err := vectorIndexTable(c.Query("table"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	status, err := h.vectorIndexService.GetStatus(c.Request.Context(), table)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": status})
+}
+
+// RebuildVectorIndex builds or rebuilds the embedding index of products or content chunks (admin only)
+func (h *Handlers) RebuildVectorIndex(c *gin.Context) {
+	var req struct {
+		Table  string `json:"table"`  // 'products' (default) or 'content_chunks'
+		Method string `json:"method"` // 'hnsw' (default) or 'ivfflat'
+	}
+	// The body is optional, an empty request rebuilds with the default method
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.Error(bindError(err))
+		return
+	}
+
+	if req.Method == "" {
+		req.Method = "hnsw"
+	}
+	if req.Method != "hnsw" && req.Method != "ivfflat" {
+		c.Error(apperror.Invalid(apperror.FieldError{Field: "method", Code: "one_of", Param: "hnsw, ivfflat"}))
+		return
+	}
+
+	table, err := vectorIndexTable(req.Table)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	status, err := h.vectorIndexService.RebuildIndex(c.Request.Context(), table, req.Method)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": status})
+}
+
+// GetVectorIndexRecall measures ANN recall against exact search on a random sample (admin only)
+func (h *Handlers) GetVectorIndexRecall(c *gin.Context) {
+	table, err := vectorIndexTable(c.Query("table"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	sample := 20
+	if sampleStr := c.Query("sample"); sampleStr != "" {
+		if s, err := strconv.Atoi(sampleStr); err == nil && s > 0 && s <= 200 {
+			sample = s
+		}
+	}
+
+	k := 10
+	if kStr := c.Query("k"); kStr != "" {
+		if v, err := strconv.Atoi(kStr); err == nil && v > 0 && v <= 100 {
+			k = v
+		}
+	}
+
+	recall, err := h.vectorIndexService.MeasureRecall(c.Request.Context(), table, sample, k)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": recall})
+}
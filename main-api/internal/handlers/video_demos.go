@@ -30,9 +30,9 @@ func (h *Handlers) GetVideoDemos(c *gin.Context) {
 		}
 	}
 
-	demos, err := h.videoDemoService.GetVideoDemos(status, limit, offset)
+	demos, err := h.videoDemoService.GetVideoDemos(c.Request.Context(), status, limit, offset)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.Error(err)
 		return
 	}
 
@@ -43,17 +43,13 @@ func (h *Handlers) GetVideoDemos(c *gin.Context) {
 func (h *Handlers) GetVideoDemoByID(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid video demo ID"})
+		c.Error(invalidID())
 		return
 	}
 
-	demo, err := h.videoDemoService.GetVideoDemoByID(id)
+	demo, err := h.videoDemoService.GetVideoDemoByID(c.Request.Context(), id)
 	if err != nil {
-		if err.Error() == "video demo not found" || err.Error() == "sql: no rows in result set" {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Video demo not found"})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.Error(err)
 		return
 	}
 
@@ -78,9 +74,9 @@ func (h *Handlers) GetAdminVideoDemos(c *gin.Context) {
 		}
 	}
 
-	demos, err := h.videoDemoService.GetVideoDemos(status, limit, offset)
+	demos, err := h.videoDemoService.GetVideoDemos(c.Request.Context(), status, limit, offset)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.Error(err)
 		return
 	}
 
@@ -91,7 +87,7 @@ func (h *Handlers) GetAdminVideoDemos(c *gin.Context) {
 func (h *Handlers) CreateVideoDemo(c *gin.Context) {
 	var demo models.VideoDemo
 	if err := c.ShouldBindJSON(&demo); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.Error(bindError(err))
 		return
 	}
 
@@ -103,8 +99,8 @@ func (h *Handlers) CreateVideoDemo(c *gin.Context) {
 		demo.VideoType = "url"
 	}
 
-	if err := h.videoDemoService.CreateVideoDemo(&demo); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.videoDemoService.CreateVideoDemo(c.Request.Context(), &demo); err != nil {
+		c.Error(err)
 		return
 	}
 
@@ -115,22 +111,18 @@ func (h *Handlers) CreateVideoDemo(c *gin.Context) {
 func (h *Handlers) UpdateVideoDemo(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid video demo ID"})
+		c.Error(invalidID())
 		return
 	}
 
 	var demo models.VideoDemo
 	if err := c.ShouldBindJSON(&demo); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.Error(bindError(err))
 		return
 	}
 
-	if err := h.videoDemoService.UpdateVideoDemo(id, &demo); err != nil {
-		if err.Error() == "video demo not found" {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Video demo not found"})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.videoDemoService.UpdateVideoDemo(c.Request.Context(), id, &demo); err != nil {
+		c.Error(err)
 		return
 	}
 
@@ -141,16 +133,12 @@ func (h *Handlers) UpdateVideoDemo(c *gin.Context) {
 func (h *Handlers) DeleteVideoDemo(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid video demo ID"})
+		c.Error(invalidID())
 		return
 	}
 
-	if err := h.videoDemoService.DeleteVideoDemo(id); err != nil {
-		if err.Error() == "video demo not found" {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Video demo not found"})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.videoDemoService.DeleteVideoDemo(c.Request.Context(), id); err != nil {
+		c.Error(err)
 		return
 	}
 
@@ -0,0 +1,91 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"runtime"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+type fieldsKey struct{}
+
+// WithFields returns a copy of ctx whose log lines also carry args, given as alternating
+// keys and values like slog. Later fields with the same key win.
+func WithFields(ctx context.Context, args ...interface{}) context.Context {
+	record := slog.Record{}
+	record.Add(args...)
+	existing, _ := ctx.Value(fieldsKey{}).([]slog.Attr)
+	fields := make([]slog.Attr, 0, len(existing)+record.NumAttrs())
+	record.Attrs(func(a slog.Attr) bool {
+		fields = append(fields, a)
+		return true
+	})
+	for _, a := range existing {
+		if !hasKey(fields, a.Key) {
+			fields = append(fields, a)
+		}
+	}
+	return context.WithValue(ctx, fieldsKey{}, fields)
+}
+
+// Field returns the value of a field stored in ctx by WithFields
+func Field(ctx context.Context, key string) (interface{}, bool) {
+	fields, _ := ctx.Value(fieldsKey{}).([]slog.Attr)
+	for _, a := range fields {
+		if a.Key == key {
+			return a.Value.Any(), true
+		}
+	}
+	return nil, false
+}
+
+func hasKey(attrs []slog.Attr, key string) bool {
+	for _, a := range attrs {
+		if a.Key == key {
+			return true
+		}
+	}
+	return false
+}
+
+// contextHandler adds the caller, the fields and trace of the context, and masks personal
+// data, before passing records on to the JSON or text handler
+type contextHandler struct {
+	inner slog.Handler
+}
+
+func (h *contextHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.inner.Enabled(ctx, level)
+}
+
+func (h *contextHandler) Handle(ctx context.Context, r slog.Record) error {
+	out := slog.NewRecord(r.Time, r.Level, redact(r.Message), r.PC)
+	if r.PC != 0 {
+		frame, _ := runtime.CallersFrames([]uintptr{r.PC}).Next()
+		out.AddAttrs(slog.String("caller", fmt.Sprintf("%s:%d", filepath.Base(frame.File), frame.Line)))
+	}
+	if fields, ok := ctx.Value(fieldsKey{}).([]slog.Attr); ok {
+		for _, a := range fields {
+			out.AddAttrs(redactAttr(a))
+		}
+	}
+	if span := trace.SpanContextFromContext(ctx); span.IsValid() {
+		out.AddAttrs(slog.String("trace_id", span.TraceID().String()), slog.String("span_id", span.SpanID().String()))
+	}
+	r.Attrs(func(a slog.Attr) bool {
+		out.AddAttrs(redactAttr(a))
+		return true
+	})
+	return h.inner.Handle(ctx, out)
+}
+
+func (h *contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &contextHandler{inner: h.inner.WithAttrs(attrs)}
+}
+
+func (h *contextHandler) WithGroup(name string) slog.Handler {
+	return &contextHandler{inner: h.inner.WithGroup(name)}
+}
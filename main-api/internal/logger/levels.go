@@ -0,0 +1,114 @@
+package logger
+
+import (
+	"fmt"
+	"log/slog"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// levels holds the default minimum level and the per-package overrides
+var levels = &levelTable{defaultLvl: slog.LevelInfo, packages: map[string]slog.Level{}}
+
+type levelTable struct {
+	mu         sync.RWMutex
+	defaultLvl slog.Level
+	packages   map[string]slog.Level
+	callers    sync.Map // uintptr -> package name
+}
+
+func (t *levelTable) setDefault(level slog.Level) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.defaultLvl = level
+}
+
+func (t *levelTable) defaultLevel() slog.Level {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.defaultLvl
+}
+
+func (t *levelTable) enabled(pc uintptr, level slog.Level) bool {
+	pkg := t.packageOf(pc)
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	min, ok := t.packages[pkg]
+	if !ok {
+		min = t.defaultLvl
+	}
+	return level >= min
+}
+
+// packageOf returns the last element of the import path of the function at pc, e.g.
+// "services" for bizgenie-api/internal/services.(*ProductService).GetProducts
+func (t *levelTable) packageOf(pc uintptr) string {
+	if pkg, ok := t.callers.Load(pc); ok {
+		return pkg.(string)
+	}
+	fn := runtime.FuncForPC(pc)
+	if fn == nil {
+		return ""
+	}
+	name := fn.Name()
+	if slash := strings.LastIndexByte(name, '/'); slash >= 0 {
+		name = name[slash+1:]
+	}
+	if dot := strings.IndexByte(name, '.'); dot >= 0 {
+		name = name[:dot]
+	}
+	t.callers.Store(pc, name)
+	return name
+}
+
+// SetPackageLevel overrides the minimum level of one package, named by the last element
+// of its import path such as "services" or "handlers"
+func SetPackageLevel(pkg string, level LogLevel) {
+	levels.mu.Lock()
+	defer levels.mu.Unlock()
+	levels.packages[pkg] = toSlogLevel(level)
+}
+
+// ResetPackageLevel removes the override of a package so it follows the default level again
+func ResetPackageLevel(pkg string) {
+	levels.mu.Lock()
+	defer levels.mu.Unlock()
+	delete(levels.packages, pkg)
+}
+
+// PackageLevels returns the current per-package overrides
+func PackageLevels() map[string]LogLevel {
+	levels.mu.RLock()
+	defer levels.mu.RUnlock()
+	overrides := make(map[string]LogLevel, len(levels.packages))
+	for pkg, level := range levels.packages {
+		overrides[pkg] = fromSlogLevel(level)
+	}
+	return overrides
+}
+
+// SetPackageLevelsFromString applies overrides written as "services=debug,database=warn".
+// Nothing is applied when an entry is invalid.
+func SetPackageLevelsFromString(spec string) error {
+	parsed := map[string]LogLevel{}
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		pkg, levelName, ok := strings.Cut(entry, "=")
+		if !ok || strings.TrimSpace(pkg) == "" {
+			return fmt.Errorf("invalid package level %q, expected package=level", entry)
+		}
+		level, err := ParseLevel(strings.TrimSpace(levelName))
+		if err != nil {
+			return err
+		}
+		parsed[strings.TrimSpace(pkg)] = level
+	}
+	for pkg, level := range parsed {
+		SetPackageLevel(pkg, level)
+	}
+	return nil
+}
@@ -1,11 +1,17 @@
+// Package logger writes structured logs through log/slog, as JSON for the log aggregator or
+// as key=value text for local development. Every line carries its caller, the fields stored
+// in the context (request ID, user ID, route) and the trace ID, and emails and phone numbers
+// in it are masked. Levels can be overridden per package at runtime.
 package logger
 
 import (
+	"context"
 	"fmt"
-	"log"
+	"log/slog"
 	"os"
 	"runtime"
 	"strings"
+	"sync/atomic"
 	"time"
 )
 
@@ -18,112 +24,189 @@ const (
 	ERROR
 )
 
-var (
-	currentLevel LogLevel = INFO
-	logger       *log.Logger
-)
+// levelFatal is logged by Fatal and FatalWithErr right before the process exits
+const levelFatal = slog.LevelError + 4
+
+var output atomic.Pointer[slog.Logger]
 
 func init() {
-	logger = log.New(os.Stdout, "", 0)
+	SetFormat("text")
+}
+
+// SetFormat switches the output between "json" and "text" (key=value); anything else is text
+func SetFormat(format string) {
+	options := &slog.HandlerOptions{Level: slog.LevelDebug - 4, ReplaceAttr: replaceAttr}
+	var inner slog.Handler
+	if strings.ToLower(format) == "json" {
+		inner = slog.NewJSONHandler(os.Stdout, options)
+	} else {
+		inner = slog.NewTextHandler(os.Stdout, options)
+	}
+	output.Store(slog.New(&contextHandler{inner: inner}))
 }
 
-// SetLevel sets the minimum log level
+// replaceAttr names the fatal level, which slog would print as ERROR+4
+func replaceAttr(groups []string, a slog.Attr) slog.Attr {
+	if a.Key == slog.LevelKey && len(groups) == 0 {
+		if level, ok := a.Value.Any().(slog.Level); ok && level == levelFatal {
+			a.Value = slog.StringValue("FATAL")
+		}
+	}
+	return a
+}
+
+// SetLevel sets the minimum log level of packages without an override
 func SetLevel(level LogLevel) {
-	currentLevel = level
+	levels.setDefault(toSlogLevel(level))
 }
 
 // SetLevelFromString sets the log level from string (debug, info, warn, error)
 func SetLevelFromString(level string) {
+	parsed, err := ParseLevel(level)
+	if err != nil {
+		parsed = INFO
+	}
+	SetLevel(parsed)
+}
+
+// GetLevel returns the current log level of packages without an override
+func GetLevel() LogLevel {
+	return fromSlogLevel(levels.defaultLevel())
+}
+
+// ParseLevel parses debug, info, warn or error
+func ParseLevel(level string) (LogLevel, error) {
 	switch strings.ToLower(level) {
 	case "debug":
-		SetLevel(DEBUG)
+		return DEBUG, nil
 	case "info":
-		SetLevel(INFO)
-	case "warn":
-		SetLevel(WARN)
+		return INFO, nil
+	case "warn", "warning":
+		return WARN, nil
 	case "error":
-		SetLevel(ERROR)
-	default:
-		SetLevel(INFO)
+		return ERROR, nil
 	}
+	return INFO, fmt.Errorf("unknown log level: %s", level)
 }
 
-// GetLevel returns the current log level
-func GetLevel() LogLevel {
-	return currentLevel
+func (l LogLevel) String() string {
+	switch l {
+	case DEBUG:
+		return "debug"
+	case WARN:
+		return "warn"
+	case ERROR:
+		return "error"
+	}
+	return "info"
 }
 
-// getCallerInfo returns file and line number of the caller
-func getCallerInfo() string {
-	_, file, line, ok := runtime.Caller(3)
-	if !ok {
-		return "unknown:0"
+func toSlogLevel(level LogLevel) slog.Level {
+	switch level {
+	case DEBUG:
+		return slog.LevelDebug
+	case WARN:
+		return slog.LevelWarn
+	case ERROR:
+		return slog.LevelError
 	}
-	// Get only the filename, not the full path
-	parts := strings.Split(file, "/")
-	filename := parts[len(parts)-1]
-	return fmt.Sprintf("%s:%d", filename, line)
+	return slog.LevelInfo
 }
 
-// formatMessage formats the log message with timestamp, level, and caller info
-func formatMessage(level string, msg string) string {
-	timestamp := time.Now().Format("2006-01-02 15:04:05")
-	caller := getCallerInfo()
-	return fmt.Sprintf("[%s] [%s] [%s] %s", timestamp, level, caller, msg)
+func fromSlogLevel(level slog.Level) LogLevel {
+	switch {
+	case level <= slog.LevelDebug:
+		return DEBUG
+	case level <= slog.LevelInfo:
+		return INFO
+	case level <= slog.LevelWarn:
+		return WARN
+	}
+	return ERROR
+}
+
+// write logs msg as seen from the caller of the exported function that called it
+func write(ctx context.Context, level slog.Level, msg string, attrs ...slog.Attr) {
+	var pcs [1]uintptr
+	runtime.Callers(3, pcs[:])
+	if level < levelFatal && !levels.enabled(pcs[0], level) {
+		return
+	}
+	record := slog.NewRecord(time.Now(), level, msg, pcs[0])
+	record.AddAttrs(attrs...)
+	_ = output.Load().Handler().Handle(ctx, record)
 }
 
 // Debug logs a debug message
 func Debug(format string, v ...interface{}) {
-	if currentLevel <= DEBUG {
-		msg := fmt.Sprintf(format, v...)
-		logger.Println(formatMessage("DEBUG", msg))
-	}
+	write(context.Background(), slog.LevelDebug, fmt.Sprintf(format, v...))
 }
 
 // Info logs an info message
 func Info(format string, v ...interface{}) {
-	if currentLevel <= INFO {
-		msg := fmt.Sprintf(format, v...)
-		logger.Println(formatMessage("INFO", msg))
-	}
+	write(context.Background(), slog.LevelInfo, fmt.Sprintf(format, v...))
 }
 
 // Warn logs a warning message
 func Warn(format string, v ...interface{}) {
-	if currentLevel <= WARN {
-		msg := fmt.Sprintf(format, v...)
-		logger.Println(formatMessage("WARN", msg))
-	}
+	write(context.Background(), slog.LevelWarn, fmt.Sprintf(format, v...))
 }
 
 // Error logs an error message
 func Error(format string, v ...interface{}) {
-	if currentLevel <= ERROR {
-		msg := fmt.Sprintf(format, v...)
-		logger.Println(formatMessage("ERROR", msg))
-	}
+	write(context.Background(), slog.LevelError, fmt.Sprintf(format, v...))
 }
 
 // ErrorWithErr logs an error message with error details
 func ErrorWithErr(msg string, err error) {
-	if currentLevel <= ERROR {
-		caller := getCallerInfo()
-		timestamp := time.Now().Format("2006-01-02 15:04:05")
-		logger.Printf("[%s] [ERROR] [%s] %s: %v\n", timestamp, caller, msg, err)
-	}
+	write(context.Background(), slog.LevelError, msg, errorAttr(err))
+}
+
+// DebugContext logs a debug message with the fields of ctx
+func DebugContext(ctx context.Context, format string, v ...interface{}) {
+	write(ctx, slog.LevelDebug, fmt.Sprintf(format, v...))
+}
+
+// InfoContext logs an info message with the fields of ctx
+func InfoContext(ctx context.Context, format string, v ...interface{}) {
+	write(ctx, slog.LevelInfo, fmt.Sprintf(format, v...))
+}
+
+// WarnContext logs a warning message with the fields of ctx
+func WarnContext(ctx context.Context, format string, v ...interface{}) {
+	write(ctx, slog.LevelWarn, fmt.Sprintf(format, v...))
+}
+
+// ErrorContext logs an error message with the fields of ctx
+func ErrorContext(ctx context.Context, format string, v ...interface{}) {
+	write(ctx, slog.LevelError, fmt.Sprintf(format, v...))
+}
+
+// ErrorWithErrContext logs an error message with error details and the fields of ctx
+func ErrorWithErrContext(ctx context.Context, msg string, err error) {
+	write(ctx, slog.LevelError, msg, errorAttr(err))
+}
+
+// LogAttrs logs msg with structured attributes and the fields of ctx
+func LogAttrs(ctx context.Context, level LogLevel, msg string, attrs ...slog.Attr) {
+	write(ctx, toSlogLevel(level), msg, attrs...)
 }
 
 // Fatal logs a fatal error and exits
 func Fatal(format string, v ...interface{}) {
-	msg := fmt.Sprintf(format, v...)
-	caller := getCallerInfo()
-	timestamp := time.Now().Format("2006-01-02 15:04:05")
-	logger.Fatalf("[%s] [FATAL] [%s] %s\n", timestamp, caller, msg)
+	write(context.Background(), levelFatal, fmt.Sprintf(format, v...))
+	os.Exit(1)
 }
 
 // FatalWithErr logs a fatal error with error details and exits
 func FatalWithErr(msg string, err error) {
-	caller := getCallerInfo()
-	timestamp := time.Now().Format("2006-01-02 15:04:05")
-	logger.Fatalf("[%s] [FATAL] [%s] %s: %v\n", timestamp, caller, msg, err)
+	write(context.Background(), levelFatal, msg, errorAttr(err))
+	os.Exit(1)
+}
+
+func errorAttr(err error) slog.Attr {
+	if err == nil {
+		return slog.String("error", "<nil>")
+	}
+	return slog.String("error", err.Error())
 }
@@ -0,0 +1,115 @@
+package logger
+
+import (
+	"log/slog"
+	"regexp"
+	"strings"
+)
+
+var (
+	emailPattern = regexp.MustCompile(`[A-Za-z0-9._%+-]+@(?:[A-Za-z0-9-]+\.)+[A-Za-z]{2,}`)
+	// Vietnamese numbers (0912 345 678, 024 3826 1234, 84912345678) and international
+	// ones with a leading + (+84 912 345 678). Every group has at least two digits and a
+	// national number starts with a group of three, so IPv4 addresses such as
+	// 84.123.45.67 and decimals such as 0.123456789 do not match.
+	phonePattern = regexp.MustCompile(`(?:\+\d{1,3}[ .-]?\d{2,4}|\b0\d{2,3}|\b84 ?\d{2,3})(?:[ .-]?\d{2,4}){2,4}\b`)
+)
+
+// redact masks emails and phone numbers in s, keeping enough to tell entries apart:
+// the first letter and domain of an email, the last three digits of a phone number
+func redact(s string) string {
+	if !strings.ContainsAny(s, "@0123456789") {
+		return s
+	}
+	s = emailPattern.ReplaceAllStringFunc(s, maskEmail)
+
+	matches := phonePattern.FindAllStringIndex(s, -1)
+	if matches == nil {
+		return s
+	}
+	var b strings.Builder
+	last := 0
+	for _, m := range matches {
+		if !isPhone(s, m[0], m[1]) {
+			continue
+		}
+		b.WriteString(s[last:m[0]])
+		b.WriteString(maskPhone(s[m[0]:m[1]]))
+		last = m[1]
+	}
+	b.WriteString(s[last:])
+	return b.String()
+}
+
+// isPhone reports whether the phonePattern match s[start:end] has the digit count of
+// a phone number and is not part of a dotted number such as an IP address
+func isPhone(s string, start, end int) bool {
+	if start > 0 && s[start-1] == '.' {
+		return false
+	}
+	if end+1 < len(s) && s[end] == '.' && s[end+1] >= '0' && s[end+1] <= '9' {
+		return false
+	}
+
+	match := s[start:end]
+	digits := 0
+	for i := 0; i < len(match); i++ {
+		if match[i] >= '0' && match[i] <= '9' {
+			digits++
+		}
+	}
+	switch {
+	case match[0] == '+':
+		return digits >= 8 && digits <= 15
+	case match[0] == '0':
+		return digits == 10 || digits == 11
+	default: // 84 without the +
+		return digits == 11 || digits == 12
+	}
+}
+
+func maskEmail(email string) string {
+	at := strings.LastIndexByte(email, '@')
+	return email[:1] + "***" + email[at:]
+}
+
+func maskPhone(phone string) string {
+	digits := make([]byte, 0, len(phone))
+	for i := 0; i < len(phone); i++ {
+		if phone[i] >= '0' && phone[i] <= '9' {
+			digits = append(digits, phone[i])
+		}
+	}
+	return "***" + string(digits[len(digits)-3:])
+}
+
+// redactAttr masks personal data in string values, including values of nested groups.
+// Values of keys named after the data, such as "email" or "phone", are masked whole;
+// IP addresses under "ip" or "client_ip" are kept as they are.
+func redactAttr(a slog.Attr) slog.Attr {
+	switch a.Value.Kind() {
+	case slog.KindString:
+		value := a.Value.String()
+		switch strings.ToLower(a.Key) {
+		case "ip", "client_ip":
+			return a
+		case "email":
+			if strings.Contains(value, "@") {
+				return slog.String(a.Key, maskEmail(value))
+			}
+		case "phone":
+			if len(value) > 3 {
+				return slog.String(a.Key, "***"+value[len(value)-3:])
+			}
+		}
+		return slog.String(a.Key, redact(value))
+	case slog.KindGroup:
+		group := a.Value.Group()
+		redacted := make([]slog.Attr, len(group))
+		for i, member := range group {
+			redacted[i] = redactAttr(member)
+		}
+		return slog.Attr{Key: a.Key, Value: slog.GroupValue(redacted...)}
+	}
+	return a
+}
@@ -0,0 +1,65 @@
+package logger
+
+import (
+	"log/slog"
+	"testing"
+)
+
+func TestRedact(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		// Phone numbers
+		{"Gọi 0912345678", "Gọi ***678"},
+		{"Gọi 0912 345 678 nhé", "Gọi ***678 nhé"},
+		{"0912.345.678", "***678"},
+		{"0912-345-678", "***678"},
+		{"024 3826 1234", "***234"},
+		{"+84 912 345 678", "***678"},
+		{"+84912345678", "***678"},
+		{"84912345678", "***678"},
+		{"+1 415 555 2671", "***671"},
+		{"phones: 0912345678, 0987654321", "phones: ***678, ***321"},
+		// Emails
+		{"from an.nguyen@example.com.vn", "from a***@example.com.vn"},
+		// Not phone numbers
+		{"client 84.123.45.67", "client 84.123.45.67"},
+		{"client 192.168.100.200", "client 192.168.100.200"},
+		{"client 10.0.912.345", "client 10.0.912.345"},
+		{"score 0.123456789", "score 0.123456789"},
+		{"price 12.345.678", "price 12.345.678"},
+		{"order 0912", "order 0912"},
+		{"id 091234567890123", "id 091234567890123"},
+		{"took 1500ms", "took 1500ms"},
+		{"2026-10-19 03:16:50", "2026-10-19 03:16:50"},
+	}
+	for _, tt := range tests {
+		if got := redact(tt.in); got != tt.want {
+			t.Errorf("redact(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestRedactAttr(t *testing.T) {
+	tests := []struct {
+		attr slog.Attr
+		want string
+	}{
+		{slog.String("email", "an@example.com"), "a***@example.com"},
+		{slog.String("phone", "0912345678"), "***678"},
+		{slog.String("client_ip", "84.123.45.67"), "84.123.45.67"},
+		{slog.String("ip", "2001:db8::1"), "2001:db8::1"},
+		{slog.String("query", "gọi 0912345678"), "gọi ***678"},
+	}
+	for _, tt := range tests {
+		if got := redactAttr(tt.attr).Value.String(); got != tt.want {
+			t.Errorf("redactAttr(%v) = %q, want %q", tt.attr, got, tt.want)
+		}
+	}
+
+	group := redactAttr(slog.Group("contact", slog.String("phone", "0912345678"), slog.String("ip", "84.123.45.67")))
+	if got := group.Value.Group(); got[0].Value.String() != "***678" || got[1].Value.String() != "84.123.45.67" {
+		t.Errorf("redactAttr(group) = %v, want the phone masked and the IP kept", got)
+	}
+}
@@ -0,0 +1,75 @@
+package metrics
+
+import (
+	"database/sql"
+	"time"
+)
+
+// Metrics recorded by the API; gauges read from the database are registered by the server
+var (
+	HTTPRequests = NewCounterVec("http_requests_total",
+		"HTTP requests by method, route template and status code.", "method", "route", "status")
+	HTTPRequestDuration = NewHistogramVec("http_request_duration_seconds",
+		"HTTP request latency by method, route template and status code.", DefaultBuckets, "method", "route", "status")
+
+	DBQueryDuration = NewHistogramVec("db_query_duration_seconds",
+		"Duration of service queries by query family, e.g. products.get_by_slug.", DefaultBuckets, "family")
+
+	SearchDuration = NewHistogramVec("search_duration_seconds",
+		"Search latency by kind: site, passages, products_text or products_vector.", DefaultBuckets, "kind")
+
+	Notifications = NewCounterVec("notifications_total",
+		"Notifications sent by channel and result: success, failure or skipped.", "channel", "result")
+
+	BackgroundTasksInFlight = NewGaugeVec("background_tasks_in_flight",
+		"Background tasks started by requests or schedules that have not finished.")
+	BackgroundTasksCompleted = NewCounterVec("background_tasks_completed_total",
+		"Background tasks that have finished.")
+
+	RateLimited = NewCounterVec("rate_limited_requests_total",
+		"Requests refused by the rate limiter by method and route template.", "method", "route")
+
+	EmbeddingBacklog = NewGaugeVec("embedding_backlog",
+		"Rows waiting for an embedding by content type: product, faq or chunk.", "type")
+
+	Backups = NewCounterVec("backups_total",
+		"Scheduled backups by result: success, failure or skipped.", "result")
+	BackupLastSuccess = NewGaugeVec("backup_last_success_timestamp_seconds",
+		"Unix time of the last successful scheduled backup taken by this replica.")
+)
+
+// ObserveQuery records the time since start for a query family; call it as
+// defer metrics.ObserveQuery("products.get_by_slug", time.Now())
+func ObserveQuery(family string, start time.Time) {
+	DBQueryDuration.Observe(time.Since(start).Seconds(), family)
+}
+
+// ObserveSearch records the time since start for a kind of search
+func ObserveSearch(kind string, start time.Time) {
+	SearchDuration.Observe(time.Since(start).Seconds(), kind)
+}
+
+// RegisterDBStats exports the connection pool statistics of db
+func RegisterDBStats(db *sql.DB) {
+	stat := func(value func(sql.DBStats) float64) func() []Sample {
+		return func() []Sample {
+			return []Sample{{Value: value(db.Stats())}}
+		}
+	}
+	NewGaugeFunc("db_max_open_connections", "Maximum number of open connections to the database.", nil,
+		stat(func(s sql.DBStats) float64 { return float64(s.MaxOpenConnections) }))
+	NewGaugeFunc("db_open_connections", "Established connections, in use and idle.", nil,
+		stat(func(s sql.DBStats) float64 { return float64(s.OpenConnections) }))
+	NewGaugeFunc("db_in_use_connections", "Connections currently in use.", nil,
+		stat(func(s sql.DBStats) float64 { return float64(s.InUse) }))
+	NewGaugeFunc("db_idle_connections", "Idle connections.", nil,
+		stat(func(s sql.DBStats) float64 { return float64(s.Idle) }))
+	NewCounterFunc("db_wait_count_total", "Connections waited for because the pool was exhausted.", nil,
+		stat(func(s sql.DBStats) float64 { return float64(s.WaitCount) }))
+	NewCounterFunc("db_wait_duration_seconds_total", "Time spent waiting for a connection.", nil,
+		stat(func(s sql.DBStats) float64 { return s.WaitDuration.Seconds() }))
+	NewCounterFunc("db_max_idle_closed_total", "Connections closed because of the idle pool limit.", nil,
+		stat(func(s sql.DBStats) float64 { return float64(s.MaxIdleClosed) }))
+	NewCounterFunc("db_max_lifetime_closed_total", "Connections closed because of their maximum lifetime.", nil,
+		stat(func(s sql.DBStats) float64 { return float64(s.MaxLifetimeClosed) }))
+}
@@ -0,0 +1,300 @@
+// Package metrics keeps counters, histograms and gauges in memory and writes them in the
+// Prometheus text exposition format for scraping.
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultBuckets are latency buckets in seconds, from 5ms to 10s
+var DefaultBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// collector is a metric family that can write itself in the exposition format
+type collector interface {
+	name() string
+	write(w io.Writer)
+}
+
+var (
+	registryMu sync.Mutex
+	registry   = map[string]collector{}
+)
+
+func register(c collector) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if _, ok := registry[c.name()]; ok {
+		panic("metrics: duplicate metric " + c.name())
+	}
+	registry[c.name()] = c
+}
+
+// WriteAll writes every registered metric, sorted by name
+func WriteAll(w io.Writer) {
+	registryMu.Lock()
+	collectors := make([]collector, 0, len(registry))
+	for _, c := range registry {
+		collectors = append(collectors, c)
+	}
+	registryMu.Unlock()
+
+	sort.Slice(collectors, func(i, j int) bool { return collectors[i].name() < collectors[j].name() })
+	for _, c := range collectors {
+		c.write(w)
+	}
+}
+
+// meta holds what every metric family has: a name, help text and label names
+type meta struct {
+	metricName string
+	help       string
+	labels     []string
+}
+
+func (m *meta) name() string { return m.metricName }
+
+func (m *meta) header(w io.Writer, kind string) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", m.metricName, m.help, m.metricName, kind)
+}
+
+func (m *meta) key(values []string) string {
+	if len(values) != len(m.labels) {
+		panic(fmt.Sprintf("metrics: %s takes %d label values, got %d", m.metricName, len(m.labels), len(values)))
+	}
+	return strings.Join(values, "\xff")
+}
+
+// labelString formats label pairs as {a="x",b="y"}, with extra appended last
+func labelString(names, values []string, extra ...string) string {
+	if len(names) == 0 && len(extra) == 0 {
+		return ""
+	}
+	pairs := make([]string, 0, len(names)+len(extra)/2)
+	for i, name := range names {
+		pairs = append(pairs, name+`="`+escapeLabel(values[i])+`"`)
+	}
+	for i := 0; i+1 < len(extra); i += 2 {
+		pairs = append(pairs, extra[i]+`="`+escapeLabel(extra[i+1])+`"`)
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+func escapeLabel(v string) string {
+	v = strings.ReplaceAll(v, `\`, `\\`)
+	v = strings.ReplaceAll(v, `"`, `\"`)
+	return strings.ReplaceAll(v, "\n", `\n`)
+}
+
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// sortedKeys returns the keys of a series map in a stable order
+func sortedKeys[T any](series map[string]T) []string {
+	keys := make([]string, 0, len(series))
+	for k := range series {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// CounterVec is a counter partitioned by label values
+type CounterVec struct {
+	meta
+	mu     sync.Mutex
+	series map[string]*counterSeries
+}
+
+type counterSeries struct {
+	values []string
+	value  float64
+}
+
+// NewCounterVec registers a counter; its name should end in _total
+func NewCounterVec(name, help string, labels ...string) *CounterVec {
+	c := &CounterVec{meta: meta{metricName: name, help: help, labels: labels}, series: map[string]*counterSeries{}}
+	register(c)
+	return c
+}
+
+// Inc adds one to the series with the given label values
+func (c *CounterVec) Inc(values ...string) {
+	c.Add(1, values...)
+}
+
+// Add adds v, which must not be negative, to the series with the given label values
+func (c *CounterVec) Add(v float64, values ...string) {
+	key := c.key(values)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	s, ok := c.series[key]
+	if !ok {
+		s = &counterSeries{values: append([]string(nil), values...)}
+		c.series[key] = s
+	}
+	s.value += v
+}
+
+func (c *CounterVec) write(w io.Writer) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.header(w, "counter")
+	for _, key := range sortedKeys(c.series) {
+		s := c.series[key]
+		fmt.Fprintf(w, "%s%s %s\n", c.metricName, labelString(c.labels, s.values), formatFloat(s.value))
+	}
+}
+
+// HistogramVec is a histogram partitioned by label values
+type HistogramVec struct {
+	meta
+	buckets []float64
+	mu      sync.Mutex
+	series  map[string]*histogramSeries
+}
+
+type histogramSeries struct {
+	values []string
+	counts []uint64 // Per bucket, not cumulative
+	count  uint64
+	sum    float64
+}
+
+// NewHistogramVec registers a histogram with the given upper bounds, in increasing order
+func NewHistogramVec(name, help string, buckets []float64, labels ...string) *HistogramVec {
+	h := &HistogramVec{
+		meta:    meta{metricName: name, help: help, labels: labels},
+		buckets: buckets,
+		series:  map[string]*histogramSeries{},
+	}
+	register(h)
+	return h
+}
+
+// Observe records v in the series with the given label values
+func (h *HistogramVec) Observe(v float64, values ...string) {
+	key := h.key(values)
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	s, ok := h.series[key]
+	if !ok {
+		s = &histogramSeries{values: append([]string(nil), values...), counts: make([]uint64, len(h.buckets))}
+		h.series[key] = s
+	}
+	for i, bound := range h.buckets {
+		if v <= bound {
+			s.counts[i]++
+			break
+		}
+	}
+	s.count++
+	s.sum += v
+}
+
+func (h *HistogramVec) write(w io.Writer) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.header(w, "histogram")
+	for _, key := range sortedKeys(h.series) {
+		s := h.series[key]
+		var cumulative uint64
+		for i, bound := range h.buckets {
+			cumulative += s.counts[i]
+			fmt.Fprintf(w, "%s_bucket%s %d\n", h.metricName, labelString(h.labels, s.values, "le", formatFloat(bound)), cumulative)
+		}
+		fmt.Fprintf(w, "%s_bucket%s %d\n", h.metricName, labelString(h.labels, s.values, "le", "+Inf"), s.count)
+		fmt.Fprintf(w, "%s_sum%s %s\n", h.metricName, labelString(h.labels, s.values), formatFloat(s.sum))
+		fmt.Fprintf(w, "%s_count%s %d\n", h.metricName, labelString(h.labels, s.values), s.count)
+	}
+}
+
+// GaugeVec is a gauge partitioned by label values, set directly
+type GaugeVec struct {
+	meta
+	mu     sync.Mutex
+	series map[string]*counterSeries
+}
+
+// NewGaugeVec registers a gauge
+func NewGaugeVec(name, help string, labels ...string) *GaugeVec {
+	g := &GaugeVec{meta: meta{metricName: name, help: help, labels: labels}, series: map[string]*counterSeries{}}
+	register(g)
+	return g
+}
+
+// Set sets the series with the given label values to v
+func (g *GaugeVec) Set(v float64, values ...string) {
+	g.update(values, func(s *counterSeries) { s.value = v })
+}
+
+// Add adds v, which may be negative, to the series with the given label values
+func (g *GaugeVec) Add(v float64, values ...string) {
+	g.update(values, func(s *counterSeries) { s.value += v })
+}
+
+func (g *GaugeVec) update(values []string, fn func(*counterSeries)) {
+	key := g.key(values)
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	s, ok := g.series[key]
+	if !ok {
+		s = &counterSeries{values: append([]string(nil), values...)}
+		g.series[key] = s
+	}
+	fn(s)
+}
+
+func (g *GaugeVec) write(w io.Writer) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.header(w, "gauge")
+	for _, key := range sortedKeys(g.series) {
+		s := g.series[key]
+		fmt.Fprintf(w, "%s%s %s\n", g.metricName, labelString(g.labels, s.values), formatFloat(s.value))
+	}
+}
+
+// Sample is one series of a GaugeFunc or CounterFunc
+type Sample struct {
+	Labels []string // Values for the label names, in order
+	Value  float64
+}
+
+// funcCollector computes its series at scrape time, e.g. from sql.DB pool stats
+type funcCollector struct {
+	meta
+	kind    string
+	collect func() []Sample
+}
+
+// NewGaugeFunc registers a gauge whose series are computed by collect on every scrape
+func NewGaugeFunc(name, help string, labels []string, collect func() []Sample) {
+	register(&funcCollector{meta: meta{metricName: name, help: help, labels: labels}, kind: "gauge", collect: collect})
+}
+
+// NewCounterFunc registers a counter whose series are read by collect on every scrape,
+// for totals kept elsewhere such as sql.DBStats.WaitCount
+func NewCounterFunc(name, help string, labels []string, collect func() []Sample) {
+	register(&funcCollector{meta: meta{metricName: name, help: help, labels: labels}, kind: "counter", collect: collect})
+}
+
+func (f *funcCollector) write(w io.Writer) {
+	samples := f.collect()
+	f.header(w, f.kind)
+	for _, s := range samples {
+		fmt.Fprintf(w, "%s%s %s\n", f.metricName, labelString(f.labels, s.Labels), formatFloat(s.Value))
+	}
+}
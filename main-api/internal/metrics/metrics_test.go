@@ -0,0 +1,84 @@
+package metrics
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// withRegistry runs fn with an empty registry, so only the metrics fn registers are written
+func withRegistry(t *testing.T, fn func()) {
+	t.Helper()
+	registryMu.Lock()
+	saved := registry
+	registry = map[string]collector{}
+	registryMu.Unlock()
+	t.Cleanup(func() {
+		registryMu.Lock()
+		registry = saved
+		registryMu.Unlock()
+	})
+	fn()
+}
+
+func TestWriteAll(t *testing.T) {
+	var buf bytes.Buffer
+	withRegistry(t, func() {
+		requests := NewCounterVec("test_requests_total", "Requests by method and route.", "method", "route")
+		requests.Inc("GET", "/api/products/:id")
+		requests.Add(2, "GET", "/api/products/:id")
+		requests.Inc("POST", `/api/"quoted"\path`+"\n")
+
+		latency := NewHistogramVec("test_duration_seconds", "Latency.", []float64{0.1, 1}, "kind")
+		latency.Observe(0.25, "site")
+		latency.Observe(1, "site")
+		latency.Observe(2.5, "site")
+		latency.Observe(0.05, "passages")
+
+		inFlight := NewGaugeVec("test_in_flight", "Tasks in flight.")
+		inFlight.Set(3)
+		inFlight.Add(-1)
+
+		NewGaugeFunc("test_open_connections", "Open connections.", nil, func() []Sample {
+			return []Sample{{Value: 4}}
+		})
+		NewCounterFunc("test_wait_seconds_total", "Time waited by pool.", []string{"pool"}, func() []Sample {
+			return []Sample{{Labels: []string{"main"}, Value: 0.5}, {Labels: []string{"replica"}, Value: 1e-06}}
+		})
+
+		// Registered but never recorded: only the header is written
+		NewCounterVec("test_empty_total", "Nothing recorded.", "result")
+
+		WriteAll(&buf)
+	})
+
+	golden := filepath.Join("testdata", "exposition.golden")
+	if *update {
+		if err := os.WriteFile(golden, buf.Bytes(), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := buf.String(); got != string(want) {
+		t.Errorf("WriteAll output differs from %s:\n%s\nwant:\n%s", golden, got, want)
+	}
+}
+
+func TestLabelValueCountMismatchPanics(t *testing.T) {
+	withRegistry(t, func() {
+		requests := NewCounterVec("test_requests_total", "Requests.", "method", "route")
+		defer func() {
+			if recover() == nil {
+				t.Error("Inc with a missing label value did not panic")
+			}
+		}()
+		requests.Inc("GET")
+	})
+}
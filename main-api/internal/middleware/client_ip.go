@@ -0,0 +1,21 @@
+package middleware
+
+import (
+	"bizgenie-api/internal/clientip"
+	"bizgenie-api/internal/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ClientIP resolves the address of the client behind the trusted proxies and stores it in
+// the request context, where logs, rate limits and handlers read it. gin's own ClientIP
+// trusts every proxy and is not used.
+func ClientIP(resolver *clientip.Resolver) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ip := resolver.Resolve(c.Request)
+		ctx := clientip.NewContext(c.Request.Context(), ip)
+		ctx = logger.WithFields(ctx, "client_ip", ip)
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
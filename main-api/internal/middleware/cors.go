@@ -1,21 +1,162 @@
 package middleware
 
 import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"bizgenie-api/internal/config"
+	"bizgenie-api/internal/requestid"
+
 	"github.com/gin-gonic/gin"
 )
 
-func CORS() gin.HandlerFunc {
+// CORSRoute applies a CORS policy to the routes under PathPrefix
+type CORSRoute struct {
+	PathPrefix string
+	Policy     config.CORSPolicy
+}
+
+// CORS sets the CORS headers of the route group a request belongs to, the longest matching
+// prefix winning, and answers its preflight requests. It runs on the router rather than on
+// the groups because gin does not route OPTIONS requests to them. Preflights from origins
+// the policy does not allow are refused; other requests from them get no CORS headers, so
+// browsers do not expose the response. Paths outside every group, such as /health, get no
+// CORS headers at all.
+func CORS(routes ...CORSRoute) gin.HandlerFunc {
+	policies := make([]*corsPolicy, len(routes))
+	for i, route := range routes {
+		policies[i] = newCORSPolicy(route)
+	}
+
 	return func(c *gin.Context) {
-		c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
-		c.Writer.Header().Set("Access-Control-Allow-Credentials", "true")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With")
-		c.Writer.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS, GET, PUT, DELETE, PATCH")
+		policy := matchCORSPolicy(policies, c.Request.URL.Path)
+		if policy == nil {
+			c.Next()
+			return
+		}
+
+		header := c.Writer.Header()
+		header.Add("Vary", "Origin")
+		preflight := c.Request.Method == http.MethodOptions && c.GetHeader("Access-Control-Request-Method") != ""
+		if preflight {
+			header.Add("Vary", "Access-Control-Request-Method")
+			header.Add("Vary", "Access-Control-Request-Headers")
+		}
 
-		if c.Request.Method == "OPTIONS" {
-			c.AbortWithStatus(204)
+		origin := c.GetHeader("Origin")
+		if origin == "" {
+			c.Next()
 			return
 		}
+		if !policy.allowsOrigin(origin) || preflight && !policy.methods[c.GetHeader("Access-Control-Request-Method")] {
+			if preflight {
+				c.AbortWithStatus(http.StatusForbidden)
+				return
+			}
+			c.Next()
+			return
+		}
+
+		if policy.anyOrigin && !policy.credentials {
+			header.Set("Access-Control-Allow-Origin", "*")
+		} else {
+			header.Set("Access-Control-Allow-Origin", origin)
+		}
+		if policy.credentials {
+			header.Set("Access-Control-Allow-Credentials", "true")
+		}
 
+		if preflight {
+			header.Set("Access-Control-Allow-Methods", policy.allowMethods)
+			header.Set("Access-Control-Allow-Headers", policy.allowHeaders)
+			if policy.maxAge != "" {
+				header.Set("Access-Control-Max-Age", policy.maxAge)
+			}
+			c.AbortWithStatus(http.StatusNoContent)
+			return
+		}
+
+		header.Set("Access-Control-Expose-Headers", requestid.Header)
 		c.Next()
 	}
 }
+
+// wildcardOrigin matches the subdomains of an origin: "https://*.example.com" is stored as
+// scheme "https://" and suffix ".example.com"
+type wildcardOrigin struct {
+	scheme string
+	suffix string
+}
+
+// corsPolicy is a config.CORSPolicy prepared for matching
+type corsPolicy struct {
+	prefix       string
+	anyOrigin    bool
+	origins      map[string]bool
+	wildcards    []wildcardOrigin
+	methods      map[string]bool
+	allowMethods string
+	allowHeaders string
+	maxAge       string
+	credentials  bool
+}
+
+func newCORSPolicy(route CORSRoute) *corsPolicy {
+	p := &corsPolicy{
+		prefix:       strings.TrimSuffix(route.PathPrefix, "/"),
+		origins:      map[string]bool{},
+		methods:      map[string]bool{},
+		allowMethods: strings.Join(route.Policy.AllowedMethods, ", "),
+		allowHeaders: strings.Join(route.Policy.AllowedHeaders, ", "),
+		credentials:  route.Policy.AllowCredentials,
+	}
+	for _, origin := range route.Policy.AllowedOrigins {
+		origin = strings.ToLower(origin)
+		switch {
+		case origin == "*":
+			p.anyOrigin = true
+		case strings.Contains(origin, "://*."):
+			scheme, suffix, _ := strings.Cut(origin, "://*")
+			p.wildcards = append(p.wildcards, wildcardOrigin{scheme: scheme + "://", suffix: suffix})
+		default:
+			p.origins[origin] = true
+		}
+	}
+	for _, method := range route.Policy.AllowedMethods {
+		p.methods[method] = true
+	}
+	if seconds := int(route.Policy.MaxAge.Seconds()); seconds > 0 {
+		p.maxAge = strconv.Itoa(seconds)
+	}
+	return p
+}
+
+// allowsOrigin reports whether origin is listed, or is a subdomain of a wildcard entry
+func (p *corsPolicy) allowsOrigin(origin string) bool {
+	origin = strings.ToLower(origin)
+	if p.anyOrigin || p.origins[origin] {
+		return true
+	}
+	for _, wildcard := range p.wildcards {
+		host, ok := strings.CutPrefix(origin, wildcard.scheme)
+		if ok && len(host) > len(wildcard.suffix) && strings.HasSuffix(host, wildcard.suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchCORSPolicy returns the policy with the longest prefix covering path, or nil
+func matchCORSPolicy(policies []*corsPolicy, path string) *corsPolicy {
+	var best *corsPolicy
+	for _, p := range policies {
+		if path != p.prefix && !strings.HasPrefix(path, p.prefix+"/") {
+			continue
+		}
+		if best == nil || len(p.prefix) > len(best.prefix) {
+			best = p
+		}
+	}
+	return best
+}
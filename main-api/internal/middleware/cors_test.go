@@ -0,0 +1,78 @@
+package middleware
+
+import (
+	"testing"
+
+	"bizgenie-api/internal/config"
+)
+
+func TestCORSPolicyAllowsOrigin(t *testing.T) {
+	policy := newCORSPolicy(CORSRoute{
+		PathPrefix: "/api/admin",
+		Policy: config.CORSPolicy{
+			AllowedOrigins: []string{"https://admin.bizgenie.vn", "https://*.Preview.bizgenie.vn", "http://localhost:3000"},
+		},
+	})
+
+	tests := []struct {
+		origin string
+		want   bool
+	}{
+		{"https://admin.bizgenie.vn", true},
+		{"HTTPS://Admin.BizGenie.vn", true},
+		{"http://localhost:3000", true},
+		{"https://pr-12.preview.bizgenie.vn", true},
+		{"https://a.b.preview.bizgenie.vn", true},
+		// Not listed
+		{"http://admin.bizgenie.vn", false},
+		{"https://admin.bizgenie.vn:8443", false},
+		{"http://localhost:3001", false},
+		{"https://bizgenie.vn", false},
+		{"null", false},
+		{"", false},
+		// Wildcards only stand for subdomains of the listed host
+		{"https://preview.bizgenie.vn", false},
+		{"https://.preview.bizgenie.vn", false},
+		{"https://evilpreview.bizgenie.vn", false},
+		{"https://pr-12.preview.bizgenie.vn.attacker.com", false},
+		{"http://pr-12.preview.bizgenie.vn", false},
+	}
+	for _, tt := range tests {
+		if got := policy.allowsOrigin(tt.origin); got != tt.want {
+			t.Errorf("allowsOrigin(%q) = %v, want %v", tt.origin, got, tt.want)
+		}
+	}
+
+	anyOrigin := newCORSPolicy(CORSRoute{PathPrefix: "/api", Policy: config.CORSPolicy{AllowedOrigins: []string{"*"}}})
+	if !anyOrigin.allowsOrigin("https://example.com") {
+		t.Error(`"*" policy refused https://example.com`)
+	}
+	none := newCORSPolicy(CORSRoute{PathPrefix: "/api/admin"})
+	if none.allowsOrigin("https://admin.bizgenie.vn") {
+		t.Error("empty policy allowed https://admin.bizgenie.vn")
+	}
+}
+
+func TestMatchCORSPolicy(t *testing.T) {
+	public := newCORSPolicy(CORSRoute{PathPrefix: "/api"})
+	admin := newCORSPolicy(CORSRoute{PathPrefix: "/api/admin/"})
+	policies := []*corsPolicy{public, admin}
+
+	tests := []struct {
+		path string
+		want *corsPolicy
+	}{
+		{"/api", public},
+		{"/api/products", public},
+		{"/api/admin", admin},
+		{"/api/admin/products/1", admin},
+		{"/api/administrators", public},
+		{"/apis", nil},
+		{"/health", nil},
+	}
+	for _, tt := range tests {
+		if got := matchCORSPolicy(policies, tt.path); got != tt.want {
+			t.Errorf("matchCORSPolicy(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
@@ -1,46 +1,128 @@
 package middleware
 
 import (
+	"bytes"
+	"encoding/json"
 	"net/http"
 	"runtime/debug"
+	"strings"
 
+	"bizgenie-api/internal/apperror"
 	"bizgenie-api/internal/logger"
+	"bizgenie-api/internal/requestid"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/render"
 )
 
+// ErrorHandler responds to errors handlers and middleware attach with c.Error with an
+// RFC 7807 problem document. The status and stable code come from the apperror kind and
+// code, the detail is in the language asked for with Accept-Language, and validation
+// errors list their fields. Other errors are internal: they are logged and never shown.
+// It also adds the request ID to every JSON error body, so a client report can be
+// matched with the logs.
 func ErrorHandler() gin.HandlerFunc {
 	return func(c *gin.Context) {
+		writer := &errorBodyWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+
 		c.Next()
 
-		if len(c.Errors) > 0 {
-			err := c.Errors.Last()
-			
-			// Log error with stack trace in debug mode
-			logger.ErrorWithErr("Request error", err.Err)
-			
+		c.Writer = writer.ResponseWriter
+		ctx := c.Request.Context()
+		if writer.body != nil {
+			c.Writer.Write(withRequestID(writer.body.Bytes(), requestid.FromContext(ctx)))
+		}
+
+		if len(c.Errors) == 0 || c.Writer.Written() {
+			return
+		}
+		err := c.Errors.Last().Err
+		appErr := apperror.From(err)
+
+		if appErr.Kind == apperror.KindInternal {
+			logger.ErrorWithErrContext(ctx, "Request failed", err)
 			// Log stack trace in debug mode
 			if logger.GetLevel() <= logger.DEBUG {
-				stack := debug.Stack()
-				logger.Debug("Stack trace:\n%s", string(stack))
+				logger.DebugContext(ctx, "Stack trace:\n%s", string(debug.Stack()))
 			}
+		} else {
+			logger.DebugContext(ctx, "Request refused: %v", err)
+		}
 
-			// Get error message
-			errMsg := "Internal server error"
-			if err.Meta != nil {
-				if msg, ok := err.Meta.(string); ok {
-					errMsg = msg
-				}
-			} else if err.Err != nil {
-				// In development, show actual error message
-				if gin.Mode() == gin.DebugMode {
-					errMsg = err.Err.Error()
-				}
+		status := appErr.Status()
+		lang := apperror.Language(c.GetHeader("Accept-Language"))
+		detail := appErr.Message(lang)
+		problem := gin.H{
+			"type":     "about:blank",
+			"title":    http.StatusText(status),
+			"status":   status,
+			"detail":   detail,
+			"instance": c.Request.URL.Path,
+			"code":     appErr.Code,
+			// The detail again under the name clients of the earlier error bodies read
+			"error":      detail,
+			"request_id": requestid.FromContext(ctx),
+		}
+		if len(appErr.Fields) > 0 {
+			fields := make([]gin.H, len(appErr.Fields))
+			for i, f := range appErr.Fields {
+				fields[i] = gin.H{"field": f.Field, "code": f.Code, "message": f.Message(lang)}
 			}
-
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"error": errMsg,
-			})
+			problem["errors"] = fields
 		}
+
+		header := c.Writer.Header()
+		header.Set("Content-Type", "application/problem+json")
+		header.Set("Content-Language", lang)
+		header.Add("Vary", "Accept-Language")
+		c.Render(status, render.JSON{Data: problem})
+	}
+}
+
+// errorBodyWriter holds back JSON bodies of error responses until the handler returns
+type errorBodyWriter struct {
+	gin.ResponseWriter
+	body *bytes.Buffer
+}
+
+func (w *errorBodyWriter) Write(data []byte) (int, error) {
+	if !w.holdsBody() {
+		return w.ResponseWriter.Write(data)
+	}
+	if w.body == nil {
+		w.body = &bytes.Buffer{}
+	}
+	return w.body.Write(data)
+}
+
+func (w *errorBodyWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// Written also counts a held body, so middleware does not write a second response
+func (w *errorBodyWriter) Written() bool {
+	return w.body != nil || w.ResponseWriter.Written()
+}
+
+func (w *errorBodyWriter) holdsBody() bool {
+	return w.body != nil || !w.ResponseWriter.Written() && w.Status() >= http.StatusBadRequest &&
+		strings.HasPrefix(w.Header().Get("Content-Type"), "application/json")
+}
+
+// withRequestID adds a request_id field to a JSON object body that lacks one
+func withRequestID(body []byte, id string) []byte {
+	var fields map[string]json.RawMessage
+	if id == "" || json.Unmarshal(body, &fields) != nil || fields == nil {
+		return body
+	}
+	if _, ok := fields["request_id"]; ok {
+		return body
+	}
+	fields["request_id"], _ = json.Marshal(id)
+	updated, err := json.Marshal(fields)
+	if err != nil {
+		return body
 	}
+	return updated
 }
@@ -1,9 +1,11 @@
 package middleware
 
 import (
-	"net/http"
 	"strings"
 
+	"bizgenie-api/internal/apperror"
+	"bizgenie-api/internal/logger"
+
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
 )
@@ -12,7 +14,7 @@ func JWTAuth(secret string) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization header required"})
+			c.Error(apperror.Unauthorized("authorization_required"))
 			c.Abort()
 			return
 		}
@@ -20,7 +22,7 @@ func JWTAuth(secret string) gin.HandlerFunc {
 		// Extract token from "Bearer <token>"
 		parts := strings.Split(authHeader, " ")
 		if len(parts) != 2 || parts[0] != "Bearer" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid authorization header format"})
+			c.Error(apperror.Unauthorized("invalid_authorization_header"))
 			c.Abort()
 			return
 		}
@@ -36,7 +38,7 @@ func JWTAuth(secret string) gin.HandlerFunc {
 		})
 
 		if err != nil || !token.Valid {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired token"})
+			c.Error(apperror.Unauthorized("invalid_token").Wrap(err))
 			c.Abort()
 			return
 		}
@@ -46,6 +48,7 @@ func JWTAuth(secret string) gin.HandlerFunc {
 			c.Set("user_id", claims["user_id"])
 			c.Set("username", claims["username"])
 			c.Set("role", claims["role"])
+			c.Request = c.Request.WithContext(logger.WithFields(c.Request.Context(), "user_id", claims["user_id"]))
 		}
 
 		c.Next()
@@ -0,0 +1,122 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"bizgenie-api/internal/apperror"
+	"bizgenie-api/internal/config"
+	"bizgenie-api/internal/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequestLimits applies the body size, JSON nesting and timeout limits of the route. The
+// body is read up front, so an oversized body is refused with 413 before the handler runs
+// and a deeply nested one with 400 before binding can exhaust the stack. The timeout
+// cancels the request context, which stops the queries and outbound calls made with it.
+// Refusals are left to ErrorHandler, which must run first.
+func RequestLimits(limits config.RequestLimits) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		route := c.Request.Method + " " + c.FullPath()
+
+		timeout := limits.Timeout
+		if d, ok := limits.RouteTimeouts[route]; ok {
+			timeout = d
+		}
+		ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
+		defer cancel()
+		c.Request = c.Request.WithContext(ctx)
+
+		maxBytes := limits.MaxBodyBytes
+		if n, ok := limits.RouteMaxBodyBytes[route]; ok {
+			maxBytes = n
+		}
+		if err := limitBody(c.Request, maxBytes, limits.MaxJSONDepth); err != nil {
+			logger.WarnContext(ctx, "Refused request body: %v", err)
+			c.Error(err)
+			c.Abort()
+			return
+		}
+
+		c.Next()
+
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			logger.WarnContext(ctx, "Request exceeded its %s timeout", timeout)
+			if !c.Writer.Written() {
+				c.Error(apperror.New(apperror.KindTimeout, "timeout").Wrap(ctx.Err()))
+				c.Abort()
+			}
+		}
+	}
+}
+
+// limitBody reads the body of req, at most maxBytes of it, and puts it back for the
+// handler. It returns the error to refuse the request with, or nil.
+func limitBody(req *http.Request, maxBytes int64, maxDepth int) error {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil
+	}
+	tooLarge := apperror.New(apperror.KindPayloadTooLarge, "body_too_large", formatSize(maxBytes))
+	if req.ContentLength > maxBytes {
+		return tooLarge
+	}
+
+	body, err := io.ReadAll(io.LimitReader(req.Body, maxBytes+1))
+	req.Body.Close()
+	if err != nil {
+		return apperror.Validation("unreadable_body").Wrap(err)
+	}
+	if int64(len(body)) > maxBytes {
+		return tooLarge
+	}
+	// Handlers bind JSON whatever the Content-Type, so every body is checked
+	if jsonDepthExceeds(body, maxDepth) {
+		return apperror.Validation("json_too_deep", maxDepth)
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	return nil
+}
+
+// jsonDepthExceeds reports whether objects and arrays in data nest deeper than max. It only
+// tracks brackets outside strings; whether data is valid JSON is left to binding.
+func jsonDepthExceeds(data []byte, max int) bool {
+	depth := 0
+	inString, escaped := false, false
+	for _, b := range data {
+		switch {
+		case escaped:
+			escaped = false
+		case inString:
+			if b == '\\' {
+				escaped = true
+			} else if b == '"' {
+				inString = false
+			}
+		case b == '"':
+			inString = true
+		case b == '{' || b == '[':
+			depth++
+			if depth > max {
+				return true
+			}
+		case b == '}' || b == ']':
+			depth--
+		}
+	}
+	return false
+}
+
+func formatSize(n int64) string {
+	switch {
+	case n%(1<<20) == 0:
+		return fmt.Sprintf("%dMB", n>>20)
+	case n%(1<<10) == 0:
+		return fmt.Sprintf("%dKB", n>>10)
+	}
+	return fmt.Sprintf("%d bytes", n)
+}
@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestJSONDepthExceeds(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		max  int
+		want bool
+	}{
+		{"scalar", `"text"`, 1, false},
+		{"flat object", `{"a": 1, "b": "x"}`, 1, false},
+		{"at the limit", `{"a": [1, {"b": 2}]}`, 3, false},
+		{"over the limit", `{"a": [1, {"b": 2}]}`, 2, true},
+		{"siblings do not add up", `[[1], [2], [3], {"a": 4}]`, 2, false},
+		{"brackets in strings", `{"a": "[[[{{{"}`, 1, false},
+		{"escaped quote in string", `{"a": "\"[[[", "b": "]]]"}`, 1, false},
+		{"escaped backslash ends string", `{"a": "\\", "b": [[1]]}`, 2, true},
+		{"deep array", strings.Repeat("[", 33) + strings.Repeat("]", 33), 32, true},
+		{"empty", ``, 1, false},
+	}
+	for _, tt := range tests {
+		if got := jsonDepthExceeds([]byte(tt.data), tt.max); got != tt.want {
+			t.Errorf("%s: jsonDepthExceeds(%q, %d) = %v, want %v", tt.name, tt.data, tt.max, got, tt.want)
+		}
+	}
+}
+
+func TestFormatSize(t *testing.T) {
+	tests := []struct {
+		n    int64
+		want string
+	}{
+		{20 << 20, "20MB"},
+		{16 << 10, "16KB"},
+		{1536, "1536 bytes"},
+		{1000, "1000 bytes"},
+	}
+	for _, tt := range tests {
+		if got := formatSize(tt.n); got != tt.want {
+			t.Errorf("formatSize(%d) = %q, want %q", tt.n, got, tt.want)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"log/slog"
 	"time"
 
 	"bizgenie-api/internal/logger"
@@ -8,46 +9,44 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-// RequestLogger logs HTTP requests with method, path, status, and duration
+// RequestLogger logs each request as it arrives and completes. The route and method are
+// stored in the request context next to the request ID and client IP, so every line logged
+// with c.Request.Context() while handling it carries them too.
 func RequestLogger() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		start := time.Now()
-		path := c.Request.URL.Path
-		query := c.Request.URL.RawQuery
-		method := c.Request.Method
-		clientIP := c.ClientIP()
-
-		// Build full path
-		fullPath := path
-		if query != "" {
-			fullPath = path + "?" + query
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
 		}
 
-		// Log incoming request (always log)
-		logger.Info("→ %s %s | IP: %s", method, fullPath, clientIP)
+		ctx := logger.WithFields(c.Request.Context(), "route", route, "method", c.Request.Method)
+		c.Request = c.Request.WithContext(ctx)
+
+		path := c.Request.URL.Path
+		if c.Request.URL.RawQuery != "" {
+			path += "?" + c.Request.URL.RawQuery
+		}
+		logger.LogAttrs(ctx, logger.INFO, "→ "+c.Request.Method+" "+path, slog.String("path", path))
 
-		// Process request
 		c.Next()
 
-		// Calculate duration
-		latency := time.Since(start)
-		statusCode := c.Writer.Status()
-		userAgent := c.Request.UserAgent()
-
-		// Log based on status code
-		if statusCode >= 500 {
-			logger.Error("← %s %s | Status: %d | Latency: %v | IP: %s | User-Agent: %s",
-				method, fullPath, statusCode, latency, clientIP, userAgent)
-		} else if statusCode >= 400 {
-			logger.Warn("← %s %s | Status: %d | Latency: %v | IP: %s",
-				method, fullPath, statusCode, latency, clientIP)
-		} else {
-			logger.Info("← %s %s | Status: %d | Latency: %v | IP: %s",
-				method, fullPath, statusCode, latency, clientIP)
+		// Read the context again: authentication adds the user ID to it
+		ctx = c.Request.Context()
+		status := c.Writer.Status()
+		attrs := []slog.Attr{
+			slog.String("path", path),
+			slog.Int("status", status),
+			slog.Float64("latency_ms", float64(time.Since(start).Microseconds())/1000),
 		}
-
-		// Debug log with more details
-		logger.Debug("Request details - Method: %s, Path: %s, Status: %d, Latency: %v, IP: %s, User-Agent: %s",
-			method, fullPath, statusCode, latency, clientIP, userAgent)
+		level := logger.INFO
+		switch {
+		case status >= 500:
+			level = logger.ERROR
+			attrs = append(attrs, slog.String("user_agent", c.Request.UserAgent()))
+		case status >= 400:
+			level = logger.WARN
+		}
+		logger.LogAttrs(ctx, level, "← "+c.Request.Method+" "+path, attrs...)
 	}
 }
@@ -0,0 +1,59 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strconv"
+	"time"
+
+	"bizgenie-api/internal/apperror"
+	"bizgenie-api/internal/metrics"
+
+	"github.com/gin-gonic/gin"
+)
+
+// metricMethods are the HTTP methods recorded under their own name
+var metricMethods = map[string]bool{
+	http.MethodGet: true, http.MethodHead: true, http.MethodPost: true, http.MethodPut: true,
+	http.MethodPatch: true, http.MethodDelete: true, http.MethodOptions: true,
+}
+
+// Metrics records the count and latency of requests by route template, so /api/products/:id
+// is one series however many products there are. Unmatched paths share one series, and so
+// do methods other than the standard ones, since clients can send any token as a method.
+func Metrics() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		method := metricMethod(c.Request.Method)
+		status := strconv.Itoa(c.Writer.Status())
+		metrics.HTTPRequests.Inc(method, route, status)
+		metrics.HTTPRequestDuration.Observe(time.Since(start).Seconds(), method, route, status)
+	}
+}
+
+// metricMethod returns the method label for method: itself when standard, OTHER otherwise
+func metricMethod(method string) string {
+	if metricMethods[method] {
+		return method
+	}
+	return "OTHER"
+}
+
+// MetricsToken requires "Authorization: Bearer <token>" for the metrics endpoint
+func MetricsToken(token string) gin.HandlerFunc {
+	expected := []byte("Bearer " + token)
+	return func(c *gin.Context) {
+		if subtle.ConstantTimeCompare([]byte(c.GetHeader("Authorization")), expected) != 1 {
+			c.Error(apperror.Unauthorized("invalid_metrics_token"))
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
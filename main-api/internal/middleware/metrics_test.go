@@ -0,0 +1,23 @@
+package middleware
+
+import "testing"
+
+func TestMetricMethod(t *testing.T) {
+	tests := []struct {
+		method string
+		want   string
+	}{
+		{"GET", "GET"},
+		{"DELETE", "DELETE"},
+		{"OPTIONS", "OPTIONS"},
+		{"get", "OTHER"},
+		{"PROPFIND", "OTHER"},
+		{"X-RANDOM-1234", "OTHER"},
+		{"", "OTHER"},
+	}
+	for _, tt := range tests {
+		if got := metricMethod(tt.method); got != tt.want {
+			t.Errorf("metricMethod(%q) = %q, want %q", tt.method, got, tt.want)
+		}
+	}
+}
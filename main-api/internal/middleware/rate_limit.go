@@ -0,0 +1,77 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"bizgenie-api/internal/apperror"
+	"bizgenie-api/internal/clientip"
+	"bizgenie-api/internal/config"
+	"bizgenie-api/internal/logger"
+	"bizgenie-api/internal/metrics"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RateLimitStore takes tokens from shared token buckets, see services.RateLimitService
+type RateLimitStore interface {
+	Take(ctx context.Context, key string, capacity int, period time.Duration) (bool, float64, error)
+}
+
+// RateLimit throttles the routes that have a rule, keyed by method and route as registered.
+// Each client gets its own token bucket per route: the signed-in user on routes behind
+// JWTAuth, which must run first, and the client IP elsewhere. Responses carry the
+// RateLimit-* headers of the IETF draft; refused requests get 429 with Retry-After. When
+// the store fails the request is let through, so an outage of the limiter does not take the
+// site down with it.
+func RateLimit(store RateLimitStore, rules map[string]config.RateLimit) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		route := c.Request.Method + " " + c.FullPath()
+		rule, ok := rules[route]
+		if !ok {
+			c.Next()
+			return
+		}
+
+		ctx := c.Request.Context()
+		allowed, remaining, err := store.Take(ctx, route+" "+rateLimitClient(c), rule.Limit, rule.Period)
+		if err != nil {
+			logger.WarnContext(ctx, "Rate limiter unavailable, letting request through: %v", err)
+			c.Next()
+			return
+		}
+
+		// Tokens refill evenly over the period
+		perToken := rule.Period.Seconds() / float64(rule.Limit)
+		header := c.Writer.Header()
+		header.Set("RateLimit-Policy", fmt.Sprintf("%d;w=%d", rule.Limit, int(rule.Period.Seconds())))
+		header.Set("RateLimit-Limit", strconv.Itoa(rule.Limit))
+		header.Set("RateLimit-Remaining", strconv.Itoa(int(remaining)))
+		header.Set("RateLimit-Reset", strconv.Itoa(int(math.Ceil((float64(rule.Limit)-remaining)*perToken))))
+
+		if !allowed {
+			retryAfter := strconv.Itoa(int(math.Ceil((1 - remaining) * perToken)))
+			header.Set("RateLimit-Reset", retryAfter)
+			header.Set("Retry-After", retryAfter)
+			metrics.RateLimited.Inc(c.Request.Method, c.FullPath())
+			logger.WarnContext(ctx, "Rate limit of %d per %s exceeded", rule.Limit, rule.Period)
+			c.Error(apperror.TooManyRequests("rate_limited"))
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// rateLimitClient identifies the client a bucket belongs to: the authenticated user, or
+// the client address. The API issues no API keys, so an X-API-Key header would be an
+// unverified value that clients could change to get fresh buckets; it is not used.
+func rateLimitClient(c *gin.Context) string {
+	if userID, ok := c.Get("user_id"); ok && userID != nil {
+		return fmt.Sprintf("user:%v", userID)
+	}
+	return "ip:" + clientip.FromContext(c.Request.Context())
+}
@@ -0,0 +1,27 @@
+package middleware
+
+import (
+	"bizgenie-api/internal/logger"
+	"bizgenie-api/internal/requestid"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequestID reuses the X-Request-ID sent by the client or the Next.js proxy, or generates
+// one, and echoes it on the response. The ID is stored in the request context for logs,
+// error bodies, background tasks and outbound webhooks.
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(requestid.Header)
+		if !requestid.Valid(id) {
+			id = requestid.New()
+		}
+
+		ctx := requestid.NewContext(c.Request.Context(), id)
+		ctx = logger.WithFields(ctx, "request_id", id)
+		c.Request = c.Request.WithContext(ctx)
+		c.Header(requestid.Header, id)
+
+		c.Next()
+	}
+}
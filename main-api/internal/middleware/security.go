@@ -0,0 +1,39 @@
+package middleware
+
+import (
+	"strconv"
+
+	"bizgenie-api/internal/config"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SecurityHeaders sets the configured security headers on every response. The API only
+// serves JSON, so the default content security policy forbids loading or framing anything.
+func SecurityHeaders(cfg config.SecurityHeaders) gin.HandlerFunc {
+	hsts := ""
+	if seconds := int64(cfg.HSTSMaxAge.Seconds()); seconds > 0 {
+		hsts = "max-age=" + strconv.FormatInt(seconds, 10)
+		if cfg.HSTSIncludeSubdomains {
+			hsts += "; includeSubDomains"
+		}
+	}
+
+	return func(c *gin.Context) {
+		header := c.Writer.Header()
+		header.Set("X-Content-Type-Options", "nosniff")
+		if hsts != "" {
+			header.Set("Strict-Transport-Security", hsts)
+		}
+		if cfg.FrameOptions != "" {
+			header.Set("X-Frame-Options", cfg.FrameOptions)
+		}
+		if cfg.ReferrerPolicy != "" {
+			header.Set("Referrer-Policy", cfg.ReferrerPolicy)
+		}
+		if cfg.ContentSecurityPolicy != "" {
+			header.Set("Content-Security-Policy", cfg.ContentSecurityPolicy)
+		}
+		c.Next()
+	}
+}
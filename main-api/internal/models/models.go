@@ -49,6 +49,34 @@ type Product struct {
 	Category        *Category      `json:"category,omitempty"`
 }
 
+// ProductFilter narrows product listings. Values of one specification key are
+// alternatives (OR); different keys and all features must match (AND).
+type ProductFilter struct {
+	Status     string
+	CategoryID *int
+	Specs      map[string][]string
+	Features   []string
+}
+
+// ProductFacetValue represents the number of products having one attribute value
+type ProductFacetValue struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
+
+// ProductSpecFacet represents the values of one specification key
+type ProductSpecFacet struct {
+	Key    string              `json:"key"`
+	Values []ProductFacetValue `json:"values"`
+}
+
+// ProductFacets represents the filterable attributes of the products matching a filter
+type ProductFacets struct {
+	Total          int                 `json:"total"`
+	Specifications []ProductSpecFacet  `json:"specifications"`
+	Features       []ProductFacetValue `json:"features"`
+}
+
 // ProductImage represents a product image
 type ProductImage struct {
 	ID        int       `json:"id" db:"id"`
@@ -163,6 +191,7 @@ type Contact struct {
 	Company   *string   `json:"company,omitempty" db:"company"`
 	Message   string    `json:"message" db:"message"`
 	Status    string    `json:"status" db:"status"`
+	ClientIP  *string   `json:"client_ip,omitempty" db:"client_ip"` // Address the request came from
 	CreatedAt time.Time `json:"created_at" db:"created_at"`
 }
 
@@ -247,3 +276,255 @@ func (j *JSONB) UnmarshalJSON(data []byte) error {
 	*j = JSONB(data)
 	return nil
 }
+
+// SearchHit represents a single result of the site-wide search
+type SearchHit struct {
+	Type         string  `json:"type"` // 'product', 'blog', 'video' or 'category'
+	ID           int     `json:"id"`
+	Title        string  `json:"title"`
+	Excerpt      string  `json:"excerpt"`
+	URL          string  `json:"url"`
+	Score        float64 `json:"score"`
+	CategorySlug *string `json:"category_slug,omitempty"`
+	CategoryName *string `json:"category_name,omitempty"`
+}
+
+// SearchFacet represents the number of hits for one facet value
+type SearchFacet struct {
+	Value string `json:"value"`
+	Label string `json:"label"`
+	Count int    `json:"count"`
+}
+
+// SearchResult represents a page of site-wide search results with facets
+type SearchResult struct {
+	SearchID *int64                   `json:"search_id,omitempty"` // Used to attribute click-through events
+	Query    string                   `json:"query"`
+	Total    int                      `json:"total"`
+	Limit    int                      `json:"limit"`
+	Offset   int                      `json:"offset"`
+	Hits     []SearchHit              `json:"hits"`
+	Facets   map[string][]SearchFacet `json:"facets"`
+}
+
+// SearchQueryStat represents aggregated statistics for one normalized search query
+type SearchQueryStat struct {
+	Query            string  `json:"query"`
+	Searches         int     `json:"searches"`
+	AvgResults       float64 `json:"avg_results"`
+	AvgLatencyMs     float64 `json:"avg_latency_ms"`
+	Clicks           int     `json:"clicks"`
+	ClickThroughRate float64 `json:"click_through_rate"`
+}
+
+// SearchAnalytics represents the search analytics report for a date range
+type SearchAnalytics struct {
+	From              time.Time         `json:"from"`
+	To                time.Time         `json:"to"`
+	TotalSearches     int               `json:"total_searches"`
+	SearchesWithClick int               `json:"searches_with_click"`
+	ClickThroughRate  float64           `json:"click_through_rate"`
+	ZeroResultRate    float64           `json:"zero_result_rate"`
+	TopQueries        []SearchQueryStat `json:"top_queries"`
+	ZeroResultQueries []SearchQueryStat `json:"zero_result_queries"`
+}
+
+// VectorIndex represents an ANN index on the embedding column of a table
+type VectorIndex struct {
+	Name       string `json:"name"`
+	Method     string `json:"method"` // 'hnsw' or 'ivfflat'
+	Definition string `json:"definition"`
+	SizeBytes  int64  `json:"size_bytes"`
+	Valid      bool   `json:"valid"`
+}
+
+// VectorIndexStatus represents the state of the embedding indexes of a table
+type VectorIndexStatus struct {
+	Table        string        `json:"table"` // 'products' or 'content_chunks'
+	EmbeddedRows int           `json:"embedded_rows"`
+	TotalRows    int           `json:"total_rows"`
+	Indexes      []VectorIndex `json:"indexes"`
+}
+
+// VectorIndexRecall represents the recall of the ANN index measured against exact search
+type VectorIndexRecall struct {
+	Table      string  `json:"table"`
+	SampleSize int     `json:"sample_size"`
+	K          int     `json:"k"`
+	Recall     float64 `json:"recall"`
+	EfSearch   int     `json:"ef_search,omitempty"`
+	Probes     int     `json:"probes,omitempty"`
+}
+
+// RelatedContent represents recommendations shown next to a product or blog post
+type RelatedContent struct {
+	Products  []Product  `json:"products"`
+	BlogPosts []BlogPost `json:"blog_posts"`
+}
+
+// FAQ represents a frequently asked question
+type FAQ struct {
+	ID        int       `json:"id" db:"id"`
+	Question  string    `json:"question" db:"question"`
+	Answer    string    `json:"answer" db:"answer"`
+	Status    string    `json:"status" db:"status"`
+	Order     int       `json:"order" db:"order"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// AssistantSource represents a piece of content retrieved to answer a question
+type AssistantSource struct {
+	Type    string  `json:"type"` // 'product', 'blog' or 'faq'
+	ID      int     `json:"id"`
+	Title   string  `json:"title"`
+	URL     string  `json:"url"`
+	Score   float64 `json:"score"`
+	Section *string `json:"section,omitempty"`
+	Snippet string  `json:"snippet"`
+	Content string  `json:"-"`
+}
+
+// PassageMatch represents a chunk of a product or blog post matched by semantic search
+type PassageMatch struct {
+	Type        string  `json:"type"` // 'product' or 'blog'
+	ID          int     `json:"id"`
+	Title       string  `json:"title"`
+	URL         string  `json:"url"`
+	Section     *string `json:"section,omitempty"`
+	Snippet     string  `json:"snippet"`
+	StartOffset int     `json:"start_offset"` // Character offsets into the cleaned source text
+	EndOffset   int     `json:"end_offset"`
+	Score       float64 `json:"score"`
+}
+
+// AssistantConversation represents one question answered by the assistant
+type AssistantConversation struct {
+	ID        int64             `json:"id" db:"id"`
+	Question  string            `json:"question" db:"question"`
+	Answer    string            `json:"answer" db:"answer"`
+	Sources   []AssistantSource `json:"sources" db:"sources"`
+	Status    string            `json:"status" db:"status"` // 'pending' while answering, then 'completed' or 'failed'
+	Error     *string           `json:"error,omitempty" db:"error"`
+	Provider  string            `json:"provider" db:"provider"`
+	Model     string            `json:"model" db:"model"`
+	LatencyMs int               `json:"latency_ms" db:"latency_ms"`
+	CreatedAt time.Time         `json:"created_at" db:"created_at"`
+}
+
+// ContentBundle represents content exported from one environment for import into another.
+// Rows reference each other by slug or username instead of database IDs.
+type ContentBundle struct {
+	Version          int                     `json:"version"`
+	ExportedAt       time.Time               `json:"exported_at"`
+	Categories       []BundleCategory        `json:"categories,omitempty"`
+	Products         []BundleProduct         `json:"products,omitempty"`
+	BlogCategories   []BundleBlogCategory    `json:"blog_categories,omitempty"`
+	BlogPosts        []BundleBlogPost        `json:"blog_posts,omitempty"`
+	VideoDemos       []BundleVideoDemo       `json:"video_demos,omitempty"`
+	SocialMediaLinks []BundleSocialMediaLink `json:"social_media_links,omitempty"`
+}
+
+// BundleCategory represents a product category in a content bundle
+type BundleCategory struct {
+	Slug        string  `json:"slug"`
+	Name        string  `json:"name"`
+	Description *string `json:"description,omitempty"`
+	ParentSlug  *string `json:"parent_slug,omitempty"`
+	Order       int     `json:"order"`
+}
+
+// BundleProduct represents a product in a content bundle
+type BundleProduct struct {
+	Slug             string  `json:"slug"`
+	Name             string  `json:"name"`
+	ShortDescription *string `json:"short_description,omitempty"`
+	Description      *string `json:"description,omitempty"`
+	CategorySlug     string  `json:"category_slug"`
+	ImageURLs        JSONB   `json:"image_urls,omitempty"`
+	Features         JSONB   `json:"features,omitempty"`
+	Specifications   JSONB   `json:"specifications,omitempty"`
+	Status           string  `json:"status"`
+}
+
+// BundleBlogCategory represents a blog category in a content bundle
+type BundleBlogCategory struct {
+	Slug        string  `json:"slug"`
+	Name        string  `json:"name"`
+	Description *string `json:"description,omitempty"`
+	Order       int     `json:"order"`
+}
+
+// BundleBlogPost represents a blog post in a content bundle
+type BundleBlogPost struct {
+	Slug           string        `json:"slug"`
+	Title          string        `json:"title"`
+	Excerpt        *string       `json:"excerpt,omitempty"`
+	Content        string        `json:"content"`
+	FeaturedImage  *string       `json:"featured_image,omitempty"`
+	AuthorUsername string        `json:"author_username"`
+	CategorySlug   *string       `json:"category_slug,omitempty"`
+	Status         string        `json:"status"`
+	PublishedAt    *FlexibleTime `json:"published_at,omitempty"`
+}
+
+// BundleVideoDemo represents a video demo in a content bundle, keyed by its video URL
+type BundleVideoDemo struct {
+	VideoURL     string  `json:"video_url"`
+	Title        string  `json:"title"`
+	Description  *string `json:"description,omitempty"`
+	VideoType    string  `json:"video_type"`
+	YouTubeID    *string `json:"youtube_id,omitempty"`
+	ThumbnailURL *string `json:"thumbnail_url,omitempty"`
+	Status       string  `json:"status"`
+	Order        int     `json:"order"`
+}
+
+// BundleSocialMediaLink represents a social media link in a content bundle, keyed by platform
+type BundleSocialMediaLink struct {
+	Platform string  `json:"platform"`
+	URL      string  `json:"url"`
+	IconName *string `json:"icon_name,omitempty"`
+	Order    int     `json:"order"`
+	IsActive bool    `json:"is_active"`
+}
+
+// BundleChange represents what an import does to one item of a bundle
+type BundleChange struct {
+	Type   string   `json:"type"`   // Bundle section, e.g. 'products'
+	Key    string   `json:"key"`    // Slug, video URL or platform
+	Action string   `json:"action"` // 'create', 'update' or 'unchanged'
+	Fields []string `json:"fields,omitempty"` // Fields an update changes
+}
+
+// BundleImportResult represents the outcome or, for a dry run, the preview of an import
+type BundleImportResult struct {
+	DryRun    bool           `json:"dry_run"`
+	Created   int            `json:"created"`
+	Updated   int            `json:"updated"`
+	Unchanged int            `json:"unchanged"`
+	Changes   []BundleChange `json:"changes"`
+	// Products and blog posts written, whose chunks need refreshing
+	ProductIDs  []int `json:"-"`
+	BlogPostIDs []int `json:"-"`
+}
+
+// HealthComponent represents the result of checking one dependency
+type HealthComponent struct {
+	Status    string `json:"status"`   // 'ok', 'fail' or 'skipped'
+	Critical  bool   `json:"critical"` // A failing critical component makes the replica not ready
+	LatencyMs int64  `json:"latency_ms"`
+}
+
+// HealthReport represents the readiness of a replica
+type HealthReport struct {
+	Status     string                     `json:"status"` // 'ok', 'degraded', 'fail' or 'shutting_down'
+	Components map[string]HealthComponent `json:"components,omitempty"`
+}
+
+// LogLevels represents the default log level and the per-package overrides, keyed by the
+// last element of the package import path such as "services"
+type LogLevels struct {
+	Default  string            `json:"default"`
+	Packages map[string]string `json:"packages"`
+}
@@ -0,0 +1,459 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"bizgenie-api/internal/apperror"
+	"bizgenie-api/internal/logger"
+	"bizgenie-api/internal/models"
+
+	"github.com/pgvector/pgvector-go"
+)
+
+// ErrAssistantQuotaExceeded is returned when a client has used up its hourly questions
+var ErrAssistantQuotaExceeded = apperror.TooManyRequests("assistant_quota_exceeded")
+
+// ErrAssistantDisabled is returned by everything that needs a chat or embedding provider
+// when none is configured
+var ErrAssistantDisabled = apperror.Unavailable("assistant_disabled")
+
+const (
+	// maxSourceRunes bounds how much of each retrieved FAQ goes into the prompt
+	maxSourceRunes = 1500
+	// maxPassagesPerSource bounds how many chunks of one product or blog post are retrieved
+	maxPassagesPerSource = 2
+	// maxEmbeddingRunes bounds the text sent to the embedding provider
+	maxEmbeddingRunes = 8000
+)
+
+const assistantSystemPrompt = `You are the BizGenie website assistant.
+Answer the question using only the numbered sources provided by the user.
+Cite the sources you use inline as [n]. If the sources do not contain the answer,
+say that you do not know and suggest contacting the BizGenie team.
+Answer in the same language as the question.`
+
+// AssistantOptions tunes retrieval and quotas of the assistant
+type AssistantOptions struct {
+	TopK        int
+	HourlyQuota int // Questions per client per hour, 0 disables the quota
+	EfSearch    int
+	Probes      int
+}
+
+type AssistantService struct {
+	db       *sql.DB
+	chat     ChatProvider
+	embedder EmbeddingProvider
+	chunks   *ChunkService
+	opts     AssistantOptions
+}
+
+func NewAssistantService(db *sql.DB, chat ChatProvider, embedder EmbeddingProvider, chunks *ChunkService, opts AssistantOptions) *AssistantService {
+	if opts.TopK <= 0 {
+		opts.TopK = 5
+	}
+	return &AssistantService{db: db, chat: chat, embedder: embedder, chunks: chunks, opts: opts}
+}
+
+// Enabled reports whether providers are configured. A disabled assistant answers no
+// questions and computes no embeddings; everything else keeps working.
+func (s *AssistantService) Enabled() bool {
+	return s.chat != nil && s.embedder != nil
+}
+
+// HashClient returns the identifier stored for a client instead of its raw IP address
+func HashClient(clientIP string) string {
+	sum := sha256.Sum256([]byte(clientIP))
+	return hex.EncodeToString(sum[:])
+}
+
+// Reserve logs the question as a pending conversation to be answered by Ask. Checking the
+// quota and logging happen in one transaction holding a lock on the client, so concurrent
+// questions from one client cannot all pass the check before any of them is logged.
+// Returns ErrAssistantQuotaExceeded if the client asked too many questions in the last hour.
+func (s *AssistantService) Reserve(ctx context.Context, clientIP, question string) (*models.AssistantConversation, error) {
+	if !s.Enabled() {
+		return nil, ErrAssistantDisabled
+	}
+	clientHash := HashClient(clientIP)
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if s.opts.HourlyQuota > 0 {
+		// Held until commit, on every replica
+		if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock(hashtext($1))`, clientHash); err != nil {
+			return nil, err
+		}
+
+		var count int
+		query := `SELECT COUNT(*) FROM assistant_conversations WHERE client_hash = $1 AND created_at > NOW() - INTERVAL '1 hour'`
+		if err := tx.QueryRowContext(ctx, query, clientHash).Scan(&count); err != nil {
+			return nil, err
+		}
+		if count >= s.opts.HourlyQuota {
+			return nil, ErrAssistantQuotaExceeded
+		}
+	}
+
+	conv := &models.AssistantConversation{
+		Question: question,
+		Sources:  []models.AssistantSource{},
+		Status:   "pending",
+		Provider: s.chat.Name(),
+		Model:    s.chat.Model(),
+	}
+	query := `
+		INSERT INTO assistant_conversations (client_hash, question, status, provider, model)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at
+	`
+	err = tx.QueryRowContext(ctx, query, clientHash, conv.Question, conv.Status, conv.Provider, conv.Model).
+		Scan(&conv.ID, &conv.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return conv, tx.Commit()
+}
+
+// Retrieve returns the passages of published products and blog posts and the
+// published FAQs closest to the question
+func (s *AssistantService) Retrieve(ctx context.Context, question string) ([]models.AssistantSource, error) {
+	embedding, err := s.embedder.Embed(ctx, question)
+	if err != nil {
+		return nil, fmt.Errorf("failed to embed question: %w", err)
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if err := applyVectorSettings(ctx, tx, s.opts.EfSearch, s.opts.Probes); err != nil {
+		return nil, err
+	}
+
+	// Allow a couple of passages per document so one long post cannot crowd out the rest
+	passages, err := s.chunks.MatchPassages(ctx, tx, embedding, nil, maxPassagesPerSource, s.opts.TopK)
+	if err != nil {
+		return nil, err
+	}
+
+	sources := []models.AssistantSource{}
+	for _, p := range passages {
+		sources = append(sources, models.AssistantSource{
+			Type:    p.Type,
+			ID:      p.ID,
+			Title:   p.Title,
+			URL:     p.URL,
+			Score:   p.Score,
+			Section: p.Section,
+			Snippet: p.Snippet,
+			Content: p.Snippet,
+		})
+	}
+
+	query := `
+		SELECT id, question, answer, '/faq#faq-' || id, 1 - (embedding <=> $1::vector) AS score
+		FROM faqs
+		WHERE status = 'published' AND embedding IS NOT NULL
+		  AND 1 - (embedding <=> $1::vector) > 0
+		ORDER BY embedding <=> $1::vector
+		LIMIT $2
+	`
+
+	rows, err := tx.QueryContext(ctx, query, pgvector.NewVector(embedding), s.opts.TopK)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		src := models.AssistantSource{Type: "faq"}
+		if err := rows.Scan(&src.ID, &src.Title, &src.Content, &src.URL, &src.Score); err != nil {
+			return nil, err
+		}
+		src.Content = truncateRunes(strings.TrimSpace(src.Content), maxSourceRunes)
+		src.Snippet = src.Content
+		sources = append(sources, src)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(sources, func(i, j int) bool { return sources[i].Score > sources[j].Score })
+	if len(sources) > s.opts.TopK {
+		sources = sources[:s.opts.TopK]
+	}
+
+	return sources, nil
+}
+
+// Ask answers a conversation returned by Reserve from retrieved content. onSources is
+// called once before the answer is generated and onDelta for every streamed piece of the
+// answer. The outcome is logged on the conversation whether or not answering succeeds.
+func (s *AssistantService) Ask(ctx context.Context, conv *models.AssistantConversation, onSources func([]models.AssistantSource) error, onDelta func(string) error) error {
+	start := time.Now()
+	answer, err := s.answer(ctx, conv, conv.Question, onSources, onDelta)
+	conv.Answer = answer
+	conv.LatencyMs = int(time.Since(start).Milliseconds())
+	conv.Status = "completed"
+	if err != nil {
+		conv.Status = "failed"
+		errMsg := err.Error()
+		conv.Error = &errMsg
+	}
+
+	// Logged without the request context so a client disconnect does not drop the record
+	if logErr := s.logConversation(context.WithoutCancel(ctx), conv); logErr != nil {
+		logger.ErrorWithErrContext(ctx, "Failed to log assistant conversation", logErr)
+	}
+
+	return err
+}
+
+func (s *AssistantService) answer(ctx context.Context, conv *models.AssistantConversation, question string, onSources func([]models.AssistantSource) error, onDelta func(string) error) (string, error) {
+	sources, err := s.Retrieve(ctx, question)
+	if err != nil {
+		return "", err
+	}
+	conv.Sources = sources
+
+	if err := onSources(sources); err != nil {
+		return "", err
+	}
+
+	var prompt strings.Builder
+	prompt.WriteString("Sources:")
+	for i, src := range sources {
+		fmt.Fprintf(&prompt, "\n\n[%d] %s\n%s", i+1, src.Title, src.Content)
+	}
+	fmt.Fprintf(&prompt, "\n\nQuestion: %s", question)
+
+	messages := []ChatMessage{
+		{Role: "system", Content: assistantSystemPrompt},
+		{Role: "user", Content: prompt.String()},
+	}
+
+	return s.chat.StreamChat(ctx, messages, onDelta)
+}
+
+func (s *AssistantService) logConversation(ctx context.Context, conv *models.AssistantConversation) error {
+	sources, err := json.Marshal(conv.Sources)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE assistant_conversations
+		SET answer = $2, sources = $3, status = $4, error = $5, latency_ms = $6
+		WHERE id = $1
+	`
+	_, err = s.db.ExecContext(ctx, query, conv.ID, conv.Answer, string(sources), conv.Status, conv.Error, conv.LatencyMs)
+	return err
+}
+
+// GetConversations returns logged conversations, newest first
+func (s *AssistantService) GetConversations(ctx context.Context, status string, limit, offset int) ([]models.AssistantConversation, error) {
+	query := `
+		SELECT id, question, answer, sources, status, error, provider, model, latency_ms, created_at
+		FROM assistant_conversations
+		WHERE ($1 = '' OR status = $1)
+		ORDER BY created_at DESC
+		LIMIT $2 OFFSET $3
+	`
+
+	rows, err := s.db.QueryContext(ctx, query, status, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	conversations := []models.AssistantConversation{}
+	for rows.Next() {
+		conv, err := scanConversation(rows)
+		if err != nil {
+			return nil, err
+		}
+		conversations = append(conversations, *conv)
+	}
+
+	return conversations, rows.Err()
+}
+
+// GetConversationByID returns a single logged conversation
+func (s *AssistantService) GetConversationByID(ctx context.Context, id int64) (*models.AssistantConversation, error) {
+	query := `
+		SELECT id, question, answer, sources, status, error, provider, model, latency_ms, created_at
+		FROM assistant_conversations
+		WHERE id = $1
+	`
+	conv, err := scanConversation(s.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		return nil, notFound(err, "conversation_not_found")
+	}
+	return conv, nil
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanConversation(row rowScanner) (*models.AssistantConversation, error) {
+	var conv models.AssistantConversation
+	var sources []byte
+
+	err := row.Scan(
+		&conv.ID, &conv.Question, &conv.Answer, &sources, &conv.Status, &conv.Error,
+		&conv.Provider, &conv.Model, &conv.LatencyMs, &conv.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	conv.Sources = []models.AssistantSource{}
+	if len(sources) > 0 {
+		if err := json.Unmarshal(sources, &conv.Sources); err != nil {
+			return nil, err
+		}
+	}
+
+	return &conv, nil
+}
+
+// embeddingSources describes how to build the whole-document embedding of each table.
+// Products keep one for similarity and recommendations; blog posts are only embedded per chunk.
+// generated selects the rows whose embedding the assistant owns: a product embedding an
+// admin supplied is kept as is.
+var embeddingSources = map[string]struct {
+	table     string
+	text      string
+	generated string
+}{
+	"product": {
+		table:     "products",
+		text:      `name || E'\n' || COALESCE(short_description, '') || E'\n' || COALESCE(description, '') || E'\n' || COALESCE(features::text, '')`,
+		generated: `NOT embedding_manual`,
+	},
+	"faq": {
+		table:     "faqs",
+		text:      `question || E'\n' || answer`,
+		generated: `TRUE`,
+	},
+}
+
+// RefreshEmbedding recomputes the embedding of one product or FAQ. A product whose
+// embedding was supplied by an admin is left alone.
+func (s *AssistantService) RefreshEmbedding(ctx context.Context, kind string, id int) error {
+	if !s.Enabled() {
+		return ErrAssistantDisabled
+	}
+	src, ok := embeddingSources[kind]
+	if !ok {
+		return fmt.Errorf("unknown content type: %s", kind)
+	}
+
+	var text string
+	var generated bool
+	query := fmt.Sprintf(`SELECT %s, %s FROM %s WHERE id = $1`, src.text, src.generated, src.table)
+	if err := s.db.QueryRowContext(ctx, query, id).Scan(&text, &generated); err != nil {
+		return err
+	}
+	if !generated {
+		return nil
+	}
+
+	embedding, err := s.embedder.Embed(ctx, truncateRunes(text, maxEmbeddingRunes))
+	if err != nil {
+		return err
+	}
+
+	// Checked again in case an admin set the embedding while this one was computed
+	update := fmt.Sprintf(`UPDATE %s SET embedding = $2 WHERE id = $1 AND %s`, src.table, src.generated)
+	_, err = s.db.ExecContext(ctx, update, id, pgvector.NewVector(embedding))
+	return err
+}
+
+// EmbeddingBacklog returns the number of rows still waiting for an embedding per content
+// type, including content chunks
+func (s *AssistantService) EmbeddingBacklog(ctx context.Context) (map[string]int, error) {
+	tables := map[string]string{"chunk": "content_chunks"}
+	for kind, src := range embeddingSources {
+		tables[kind] = src.table
+	}
+
+	backlog := map[string]int{}
+	for kind, table := range tables {
+		var count int
+		query := fmt.Sprintf(`SELECT COUNT(*) FROM %s WHERE embedding IS NULL`, table)
+		if err := s.db.QueryRowContext(ctx, query).Scan(&count); err != nil {
+			return backlog, err
+		}
+		backlog[kind] = count
+	}
+	return backlog, nil
+}
+
+// EmbedMissing computes embeddings for rows that have none, or for every row when all is true.
+// Product embeddings supplied by an admin are kept either way. Returns the number of rows
+// embedded per content type.
+func (s *AssistantService) EmbedMissing(ctx context.Context, all bool) (map[string]int, error) {
+	counts := map[string]int{}
+	if !s.Enabled() {
+		return counts, ErrAssistantDisabled
+	}
+
+	for kind, src := range embeddingSources {
+		query := fmt.Sprintf(`SELECT id FROM %s WHERE %s`, src.table, src.generated)
+		if !all {
+			query += " AND embedding IS NULL"
+		}
+
+		rows, err := s.db.QueryContext(ctx, query)
+		if err != nil {
+			return counts, err
+		}
+
+		var ids []int
+		for rows.Next() {
+			var id int
+			if err := rows.Scan(&id); err != nil {
+				rows.Close()
+				return counts, err
+			}
+			ids = append(ids, id)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return counts, err
+		}
+
+		for _, id := range ids {
+			if err := s.RefreshEmbedding(ctx, kind, id); err != nil {
+				return counts, fmt.Errorf("failed to embed %s %d: %w", kind, id, err)
+			}
+			counts[kind]++
+		}
+	}
+
+	return counts, nil
+}
+
+func truncateRunes(s string, n int) string {
+	if runes := []rune(s); len(runes) > n {
+		return string(runes[:n])
+	}
+	return s
+}
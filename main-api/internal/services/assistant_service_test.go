@@ -0,0 +1,170 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"bizgenie-api/internal/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func newFakeAssistant(t *testing.T, opts AssistantOptions) (*AssistantService, sqlmock.Sqlmock) {
+	t.Helper()
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	fake := &FakeLLM{}
+	return NewAssistantService(db, fake, fake, NewChunkService(db, fake), opts), mock
+}
+
+func TestAssistantReserve(t *testing.T) {
+	clientHash := HashClient("203.0.113.7")
+
+	t.Run("within quota", func(t *testing.T) {
+		assistant, mock := newFakeAssistant(t, AssistantOptions{HourlyQuota: 2})
+		mock.ExpectBegin()
+		mock.ExpectExec(`SELECT pg_advisory_xact_lock`).WithArgs(clientHash).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery(`SELECT COUNT\(\*\) FROM assistant_conversations`).WithArgs(clientHash).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+		mock.ExpectQuery(`INSERT INTO assistant_conversations`).WithArgs(clientHash, "Bảo hành bao lâu?", "pending", "fake", "fake").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(7, time.Now()))
+		mock.ExpectCommit()
+
+		conv, err := assistant.Reserve(context.Background(), "203.0.113.7", "Bảo hành bao lâu?")
+		if err != nil {
+			t.Fatalf("Reserve: %v", err)
+		}
+		if conv.ID != 7 || conv.Status != "pending" {
+			t.Errorf("Reserve = id %d status %q, want id 7 status pending", conv.ID, conv.Status)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Error(err)
+		}
+	})
+
+	t.Run("quota used up", func(t *testing.T) {
+		assistant, mock := newFakeAssistant(t, AssistantOptions{HourlyQuota: 2})
+		mock.ExpectBegin()
+		mock.ExpectExec(`SELECT pg_advisory_xact_lock`).WithArgs(clientHash).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery(`SELECT COUNT\(\*\) FROM assistant_conversations`).WithArgs(clientHash).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+		mock.ExpectRollback()
+
+		if _, err := assistant.Reserve(context.Background(), "203.0.113.7", "Bảo hành bao lâu?"); !errors.Is(err, ErrAssistantQuotaExceeded) {
+			t.Fatalf("Reserve error = %v, want ErrAssistantQuotaExceeded", err)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Error(err)
+		}
+	})
+
+	t.Run("assistant disabled", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer db.Close()
+		chunks := NewChunkService(db, nil)
+		assistant := NewAssistantService(db, nil, nil, chunks, AssistantOptions{HourlyQuota: 2})
+
+		if _, err := assistant.Reserve(context.Background(), "203.0.113.7", "Bảo hành bao lâu?"); !errors.Is(err, ErrAssistantDisabled) {
+			t.Fatalf("Reserve error = %v, want ErrAssistantDisabled", err)
+		}
+		if _, err := chunks.Sync(context.Background(), "product", 1, false); !errors.Is(err, ErrAssistantDisabled) {
+			t.Fatalf("Sync error = %v, want ErrAssistantDisabled", err)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Error(err)
+		}
+	})
+}
+
+func TestAssistantAskWithFakeProvider(t *testing.T) {
+	assistant, mock := newFakeAssistant(t, AssistantOptions{TopK: 3})
+	section := "Tính năng"
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`FROM content_chunks`).WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), maxPassagesPerSource, 3, maxPassagesPerSource*3).
+		WillReturnRows(sqlmock.NewRows([]string{"source_type", "source_id", "title", "url", "section", "content", "start_offset", "end_offset", "score"}).
+			AddRow("product", 1, "Máy lọc nước RO", "/products/may-loc-nuoc-ro", section, "Máy lọc nước RO 10 lõi. Lọc sạch vi khuẩn.", 0, 42, 0.82).
+			AddRow("blog", 4, "Cách chọn máy lọc nước", "/blog/cach-chon-may-loc-nuoc", nil, "Nên chọn máy theo nguồn nước.", 0, 29, 0.55))
+	mock.ExpectQuery(`FROM faqs`).WithArgs(sqlmock.AnyArg(), 3).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "question", "answer", "url", "score"}).
+			AddRow(9, "Bảo hành bao lâu?", "  Bảo hành 24 tháng tại nhà.  ", "/faq#faq-9", 0.7))
+	mock.ExpectRollback()
+
+	wantAnswer := "Máy lọc nước RO 10 lõi. [1] Bảo hành 24 tháng tại nhà. [2]"
+	mock.ExpectExec(`UPDATE assistant_conversations`).
+		WithArgs(int64(7), wantAnswer, sqlmock.AnyArg(), "completed", sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	conv := &models.AssistantConversation{ID: 7, Question: "Máy lọc nước bảo hành bao lâu?", Status: "pending"}
+	var sources []models.AssistantSource
+	var streamed strings.Builder
+	err := assistant.Ask(context.Background(), conv,
+		func(s []models.AssistantSource) error { sources = s; return nil },
+		func(delta string) error { streamed.WriteString(delta); return nil },
+	)
+	if err != nil {
+		t.Fatalf("Ask: %v", err)
+	}
+
+	// Passages and FAQs are merged by score
+	var got []string
+	for _, src := range sources {
+		got = append(got, src.URL)
+	}
+	want := []string{"/products/may-loc-nuoc-ro", "/faq#faq-9", "/blog/cach-chon-may-loc-nuoc"}
+	if strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Errorf("sources = %v, want %v", got, want)
+	}
+	if sources[0].Section == nil || *sources[0].Section != section {
+		t.Errorf("first source section = %v, want %q", sources[0].Section, section)
+	}
+	if sources[1].Content != "Bảo hành 24 tháng tại nhà." {
+		t.Errorf("FAQ content = %q, want it trimmed", sources[1].Content)
+	}
+
+	if conv.Answer != wantAnswer || streamed.String() != wantAnswer {
+		t.Errorf("answer = %q, streamed %q, want %q", conv.Answer, streamed.String(), wantAnswer)
+	}
+	if conv.Status != "completed" || conv.Error != nil {
+		t.Errorf("status = %q error = %v, want completed without error", conv.Status, conv.Error)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestAssistantAskLogsFailure(t *testing.T) {
+	assistant, mock := newFakeAssistant(t, AssistantOptions{TopK: 3})
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`FROM content_chunks`).WillReturnError(errors.New("connection reset"))
+	mock.ExpectRollback()
+	mock.ExpectExec(`UPDATE assistant_conversations`).
+		WithArgs(int64(8), "", sqlmock.AnyArg(), "failed", sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	conv := &models.AssistantConversation{ID: 8, Question: "Giá bao nhiêu?", Status: "pending"}
+	err := assistant.Ask(context.Background(), conv,
+		func([]models.AssistantSource) error { t.Error("sources sent after retrieval failed"); return nil },
+		func(string) error { t.Error("answer streamed after retrieval failed"); return nil },
+	)
+	if err == nil {
+		t.Fatal("Ask succeeded, want the retrieval error")
+	}
+	if conv.Status != "failed" || conv.Error == nil || !strings.Contains(*conv.Error, "connection reset") {
+		t.Errorf("status = %q error = %v, want failed with the cause", conv.Status, conv.Error)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
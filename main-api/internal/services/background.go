@@ -0,0 +1,44 @@
+package services
+
+import (
+	"context"
+	"sync"
+
+	"bizgenie-api/internal/metrics"
+)
+
+// BackgroundTasks tracks goroutines that outlive the request that started them, such as
+// notifications and embedding refreshes, so shutdown can wait for them to finish
+type BackgroundTasks struct {
+	wg sync.WaitGroup
+}
+
+// Go runs task in a new goroutine tracked until it returns
+func (t *BackgroundTasks) Go(task func()) {
+	t.wg.Add(1)
+	metrics.BackgroundTasksInFlight.Add(1)
+	go func() {
+		defer t.wg.Done()
+		defer func() {
+			metrics.BackgroundTasksInFlight.Add(-1)
+			metrics.BackgroundTasksCompleted.Inc()
+		}()
+		task()
+	}()
+}
+
+// Wait blocks until every tracked task has returned or ctx is done
+func (t *BackgroundTasks) Wait(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		t.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
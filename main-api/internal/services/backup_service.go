@@ -0,0 +1,702 @@
+package services
+
+import (
+	"compress/gzip"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"bizgenie-api/internal/logger"
+	"bizgenie-api/internal/metrics"
+
+	"github.com/lib/pq"
+)
+
+const (
+	// BackupFormat identifies backup archives; BackupFormatVersion changes with the archive layout
+	BackupFormat        = "bizgenie-backup"
+	BackupFormatVersion = 1
+
+	// Conflict policies for rows of the archive whose key already exists
+	ConflictFail      = "fail"
+	ConflictSkip      = "skip"
+	ConflictOverwrite = "overwrite"
+
+	backupFilePrefix = "bizgenie-backup-"
+	backupFileSuffix = ".jsonl.gz"
+
+	// backupLockKey keeps API replicas from taking the same scheduled backup
+	backupLockKey = 727002
+)
+
+// backupTable describes how one table is backed up and restored
+type backupTable struct {
+	name string
+	// Columns never written to the archive
+	exclude []string
+	// SQL expressions for NOT NULL columns missing from the archive, used for new rows
+	fill map[string]string
+	// Self-referencing columns restored in a second pass, once every row exists
+	deferred []string
+}
+
+// backupTables lists the content tables in foreign key order. Derived data (content
+// chunks, search analytics, assistant conversations) is rebuilt or not worth keeping.
+// Password hashes are secrets and never archived: restored users that already existed
+// keep their password, new ones get an unusable password and must have it reset.
+var backupTables = []backupTable{
+	{name: "users", exclude: []string{"password_hash"}, fill: map[string]string{"password_hash": "'!'"}},
+	{name: "categories", deferred: []string{"parent_id"}},
+	{name: "blog_categories"},
+	{name: "products"},
+	{name: "product_images"},
+	{name: "blog_posts"},
+	{name: "contacts"},
+	{name: "video_demos"},
+	{name: "social_media_links"},
+	{name: "faqs"},
+}
+
+// An archive is a gzip-compressed stream of JSON lines: a backupHeader, then for each
+// table a backupTableHeader, one JSON array of column values per row (PostgreSQL text
+// representation, null for NULL) and a backupTableTrailer.
+type backupHeader struct {
+	Format        string    `json:"format"`
+	Version       int       `json:"version"`
+	CreatedAt     time.Time `json:"created_at"`
+	SchemaVersion string    `json:"schema_version"`
+	Tables        []string  `json:"tables"`
+}
+
+type backupTableHeader struct {
+	Table   string   `json:"table"`
+	Columns []string `json:"columns"`
+}
+
+// backupTableTrailer closes a table with its row count so truncated archives are detected
+type backupTableTrailer struct {
+	End  string `json:"end"`
+	Rows int64  `json:"rows"`
+}
+
+// BackupTableCount is the number of rows of one table written or restored
+type BackupTableCount struct {
+	Table   string
+	Rows    int64
+	Skipped int64 // Rows left alone by the skip conflict policy
+}
+
+// BackupSummary describes a backup archive and what was written or restored
+type BackupSummary struct {
+	CreatedAt     time.Time
+	SchemaVersion string // Latest migration applied when the backup was taken
+	Tables        []BackupTableCount
+}
+
+// RestoreOptions controls how an archive is restored
+type RestoreOptions struct {
+	OnConflict string // ConflictFail, ConflictSkip or ConflictOverwrite
+	Truncate   bool   // Empty the content tables before restoring
+}
+
+type BackupService struct {
+	db *sql.DB
+}
+
+func NewBackupService(db *sql.DB) *BackupService {
+	return &BackupService{db: db}
+}
+
+// Backup streams the content tables from one consistent snapshot into w as a compressed archive
+func (s *BackupService) Backup(ctx context.Context, w io.Writer) (*BackupSummary, error) {
+	tx, err := s.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	schemaVersion, err := currentSchemaVersion(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &BackupSummary{CreatedAt: time.Now().UTC(), SchemaVersion: schemaVersion}
+	header := backupHeader{
+		Format:        BackupFormat,
+		Version:       BackupFormatVersion,
+		CreatedAt:     summary.CreatedAt,
+		SchemaVersion: schemaVersion,
+	}
+	for _, table := range backupTables {
+		header.Tables = append(header.Tables, table.name)
+	}
+
+	gz := gzip.NewWriter(w)
+	enc := json.NewEncoder(gz)
+	enc.SetEscapeHTML(false)
+
+	if err := enc.Encode(header); err != nil {
+		return nil, err
+	}
+
+	for _, table := range backupTables {
+		columns, err := tableColumns(ctx, tx, table.name)
+		if err != nil {
+			return nil, err
+		}
+		columns = withoutColumns(columns, table.exclude)
+
+		rows, err := exportTable(ctx, tx, enc, table.name, columns)
+		if err != nil {
+			return nil, fmt.Errorf("failed to export %s: %w", table.name, err)
+		}
+		summary.Tables = append(summary.Tables, BackupTableCount{Table: table.name, Rows: rows})
+	}
+
+	if err := gz.Close(); err != nil {
+		return nil, err
+	}
+	return summary, nil
+}
+
+// exportTable writes the header, rows and trailer of one table.
+// Values are cast to text so every type round-trips through COPY on restore.
+func exportTable(ctx context.Context, tx *sql.Tx, enc *json.Encoder, table string, columns []string) (int64, error) {
+	if err := enc.Encode(backupTableHeader{Table: table, Columns: columns}); err != nil {
+		return 0, err
+	}
+
+	selects := make([]string, len(columns))
+	for i, column := range columns {
+		selects[i] = pq.QuoteIdentifier(column) + "::text"
+	}
+	query := fmt.Sprintf(`SELECT %s FROM %s ORDER BY id`, strings.Join(selects, ", "), pq.QuoteIdentifier(table))
+
+	rows, err := tx.QueryContext(ctx, query)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	record := make([]*string, len(columns))
+	dest := make([]interface{}, len(columns))
+	for i := range record {
+		dest[i] = &record[i]
+	}
+
+	var count int64
+	for rows.Next() {
+		if err := rows.Scan(dest...); err != nil {
+			return 0, err
+		}
+		if err := enc.Encode(record); err != nil {
+			return 0, err
+		}
+		count++
+	}
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	return count, enc.Encode(backupTableTrailer{End: table, Rows: count})
+}
+
+// Restore loads an archive in a single transaction. Rows are copied into a staging table
+// and merged into the live table according to the conflict policy. The database must
+// already be migrated to the archive's schema version or a later one.
+func (s *BackupService) Restore(ctx context.Context, r io.Reader, opts RestoreOptions) (*BackupSummary, error) {
+	switch opts.OnConflict {
+	case ConflictFail, ConflictSkip, ConflictOverwrite:
+	default:
+		return nil, fmt.Errorf("invalid conflict policy: %s (expected fail, skip or overwrite)", opts.OnConflict)
+	}
+
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("not a backup archive: %w", err)
+	}
+	defer gz.Close()
+	dec := json.NewDecoder(gz)
+
+	var header backupHeader
+	if err := dec.Decode(&header); err != nil || header.Format != BackupFormat {
+		return nil, fmt.Errorf("not a backup archive")
+	}
+	if header.Version > BackupFormatVersion {
+		return nil, fmt.Errorf("archive format version %d is newer than the supported version %d", header.Version, BackupFormatVersion)
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// Versions are zero-padded, so they compare as strings
+	schemaVersion, err := currentSchemaVersion(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+	if header.SchemaVersion > schemaVersion {
+		return nil, fmt.Errorf("archive schema %s is newer than database schema %s, upgrade and migrate the database first",
+			header.SchemaVersion, schemaVersion)
+	}
+
+	if opts.Truncate {
+		// Users are emptied too, so set their password hashes aside to put back afterwards
+		keep := `CREATE TEMP TABLE restore_user_passwords ON COMMIT DROP AS SELECT username, password_hash FROM users`
+		if _, err := tx.ExecContext(ctx, keep); err != nil {
+			return nil, err
+		}
+
+		names := []string{"content_chunks"}
+		for _, table := range backupTables {
+			names = append(names, table.name)
+		}
+		if _, err := tx.ExecContext(ctx, "TRUNCATE "+strings.Join(names, ", ")+" RESTART IDENTITY"); err != nil {
+			return nil, err
+		}
+	}
+
+	summary := &BackupSummary{CreatedAt: header.CreatedAt, SchemaVersion: header.SchemaVersion}
+	for {
+		var tableHeader backupTableHeader
+		if err := dec.Decode(&tableHeader); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("corrupt archive: %w", err)
+		}
+
+		table, ok := findBackupTable(tableHeader.Table)
+		if !ok {
+			return nil, fmt.Errorf("archive contains unknown table %q", tableHeader.Table)
+		}
+
+		count, err := restoreTable(ctx, tx, dec, table, tableHeader.Columns, opts.OnConflict)
+		if err != nil {
+			return nil, fmt.Errorf("failed to restore %s: %w", table.name, err)
+		}
+		summary.Tables = append(summary.Tables, count)
+	}
+
+	if len(summary.Tables) != len(header.Tables) {
+		return nil, fmt.Errorf("archive is truncated: %d of %d tables present", len(summary.Tables), len(header.Tables))
+	}
+
+	if opts.Truncate {
+		restorePasswords := `
+			UPDATE users u SET password_hash = k.password_hash
+			FROM restore_user_passwords k
+			WHERE u.username = k.username
+		`
+		if _, err := tx.ExecContext(ctx, restorePasswords); err != nil {
+			return nil, fmt.Errorf("failed to keep user passwords: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return summary, nil
+}
+
+func restoreTable(ctx context.Context, tx *sql.Tx, dec *json.Decoder, table backupTable, columns []string, onConflict string) (BackupTableCount, error) {
+	count := BackupTableCount{Table: table.name}
+
+	live, err := tableColumns(ctx, tx, table.name)
+	if err != nil {
+		return count, err
+	}
+	liveSet := map[string]bool{}
+	for _, column := range live {
+		liveSet[column] = true
+	}
+	archived := map[string]bool{}
+	for _, column := range columns {
+		if !liveSet[column] {
+			return count, fmt.Errorf("column %s is not in the database schema", column)
+		}
+		archived[column] = true
+	}
+	if !archived["id"] {
+		return count, fmt.Errorf("archive has no id column")
+	}
+
+	quoted := make([]string, len(columns))
+	for i, column := range columns {
+		quoted[i] = pq.QuoteIdentifier(column)
+	}
+
+	staging := pq.QuoteIdentifier("restore_" + table.name)
+	createStaging := fmt.Sprintf(`CREATE TEMP TABLE %s ON COMMIT DROP AS SELECT %s FROM %s WITH NO DATA`,
+		staging, strings.Join(quoted, ", "), pq.QuoteIdentifier(table.name))
+	if _, err := tx.ExecContext(ctx, createStaging); err != nil {
+		return count, err
+	}
+
+	copied, err := copyRows(ctx, tx, dec, table.name, "restore_"+table.name, columns)
+	if err != nil {
+		return count, err
+	}
+
+	// Build the merge from the staging table
+	targets := append([]string{}, quoted...)
+	selects := append([]string{}, quoted...)
+	for i, column := range columns {
+		if slices.Contains(table.deferred, column) {
+			selects[i] = "NULL"
+		}
+	}
+	for column, expr := range table.fill {
+		if liveSet[column] && !archived[column] {
+			targets = append(targets, pq.QuoteIdentifier(column))
+			selects = append(selects, expr)
+		}
+	}
+
+	var conflict string
+	switch onConflict {
+	case ConflictSkip:
+		conflict = "ON CONFLICT DO NOTHING"
+	case ConflictOverwrite:
+		var sets []string
+		for i, column := range columns {
+			if column != "id" {
+				sets = append(sets, fmt.Sprintf("%s = EXCLUDED.%s", quoted[i], quoted[i]))
+			}
+		}
+		conflict = "ON CONFLICT (id) DO UPDATE SET " + strings.Join(sets, ", ")
+
+		// Keep the archived updated_at instead of letting the triggers stamp the restore time.
+		// ALTER TABLE is transactional, so a failed restore leaves the triggers enabled.
+		if err := setUserTriggers(ctx, tx, table.name, false); err != nil {
+			return count, err
+		}
+	}
+
+	merge := fmt.Sprintf(`INSERT INTO %s (%s) SELECT %s FROM %s %s RETURNING id`,
+		pq.QuoteIdentifier(table.name), strings.Join(targets, ", "), strings.Join(selects, ", "), staging, conflict)
+	rows, err := tx.QueryContext(ctx, merge)
+	if err != nil {
+		return count, err
+	}
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return count, err
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return count, err
+	}
+	count.Rows = int64(len(ids))
+	count.Skipped = copied - count.Rows
+
+	// Second pass for self references, limited to the rows written above
+	for _, column := range table.deferred {
+		if !archived[column] || len(ids) == 0 {
+			continue
+		}
+		update := fmt.Sprintf(`UPDATE %s t SET %s = s.%s FROM %s s WHERE t.id = s.id AND t.id = ANY($1)`,
+			pq.QuoteIdentifier(table.name), pq.QuoteIdentifier(column), pq.QuoteIdentifier(column), staging)
+		if _, err := tx.ExecContext(ctx, update, pq.Array(ids)); err != nil {
+			return count, err
+		}
+	}
+
+	if onConflict == ConflictOverwrite {
+		if err := setUserTriggers(ctx, tx, table.name, true); err != nil {
+			return count, err
+		}
+	}
+
+	// Move the sequence past the restored ids so new rows do not collide
+	resetSequence := fmt.Sprintf(`SELECT setval(pg_get_serial_sequence($1, 'id'), COALESCE(MAX(id), 0) + 1, false) FROM %s`,
+		pq.QuoteIdentifier(table.name))
+	if _, err := tx.ExecContext(ctx, resetSequence, table.name); err != nil {
+		return count, err
+	}
+
+	return count, nil
+}
+
+func setUserTriggers(ctx context.Context, tx *sql.Tx, table string, enabled bool) error {
+	action := "DISABLE"
+	if enabled {
+		action = "ENABLE"
+	}
+	_, err := tx.ExecContext(ctx, fmt.Sprintf(`ALTER TABLE %s %s TRIGGER USER`, pq.QuoteIdentifier(table), action))
+	return err
+}
+
+// copyRows streams the rows of one table from the archive into the staging table with COPY
+// and checks them against the table trailer
+func copyRows(ctx context.Context, tx *sql.Tx, dec *json.Decoder, table, staging string, columns []string) (int64, error) {
+	stmt, err := tx.PrepareContext(ctx, pq.CopyIn(staging, columns...))
+	if err != nil {
+		return 0, err
+	}
+	defer stmt.Close()
+
+	values := make([]interface{}, len(columns))
+	var copied int64
+	for {
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err == io.EOF {
+			return 0, fmt.Errorf("archive ends before the last row")
+		} else if err != nil {
+			return 0, fmt.Errorf("corrupt archive: %w", err)
+		}
+
+		if len(raw) > 0 && raw[0] == '{' {
+			var trailer backupTableTrailer
+			if err := json.Unmarshal(raw, &trailer); err != nil || trailer.End != table {
+				return 0, fmt.Errorf("corrupt archive: expected end of %s", table)
+			}
+			if trailer.Rows != copied {
+				return 0, fmt.Errorf("archive has %d rows, expected %d", copied, trailer.Rows)
+			}
+			break
+		}
+
+		var record []*string
+		if err := json.Unmarshal(raw, &record); err != nil {
+			return 0, fmt.Errorf("corrupt archive: %w", err)
+		}
+		if len(record) != len(columns) {
+			return 0, fmt.Errorf("corrupt archive: row has %d values, expected %d", len(record), len(columns))
+		}
+		for i, value := range record {
+			if value == nil {
+				values[i] = nil
+			} else {
+				values[i] = *value
+			}
+		}
+		if _, err := stmt.ExecContext(ctx, values...); err != nil {
+			return 0, err
+		}
+		copied++
+	}
+
+	// An empty Exec flushes the COPY
+	if _, err := stmt.ExecContext(ctx); err != nil {
+		return 0, err
+	}
+	return copied, nil
+}
+
+// BackupToDir writes a timestamped archive into dir and, when keep is positive, removes
+// all but the newest keep archives. Returns the path of the new archive.
+func (s *BackupService) BackupToDir(ctx context.Context, dir string, keep int) (string, *BackupSummary, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", nil, err
+	}
+
+	path := filepath.Join(dir, backupFilePrefix+time.Now().UTC().Format("20060102T150405Z")+backupFileSuffix)
+	summary, err := s.BackupToFile(ctx, path)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if keep > 0 {
+		removed, err := PruneBackups(dir, keep)
+		if err != nil {
+			return path, summary, fmt.Errorf("backup written but rotation failed: %w", err)
+		}
+		for _, old := range removed {
+			logger.InfoContext(ctx, "Removed old backup %s", old)
+		}
+	}
+
+	return path, summary, nil
+}
+
+// BackupToFile writes an archive to path. The file only appears once the backup is complete.
+// Archives hold personal data such as contacts, so only the owner can read them.
+func (s *BackupService) BackupToFile(ctx context.Context, path string) (*BackupSummary, error) {
+	tmp := path + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		return nil, err
+	}
+
+	summary, err := s.Backup(ctx, f)
+	if err == nil {
+		err = f.Sync()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp, path)
+	}
+	if err != nil {
+		os.Remove(tmp)
+		return nil, err
+	}
+
+	return summary, nil
+}
+
+// PruneBackups deletes all but the newest keep archives in dir and returns the removed paths
+func PruneBackups(dir string, keep int) ([]string, error) {
+	backups, err := listBackups(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var removed []string
+	for len(backups) > keep {
+		if err := os.Remove(backups[0]); err != nil {
+			return removed, err
+		}
+		removed = append(removed, backups[0])
+		backups = backups[1:]
+	}
+	return removed, nil
+}
+
+// listBackups returns the archives in dir, oldest first; the file names sort by time
+func listBackups(dir string) ([]string, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, backupFilePrefix+"*"+backupFileSuffix))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
+
+// RunSchedule takes a backup into dir every interval until ctx is done. Replicas sharing
+// the directory skip the run when a recent archive exists or another replica is writing one.
+func (s *BackupService) RunSchedule(ctx context.Context, dir string, interval time.Duration, keep int) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.scheduledBackup(ctx, dir, interval, keep); err != nil {
+			metrics.Backups.Inc("failure")
+			logger.ErrorWithErrContext(ctx, "Scheduled backup failed", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *BackupService) scheduledBackup(ctx context.Context, dir string, interval time.Duration, keep int) error {
+	conn, err := s.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	var locked bool
+	if err := conn.QueryRowContext(ctx, `SELECT pg_try_advisory_lock($1)`, backupLockKey).Scan(&locked); err != nil {
+		return err
+	}
+	if !locked {
+		metrics.Backups.Inc("skipped")
+		logger.DebugContext(ctx, "Scheduled backup skipped, another replica is taking one")
+		return nil
+	}
+	defer conn.ExecContext(ctx, `SELECT pg_advisory_unlock($1)`, backupLockKey)
+
+	// Half an interval of slack absorbs ticker drift between replicas
+	backups, err := listBackups(dir)
+	if err != nil {
+		return err
+	}
+	if len(backups) > 0 {
+		if info, err := os.Stat(backups[len(backups)-1]); err == nil && time.Since(info.ModTime()) < interval/2 {
+			metrics.Backups.Inc("skipped")
+			logger.DebugContext(ctx, "Scheduled backup skipped, %s is recent", backups[len(backups)-1])
+			return nil
+		}
+	}
+
+	path, summary, err := s.BackupToDir(ctx, dir, keep)
+	if err != nil {
+		return err
+	}
+
+	var rows int64
+	for _, table := range summary.Tables {
+		rows += table.Rows
+	}
+	metrics.Backups.Inc("success")
+	metrics.BackupLastSuccess.Set(float64(time.Now().Unix()))
+	logger.InfoContext(ctx, "Scheduled backup written to %s (%d rows, schema %s)", path, rows, summary.SchemaVersion)
+	return nil
+}
+
+// currentSchemaVersion returns the latest applied migration, which tags archives
+func currentSchemaVersion(ctx context.Context, tx *sql.Tx) (string, error) {
+	var version string
+	err := tx.QueryRowContext(ctx, `SELECT COALESCE(MAX(version), '') FROM schema_migrations`).Scan(&version)
+	return version, err
+}
+
+// tableColumns returns the stored columns of a public table in definition order
+func tableColumns(ctx context.Context, tx *sql.Tx, table string) ([]string, error) {
+	query := `
+		SELECT column_name FROM information_schema.columns
+		WHERE table_schema = 'public' AND table_name = $1 AND is_generated = 'NEVER'
+		ORDER BY ordinal_position
+	`
+	rows, err := tx.QueryContext(ctx, query, table)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var columns []string
+	for rows.Next() {
+		var column string
+		if err := rows.Scan(&column); err != nil {
+			return nil, err
+		}
+		columns = append(columns, column)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("table %s does not exist", table)
+	}
+	return columns, nil
+}
+
+func findBackupTable(name string) (backupTable, bool) {
+	for _, table := range backupTables {
+		if table.name == name {
+			return table, true
+		}
+	}
+	return backupTable{}, false
+}
+
+func withoutColumns(columns, exclude []string) []string {
+	var kept []string
+	for _, column := range columns {
+		if !slices.Contains(exclude, column) {
+			kept = append(kept, column)
+		}
+	}
+	return kept
+}
@@ -1,8 +1,11 @@
 package services
 
 import (
+	"context"
 	"database/sql"
+	"time"
 
+	"bizgenie-api/internal/metrics"
 	"bizgenie-api/internal/models"
 )
 
@@ -14,9 +17,10 @@ func NewBlogCategoryService(db *sql.DB) *BlogCategoryService {
 	return &BlogCategoryService{db: db}
 }
 
-func (s *BlogCategoryService) GetBlogCategories() ([]models.BlogCategory, error) {
+func (s *BlogCategoryService) GetBlogCategories(ctx context.Context) ([]models.BlogCategory, error) {
+	defer metrics.ObserveQuery("blog_categories.list", time.Now())
 	query := `SELECT id, name, slug, description, "order", created_at FROM blog_categories ORDER BY "order", created_at`
-	rows, err := s.db.Query(query)
+	rows, err := s.db.QueryContext(ctx, query)
 	if err != nil {
 		return nil, err
 	}
@@ -35,40 +39,51 @@ func (s *BlogCategoryService) GetBlogCategories() ([]models.BlogCategory, error)
 	return categories, nil
 }
 
-func (s *BlogCategoryService) GetBlogCategoryByID(id int) (*models.BlogCategory, error) {
+func (s *BlogCategoryService) GetBlogCategoryByID(ctx context.Context, id int) (*models.BlogCategory, error) {
+	defer metrics.ObserveQuery("blog_categories.get_by_id", time.Now())
 	query := `SELECT id, name, slug, description, "order", created_at FROM blog_categories WHERE id = $1`
 	var c models.BlogCategory
-	err := s.db.QueryRow(query, id).Scan(&c.ID, &c.Name, &c.Slug, &c.Description, &c.Order, &c.CreatedAt)
+	err := s.db.QueryRowContext(ctx, query, id).Scan(&c.ID, &c.Name, &c.Slug, &c.Description, &c.Order, &c.CreatedAt)
 	if err != nil {
-		return nil, err
+		return nil, notFound(err, "blog_category_not_found")
 	}
 	return &c, nil
 }
 
-func (s *BlogCategoryService) GetBlogCategoryBySlug(slug string) (*models.BlogCategory, error) {
+func (s *BlogCategoryService) GetBlogCategoryBySlug(ctx context.Context, slug string) (*models.BlogCategory, error) {
+	defer metrics.ObserveQuery("blog_categories.get_by_slug", time.Now())
 	query := `SELECT id, name, slug, description, "order", created_at FROM blog_categories WHERE slug = $1`
 	var c models.BlogCategory
-	err := s.db.QueryRow(query, slug).Scan(&c.ID, &c.Name, &c.Slug, &c.Description, &c.Order, &c.CreatedAt)
+	err := s.db.QueryRowContext(ctx, query, slug).Scan(&c.ID, &c.Name, &c.Slug, &c.Description, &c.Order, &c.CreatedAt)
 	if err != nil {
-		return nil, err
+		return nil, notFound(err, "blog_category_not_found")
 	}
 	return &c, nil
 }
 
-func (s *BlogCategoryService) CreateBlogCategory(c *models.BlogCategory) error {
+func (s *BlogCategoryService) CreateBlogCategory(ctx context.Context, c *models.BlogCategory) error {
+	if c.Slug == "" {
+		c.Slug = Slugify(c.Name)
+	}
 	query := `INSERT INTO blog_categories (name, slug, description, "order") VALUES ($1, $2, $3, $4) RETURNING id, created_at`
-	err := s.db.QueryRow(query, c.Name, c.Slug, c.Description, c.Order).Scan(&c.ID, &c.CreatedAt)
-	return err
+	err := s.db.QueryRowContext(ctx, query, c.Name, c.Slug, c.Description, c.Order).Scan(&c.ID, &c.CreatedAt)
+	return dbError(err)
 }
 
-func (s *BlogCategoryService) UpdateBlogCategory(id int, c *models.BlogCategory) error {
+func (s *BlogCategoryService) UpdateBlogCategory(ctx context.Context, id int, c *models.BlogCategory) error {
 	query := `UPDATE blog_categories SET name = $2, slug = $3, description = $4, "order" = $5 WHERE id = $1`
-	_, err := s.db.Exec(query, id, c.Name, c.Slug, c.Description, c.Order)
-	return err
+	result, err := s.db.ExecContext(ctx, query, id, c.Name, c.Slug, c.Description, c.Order)
+	if err != nil {
+		return dbError(err)
+	}
+	return affectedOne(result, "blog_category_not_found")
 }
 
-func (s *BlogCategoryService) DeleteBlogCategory(id int) error {
+func (s *BlogCategoryService) DeleteBlogCategory(ctx context.Context, id int) error {
 	query := `DELETE FROM blog_categories WHERE id = $1`
-	_, err := s.db.Exec(query, id)
-	return err
+	result, err := s.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return dbError(err)
+	}
+	return affectedOne(result, "blog_category_not_found")
 }
@@ -1,10 +1,12 @@
 package services
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"time"
 
+	"bizgenie-api/internal/metrics"
 	"bizgenie-api/internal/models"
 )
 
@@ -16,7 +18,8 @@ func NewBlogService(db *sql.DB) *BlogService {
 	return &BlogService{db: db}
 }
 
-func (s *BlogService) GetBlogPosts(status string, limit, offset int) ([]models.BlogPost, error) {
+func (s *BlogService) GetBlogPosts(ctx context.Context, status string, limit, offset int) ([]models.BlogPost, error) {
+	defer metrics.ObserveQuery("blog_posts.list", time.Now())
 	query := `
 		SELECT b.id, b.title, b.slug, b.excerpt, b.content, b.featured_image,
 		       b.author_id, b.category_id, b.status, b.published_at, b.created_at, b.updated_at,
@@ -39,7 +42,7 @@ func (s *BlogService) GetBlogPosts(status string, limit, offset int) ([]models.B
 	query += fmt.Sprintf(" ORDER BY b.published_at DESC, b.created_at DESC LIMIT $%d OFFSET $%d", argPos, argPos+1)
 	args = append(args, limit, offset)
 
-	rows, err := s.db.Query(query, args...)
+	rows, err := s.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -101,7 +104,8 @@ func (s *BlogService) GetBlogPosts(status string, limit, offset int) ([]models.B
 	return posts, nil
 }
 
-func (s *BlogService) GetBlogPostByID(id int) (*models.BlogPost, error) {
+func (s *BlogService) GetBlogPostByID(ctx context.Context, id int) (*models.BlogPost, error) {
+	defer metrics.ObserveQuery("blog_posts.get_by_id", time.Now())
 	query := `
 		SELECT b.id, b.title, b.slug, b.excerpt, b.content, b.featured_image,
 		       b.author_id, b.category_id, b.status, b.published_at, b.created_at, b.updated_at,
@@ -124,14 +128,14 @@ func (s *BlogService) GetBlogPostByID(id int) (*models.BlogPost, error) {
 	var categoryCreatedAt sql.NullTime
 	var pCategoryID sql.NullInt64
 
-	err := s.db.QueryRow(query, id).Scan(
+	err := s.db.QueryRowContext(ctx, query, id).Scan(
 		&p.ID, &p.Title, &p.Slug, &p.Excerpt, &p.Content, &p.FeaturedImage,
 		&p.AuthorID, &pCategoryID, &p.Status, &p.PublishedAt, &p.CreatedAt, &p.UpdatedAt,
 		&author.ID, &author.Username, &author.Email, &author.Role, &author.CreatedAt,
 		&categoryID, &categoryName, &categorySlug, &categoryDesc, &categoryOrder, &categoryCreatedAt,
 	)
 	if err != nil {
-		return nil, err
+		return nil, notFound(err, "blog_post_not_found")
 	}
 
 	if pCategoryID.Valid {
@@ -164,7 +168,8 @@ func (s *BlogService) GetBlogPostByID(id int) (*models.BlogPost, error) {
 	return &p, nil
 }
 
-func (s *BlogService) GetBlogPostBySlug(slug string) (*models.BlogPost, error) {
+func (s *BlogService) GetBlogPostBySlug(ctx context.Context, slug string) (*models.BlogPost, error) {
+	defer metrics.ObserveQuery("blog_posts.get_by_slug", time.Now())
 	query := `
 		SELECT b.id, b.title, b.slug, b.excerpt, b.content, b.featured_image,
 		       b.author_id, b.category_id, b.status, b.published_at, b.created_at, b.updated_at,
@@ -187,14 +192,14 @@ func (s *BlogService) GetBlogPostBySlug(slug string) (*models.BlogPost, error) {
 	var categoryCreatedAt sql.NullTime
 	var pCategoryID sql.NullInt64
 
-	err := s.db.QueryRow(query, slug).Scan(
+	err := s.db.QueryRowContext(ctx, query, slug).Scan(
 		&p.ID, &p.Title, &p.Slug, &p.Excerpt, &p.Content, &p.FeaturedImage,
 		&p.AuthorID, &pCategoryID, &p.Status, &p.PublishedAt, &p.CreatedAt, &p.UpdatedAt,
 		&author.ID, &author.Username, &author.Email, &author.Role, &author.CreatedAt,
 		&categoryID, &categoryName, &categorySlug, &categoryDesc, &categoryOrder, &categoryCreatedAt,
 	)
 	if err != nil {
-		return nil, err
+		return nil, notFound(err, "blog_post_not_found")
 	}
 
 	if pCategoryID.Valid {
@@ -229,7 +234,10 @@ func (s *BlogService) GetBlogPostBySlug(slug string) (*models.BlogPost, error) {
 
 // CreateBlogPost tạo bài viết blog mới
 // Nếu bỏ trống thời gian tạo (published_at), hệ thống sẽ tự động lấy ngày giờ hiện tại
-func (s *BlogService) CreateBlogPost(p *models.BlogPost) error {
+func (s *BlogService) CreateBlogPost(ctx context.Context, p *models.BlogPost) error {
+	if p.Slug == "" {
+		p.Slug = Slugify(p.Title)
+	}
 	query := `
 		INSERT INTO blog_posts (title, slug, excerpt, content, featured_image, author_id, category_id, status, published_at)
 		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
@@ -251,15 +259,15 @@ func (s *BlogService) CreateBlogPost(p *models.BlogPost) error {
 		publishedAtValue = now
 	}
 
-	err := s.db.QueryRow(
+	err := s.db.QueryRowContext(ctx,
 		query, p.Title, p.Slug, p.Excerpt, p.Content, p.FeaturedImage,
 		p.AuthorID, p.CategoryID, p.Status, publishedAtValue,
 	).Scan(&p.ID, &p.CreatedAt, &p.UpdatedAt)
 
-	return err
+	return dbError(err)
 }
 
-func (s *BlogService) UpdateBlogPost(id int, p *models.BlogPost) error {
+func (s *BlogService) UpdateBlogPost(ctx context.Context, id int, p *models.BlogPost) error {
 	query := `
 		UPDATE blog_posts 
 		SET title = $2, slug = $3, excerpt = $4, content = $5, 
@@ -281,16 +289,19 @@ func (s *BlogService) UpdateBlogPost(id int, p *models.BlogPost) error {
 		publishedAtValue = nil
 	}
 
-	err := s.db.QueryRow(
+	err := s.db.QueryRowContext(ctx,
 		query, id, p.Title, p.Slug, p.Excerpt, p.Content,
 		p.FeaturedImage, p.CategoryID, p.Status, publishedAtValue,
 	).Scan(&p.UpdatedAt)
 
-	return err
+	return notFound(err, "blog_post_not_found")
 }
 
-func (s *BlogService) DeleteBlogPost(id int) error {
+func (s *BlogService) DeleteBlogPost(ctx context.Context, id int) error {
 	query := `DELETE FROM blog_posts WHERE id = $1`
-	_, err := s.db.Exec(query, id)
-	return err
+	result, err := s.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return dbError(err)
+	}
+	return affectedOne(result, "blog_post_not_found")
 }
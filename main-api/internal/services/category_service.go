@@ -1,8 +1,11 @@
 package services
 
 import (
+	"context"
 	"database/sql"
+	"time"
 
+	"bizgenie-api/internal/metrics"
 	"bizgenie-api/internal/models"
 )
 
@@ -14,16 +17,17 @@ func NewCategoryService(db *sql.DB) *CategoryService {
 	return &CategoryService{db: db}
 }
 
-func (s *CategoryService) GetCategories(parentID *int) ([]models.Category, error) {
+func (s *CategoryService) GetCategories(ctx context.Context, parentID *int) ([]models.Category, error) {
+	defer metrics.ObserveQuery("categories.list", time.Now())
 	query := `SELECT id, name, slug, description, parent_id, "order", created_at FROM categories`
 	var rows *sql.Rows
 	var err error
 
 	if parentID != nil {
 		query += " WHERE parent_id = $1"
-		rows, err = s.db.Query(query+" ORDER BY \"order\", created_at", *parentID)
+		rows, err = s.db.QueryContext(ctx, query+" ORDER BY \"order\", created_at", *parentID)
 	} else {
-		rows, err = s.db.Query(query + " ORDER BY \"order\", created_at")
+		rows, err = s.db.QueryContext(ctx, query+" ORDER BY \"order\", created_at")
 	}
 
 	if err != nil {
@@ -44,40 +48,51 @@ func (s *CategoryService) GetCategories(parentID *int) ([]models.Category, error
 	return categories, nil
 }
 
-func (s *CategoryService) GetCategoryByID(id int) (*models.Category, error) {
+func (s *CategoryService) GetCategoryByID(ctx context.Context, id int) (*models.Category, error) {
+	defer metrics.ObserveQuery("categories.get_by_id", time.Now())
 	query := `SELECT id, name, slug, description, parent_id, "order", created_at FROM categories WHERE id = $1`
 	var c models.Category
-	err := s.db.QueryRow(query, id).Scan(&c.ID, &c.Name, &c.Slug, &c.Description, &c.ParentID, &c.Order, &c.CreatedAt)
+	err := s.db.QueryRowContext(ctx, query, id).Scan(&c.ID, &c.Name, &c.Slug, &c.Description, &c.ParentID, &c.Order, &c.CreatedAt)
 	if err != nil {
-		return nil, err
+		return nil, notFound(err, "category_not_found")
 	}
 	return &c, nil
 }
 
-func (s *CategoryService) GetCategoryBySlug(slug string) (*models.Category, error) {
+func (s *CategoryService) GetCategoryBySlug(ctx context.Context, slug string) (*models.Category, error) {
+	defer metrics.ObserveQuery("categories.get_by_slug", time.Now())
 	query := `SELECT id, name, slug, description, parent_id, "order", created_at FROM categories WHERE slug = $1`
 	var c models.Category
-	err := s.db.QueryRow(query, slug).Scan(&c.ID, &c.Name, &c.Slug, &c.Description, &c.ParentID, &c.Order, &c.CreatedAt)
+	err := s.db.QueryRowContext(ctx, query, slug).Scan(&c.ID, &c.Name, &c.Slug, &c.Description, &c.ParentID, &c.Order, &c.CreatedAt)
 	if err != nil {
-		return nil, err
+		return nil, notFound(err, "category_not_found")
 	}
 	return &c, nil
 }
 
-func (s *CategoryService) CreateCategory(c *models.Category) error {
+func (s *CategoryService) CreateCategory(ctx context.Context, c *models.Category) error {
+	if c.Slug == "" {
+		c.Slug = Slugify(c.Name)
+	}
 	query := `INSERT INTO categories (name, slug, description, parent_id, "order") VALUES ($1, $2, $3, $4, $5) RETURNING id, created_at`
-	err := s.db.QueryRow(query, c.Name, c.Slug, c.Description, c.ParentID, c.Order).Scan(&c.ID, &c.CreatedAt)
-	return err
+	err := s.db.QueryRowContext(ctx, query, c.Name, c.Slug, c.Description, c.ParentID, c.Order).Scan(&c.ID, &c.CreatedAt)
+	return dbError(err)
 }
 
-func (s *CategoryService) UpdateCategory(id int, c *models.Category) error {
+func (s *CategoryService) UpdateCategory(ctx context.Context, id int, c *models.Category) error {
 	query := `UPDATE categories SET name = $2, slug = $3, description = $4, parent_id = $5, "order" = $6 WHERE id = $1`
-	_, err := s.db.Exec(query, id, c.Name, c.Slug, c.Description, c.ParentID, c.Order)
-	return err
+	result, err := s.db.ExecContext(ctx, query, id, c.Name, c.Slug, c.Description, c.ParentID, c.Order)
+	if err != nil {
+		return dbError(err)
+	}
+	return affectedOne(result, "category_not_found")
 }
 
-func (s *CategoryService) DeleteCategory(id int) error {
+func (s *CategoryService) DeleteCategory(ctx context.Context, id int) error {
 	query := `DELETE FROM categories WHERE id = $1`
-	_, err := s.db.Exec(query, id)
-	return err
+	result, err := s.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return dbError(err)
+	}
+	return affectedOne(result, "category_not_found")
 }
@@ -0,0 +1,344 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"bizgenie-api/internal/metrics"
+	"bizgenie-api/internal/models"
+
+	"github.com/lib/pq"
+	"github.com/pgvector/pgvector-go"
+)
+
+// ChunkSourceTypes lists the content types split into content_chunks
+var ChunkSourceTypes = []string{"product", "blog"}
+
+// ChunkService keeps content_chunks in sync with products and blog posts and
+// matches questions or queries against individual passages
+type ChunkService struct {
+	db       *sql.DB
+	embedder EmbeddingProvider
+}
+
+func NewChunkService(db *sql.DB, embedder EmbeddingProvider) *ChunkService {
+	return &ChunkService{db: db, embedder: embedder}
+}
+
+// Enabled reports whether an embedding provider is configured
+func (s *ChunkService) Enabled() bool {
+	return s.embedder != nil
+}
+
+// chunkSource is the text of a product or blog post prepared for chunking
+type chunkSource struct {
+	title     string
+	content   string
+	published bool
+}
+
+func (s *ChunkService) loadSource(ctx context.Context, sourceType string, id int) (*chunkSource, error) {
+	src := &chunkSource{}
+	var status string
+
+	switch sourceType {
+	case "product":
+		var shortDescription, description string
+		var features []byte
+		query := `
+			SELECT name, COALESCE(short_description, ''), COALESCE(description, ''), features, status
+			FROM products WHERE id = $1
+		`
+		err := s.db.QueryRowContext(ctx, query, id).Scan(&src.title, &shortDescription, &description, &features, &status)
+		if err != nil {
+			return nil, err
+		}
+
+		parts := []string{shortDescription, description}
+		// Features are a list of strings; other shapes are left to the product embedding
+		var featureList []string
+		if json.Unmarshal(features, &featureList) == nil && len(featureList) > 0 {
+			parts = append(parts, "<h2>Features</h2>\n<ul><li>"+strings.Join(featureList, "</li><li>")+"</li></ul>")
+		}
+		src.content = strings.Join(parts, "\n\n")
+
+	case "blog":
+		var excerpt string
+		query := `SELECT title, COALESCE(excerpt, ''), content, status FROM blog_posts WHERE id = $1`
+		if err := s.db.QueryRowContext(ctx, query, id).Scan(&src.title, &excerpt, &src.content, &status); err != nil {
+			return nil, err
+		}
+		src.content = excerpt + "\n\n" + src.content
+
+	default:
+		return nil, fmt.Errorf("unknown content type: %s", sourceType)
+	}
+
+	src.published = status == "published"
+	return src, nil
+}
+
+// Sync rebuilds the chunks of one product or blog post and returns how many were stored.
+// Chunks whose text is unchanged keep their embedding; deleted or unpublished sources
+// lose their chunks. With reembed every chunk is embedded again.
+func (s *ChunkService) Sync(ctx context.Context, sourceType string, id int, reembed bool) (int, error) {
+	if !s.Enabled() {
+		return 0, ErrAssistantDisabled
+	}
+	src, err := s.loadSource(ctx, sourceType, id)
+	if err == sql.ErrNoRows {
+		return 0, s.Delete(ctx, sourceType, id)
+	}
+	if err != nil {
+		return 0, err
+	}
+	if !src.published {
+		return 0, s.Delete(ctx, sourceType, id)
+	}
+
+	existing := map[string]pgvector.Vector{}
+	if !reembed {
+		existing, err = s.existingEmbeddings(ctx, sourceType, id)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	_, chunks := ChunkContent(src.content)
+
+	// Embed before opening the transaction so provider latency does not hold locks
+	hashes := make([]string, len(chunks))
+	embeddings := make([]pgvector.Vector, len(chunks))
+	for i, chunk := range chunks {
+		text := chunkEmbeddingText(src.title, chunk)
+		sum := sha256.Sum256([]byte(text))
+		hashes[i] = hex.EncodeToString(sum[:])
+
+		if embedding, ok := existing[hashes[i]]; ok {
+			embeddings[i] = embedding
+			continue
+		}
+		embedding, err := s.embedder.Embed(ctx, text)
+		if err != nil {
+			return 0, fmt.Errorf("failed to embed chunk %d: %w", chunk.Index, err)
+		}
+		embeddings[i] = pgvector.NewVector(embedding)
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	// Serialize concurrent syncs of the same source so their inserts cannot collide
+	if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock(hashtext($1), $2)`, sourceType, id); err != nil {
+		return 0, err
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM content_chunks WHERE source_type = $1 AND source_id = $2`, sourceType, id); err != nil {
+		return 0, err
+	}
+
+	insert := `
+		INSERT INTO content_chunks (source_type, source_id, chunk_index, section, content, start_offset, end_offset, content_hash, embedding)
+		VALUES ($1, $2, $3, NULLIF($4, ''), $5, $6, $7, $8, $9)
+	`
+	for i, chunk := range chunks {
+		_, err := tx.ExecContext(ctx, insert,
+			sourceType, id, chunk.Index, chunk.Section, chunk.Content,
+			chunk.StartOffset, chunk.EndOffset, hashes[i], embeddings[i],
+		)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return len(chunks), nil
+}
+
+// chunkEmbeddingText prefixes the chunk with its document title and section so
+// short passages keep their context
+func chunkEmbeddingText(title string, chunk ContentChunk) string {
+	text := title
+	if chunk.Section != "" {
+		text += "\n" + chunk.Section
+	}
+	return text + "\n" + chunk.Content
+}
+
+func (s *ChunkService) existingEmbeddings(ctx context.Context, sourceType string, id int) (map[string]pgvector.Vector, error) {
+	query := `
+		SELECT content_hash, embedding FROM content_chunks
+		WHERE source_type = $1 AND source_id = $2 AND embedding IS NOT NULL
+	`
+	rows, err := s.db.QueryContext(ctx, query, sourceType, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	embeddings := map[string]pgvector.Vector{}
+	for rows.Next() {
+		var hash string
+		var embedding pgvector.Vector
+		if err := rows.Scan(&hash, &embedding); err != nil {
+			return nil, err
+		}
+		embeddings[hash] = embedding
+	}
+
+	return embeddings, rows.Err()
+}
+
+// Delete removes the chunks of one product or blog post
+func (s *ChunkService) Delete(ctx context.Context, sourceType string, id int) error {
+	_, err := s.db.ExecContext(ctx, `DELETE FROM content_chunks WHERE source_type = $1 AND source_id = $2`, sourceType, id)
+	return err
+}
+
+// SyncAll rebuilds the chunks of every product and blog post.
+// Returns the number of chunks stored per content type.
+func (s *ChunkService) SyncAll(ctx context.Context, reembed bool) (map[string]int, error) {
+	counts := map[string]int{}
+	if !s.Enabled() {
+		return counts, ErrAssistantDisabled
+	}
+
+	for _, sourceType := range ChunkSourceTypes {
+		table := "products"
+		if sourceType == "blog" {
+			table = "blog_posts"
+		}
+
+		ids, err := s.sourceIDs(ctx, table)
+		if err != nil {
+			return counts, err
+		}
+
+		for _, id := range ids {
+			n, err := s.Sync(ctx, sourceType, id, reembed)
+			if err != nil {
+				return counts, fmt.Errorf("failed to chunk %s %d: %w", sourceType, id, err)
+			}
+			counts[sourceType] += n
+		}
+	}
+
+	// Drop chunks left behind by sources removed while the triggers were missing
+	_, err := s.db.ExecContext(ctx, `
+		DELETE FROM content_chunks c
+		WHERE (c.source_type = 'product' AND NOT EXISTS (SELECT 1 FROM products p WHERE p.id = c.source_id))
+		   OR (c.source_type = 'blog' AND NOT EXISTS (SELECT 1 FROM blog_posts b WHERE b.id = c.source_id))
+	`)
+	return counts, err
+}
+
+func (s *ChunkService) sourceIDs(ctx context.Context, table string) ([]int, error) {
+	rows, err := s.db.QueryContext(ctx, fmt.Sprintf(`SELECT id FROM %s ORDER BY id`, table))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
+
+// SearchPassages embeds the query and returns the best matching passage of each
+// published product or blog post, most similar first
+func (s *ChunkService) SearchPassages(ctx context.Context, query string, types []string, limit int) ([]models.PassageMatch, error) {
+	if !s.Enabled() {
+		return nil, ErrAssistantDisabled
+	}
+	defer metrics.ObserveSearch("passages", time.Now())
+	embedding, err := s.embedder.Embed(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to embed query: %w", err)
+	}
+	return s.MatchPassages(ctx, s.db, embedding, types, 1, limit)
+}
+
+// queryer is satisfied by both *sql.DB and *sql.Tx
+type queryer interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// MatchPassages returns up to limit chunks closest to embedding, at most perSource
+// per product or blog post. An empty types list matches every source type.
+//
+// Only the limit*perSource nearest chunks are considered, so the ANN index on
+// content_chunks.embedding serves the inner query; sources are deduplicated afterwards.
+func (s *ChunkService) MatchPassages(ctx context.Context, q queryer, embedding []float32, types []string, perSource, limit int) ([]models.PassageMatch, error) {
+	defer metrics.ObserveQuery("content_chunks.match", time.Now())
+	if len(types) == 0 {
+		types = ChunkSourceTypes
+	}
+
+	query := `
+		WITH sources AS (
+			SELECT 'product' AS source_type, id, name AS title, '/products/' || slug AS url
+			FROM products WHERE status = 'published'
+			UNION ALL
+			SELECT 'blog', id, title, '/blog/' || slug
+			FROM blog_posts WHERE status = 'published'
+		),
+		nearest AS (
+			SELECT source_type, source_id, section, content, start_offset, end_offset,
+			       embedding <=> $1::vector AS distance
+			FROM content_chunks
+			WHERE embedding IS NOT NULL
+			  AND source_type = ANY($2)
+			ORDER BY embedding <=> $1::vector
+			LIMIT $5
+		),
+		ranked AS (
+			SELECT c.source_type, c.source_id, src.title, src.url, c.section, c.content,
+			       c.start_offset, c.end_offset,
+			       1 - c.distance AS score,
+			       ROW_NUMBER() OVER (PARTITION BY c.source_type, c.source_id ORDER BY c.distance) AS rank
+			FROM nearest c
+			JOIN sources src ON src.source_type = c.source_type AND src.id = c.source_id
+		)
+		SELECT source_type, source_id, title, url, section, content, start_offset, end_offset, score
+		FROM ranked
+		WHERE rank <= $3 AND score > 0
+		ORDER BY score DESC
+		LIMIT $4
+	`
+
+	rows, err := q.QueryContext(ctx, query, pgvector.NewVector(embedding), pq.Array(types), perSource, limit, limit*perSource)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	matches := []models.PassageMatch{}
+	for rows.Next() {
+		var m models.PassageMatch
+		err := rows.Scan(&m.Type, &m.ID, &m.Title, &m.URL, &m.Section, &m.Snippet, &m.StartOffset, &m.EndOffset, &m.Score)
+		if err != nil {
+			return nil, err
+		}
+		matches = append(matches, m)
+	}
+
+	return matches, rows.Err()
+}
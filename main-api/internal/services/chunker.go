@@ -0,0 +1,185 @@
+package services
+
+import (
+	"html"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+const (
+	// maxChunkRunes bounds the size of a chunk; paragraphs are packed up to this size
+	maxChunkRunes = 800
+	// headingMarker tags heading paragraphs between HTML cleaning and chunking
+	headingMarker = "\x00"
+)
+
+var (
+	scriptStylePattern = regexp.MustCompile(`(?is)<(script|style)[^>]*>.*?</(script|style)>`)
+	headingTagPattern  = regexp.MustCompile(`(?is)<h[1-6][^>]*>(.*?)</h[1-6]>`)
+	lineBreakPattern   = regexp.MustCompile(`(?i)<br\s*/?>`)
+	blockTagPattern    = regexp.MustCompile(`(?i)</?(p|div|section|article|ul|ol|li|table|tr|blockquote|pre|figure)[^>]*>`)
+	anyTagPattern      = regexp.MustCompile(`<[^>]+>`)
+	paragraphSeparator = regexp.MustCompile(`\n\s*\n`)
+	markdownHeading    = regexp.MustCompile(`^#{1,6}\s+`)
+)
+
+// ContentChunk is a passage of a cleaned document. Offsets are rune offsets into
+// the cleaned text, so cleaned[StartOffset:EndOffset] equals Content.
+type ContentChunk struct {
+	Index       int
+	Section     string
+	Content     string
+	StartOffset int
+	EndOffset   int
+}
+
+// CleanContent converts HTML or plain text into paragraphs separated by blank lines.
+// Headings (<h1>-<h6> or markdown "#" lines) are kept as their own paragraphs and
+// marked with headingMarker so ChunkContent can track sections.
+func CleanContent(content string) string {
+	s := scriptStylePattern.ReplaceAllString(content, " ")
+	s = headingTagPattern.ReplaceAllStringFunc(s, func(m string) string {
+		inner := headingTagPattern.FindStringSubmatch(m)[1]
+		return "\n\n" + headingMarker + anyTagPattern.ReplaceAllString(inner, " ") + "\n\n"
+	})
+	s = lineBreakPattern.ReplaceAllString(s, "\n")
+	s = blockTagPattern.ReplaceAllString(s, "\n\n")
+	s = anyTagPattern.ReplaceAllString(s, " ")
+	s = html.UnescapeString(s)
+
+	var paragraphs []string
+	add := func(p string, heading bool) {
+		p = strings.Join(strings.Fields(p), " ")
+		if p == "" {
+			return
+		}
+		if heading {
+			p = headingMarker + p
+		}
+		paragraphs = append(paragraphs, p)
+	}
+
+	for _, p := range paragraphSeparator.Split(s, -1) {
+		p = strings.TrimSpace(p)
+		if strings.HasPrefix(p, headingMarker) {
+			add(strings.ReplaceAll(p, headingMarker, ""), true)
+			continue
+		}
+
+		p = strings.ReplaceAll(p, headingMarker, "")
+		if firstLine, rest, _ := strings.Cut(p, "\n"); markdownHeading.MatchString(firstLine) {
+			add(markdownHeading.ReplaceAllString(firstLine, ""), true)
+			p = rest
+		}
+		add(p, false)
+	}
+
+	return strings.Join(paragraphs, "\n\n")
+}
+
+// ChunkContent cleans content and splits it into chunks that never cross a heading.
+// Paragraphs are packed together up to maxChunkRunes; longer paragraphs are split at
+// sentence or word boundaries. Returns the cleaned text the offsets refer to.
+func ChunkContent(content string) (string, []ContentChunk) {
+	marked := CleanContent(content)
+	paragraphs := strings.Split(marked, "\n\n")
+
+	var cleaned []rune
+	var chunks []ContentChunk
+	var section string
+	start, end := -1, -1
+
+	flush := func() {
+		if start >= 0 {
+			chunks = append(chunks, ContentChunk{
+				Index:       len(chunks),
+				Section:     section,
+				Content:     string(cleaned[start:end]),
+				StartOffset: start,
+				EndOffset:   end,
+			})
+		}
+		start, end = -1, -1
+	}
+
+	for _, p := range paragraphs {
+		if p == "" {
+			continue
+		}
+		if len(cleaned) > 0 {
+			cleaned = append(cleaned, '\n', '\n')
+		}
+
+		if strings.HasPrefix(p, headingMarker) {
+			flush()
+			section = truncateRunes(strings.TrimPrefix(p, headingMarker), 255)
+			cleaned = append(cleaned, []rune(strings.TrimPrefix(p, headingMarker))...)
+			continue
+		}
+
+		pStart := len(cleaned)
+		runes := []rune(p)
+		cleaned = append(cleaned, runes...)
+
+		if len(runes) > maxChunkRunes {
+			flush()
+			for _, piece := range splitLongParagraph(runes) {
+				start, end = pStart+piece[0], pStart+piece[1]
+				flush()
+			}
+			continue
+		}
+
+		if start >= 0 && len(cleaned)-start > maxChunkRunes {
+			flush()
+		}
+		if start < 0 {
+			start = pStart
+		}
+		end = len(cleaned)
+	}
+	flush()
+
+	return string(cleaned), chunks
+}
+
+// splitLongParagraph returns [start, end) rune ranges of at most maxChunkRunes,
+// preferring to cut after a sentence end, then at whitespace
+func splitLongParagraph(runes []rune) [][2]int {
+	var pieces [][2]int
+	start := 0
+
+	for len(runes)-start > maxChunkRunes {
+		limit := start + maxChunkRunes
+		cut := -1
+		for i := limit - 1; i > start+maxChunkRunes/2; i-- {
+			if strings.ContainsRune(".!?", runes[i-1]) && unicode.IsSpace(runes[i]) {
+				cut = i
+				break
+			}
+		}
+		if cut < 0 {
+			for i := limit - 1; i > start+maxChunkRunes/2; i-- {
+				if unicode.IsSpace(runes[i]) {
+					cut = i
+					break
+				}
+			}
+		}
+		if cut < 0 {
+			cut = limit
+		}
+
+		pieces = append(pieces, [2]int{start, cut})
+		start = cut
+		for start < len(runes) && unicode.IsSpace(runes[start]) {
+			start++
+		}
+	}
+
+	if start < len(runes) {
+		pieces = append(pieces, [2]int{start, len(runes)})
+	}
+	return pieces
+}
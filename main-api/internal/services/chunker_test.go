@@ -0,0 +1,110 @@
+package services
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestChunkContent(t *testing.T) {
+	longParagraph := strings.Repeat("Máy lọc nước RO loại bỏ vi khuẩn và kim loại nặng. ", 40)
+
+	tests := []struct {
+		name        string
+		content     string
+		wantCleaned string
+		wantChunks  []ContentChunk // Offsets are checked against wantCleaned instead
+		// wantSentenceCuts replaces wantChunks for paragraphs too long for one chunk
+		wantSentenceCuts bool
+	}{
+		{
+			name:        "empty",
+			content:     "",
+			wantCleaned: "",
+		},
+		{
+			name:        "html headings start sections",
+			content:     "<h2>Giới thiệu</h2><p>Máy lọc nước.</p><p>Lõi RO.</p><h2>Bảo hành</h2><p>24 tháng.</p>",
+			wantCleaned: "Giới thiệu\n\nMáy lọc nước.\n\nLõi RO.\n\nBảo hành\n\n24 tháng.",
+			wantChunks: []ContentChunk{
+				{Index: 0, Section: "Giới thiệu", Content: "Máy lọc nước.\n\nLõi RO."},
+				{Index: 1, Section: "Bảo hành", Content: "24 tháng."},
+			},
+		},
+		{
+			name:        "markdown heading",
+			content:     "# Tính năng\nLọc sạch.",
+			wantCleaned: "Tính năng\n\nLọc sạch.",
+			wantChunks: []ContentChunk{
+				{Index: 0, Section: "Tính năng", Content: "Lọc sạch."},
+			},
+		},
+		{
+			name:        "scripts dropped and entities decoded",
+			content:     "<p>Lọc &amp; khoáng</p><script>track()</script><style>p{}</style>",
+			wantCleaned: "Lọc & khoáng",
+			wantChunks: []ContentChunk{
+				{Index: 0, Content: "Lọc & khoáng"},
+			},
+		},
+		{
+			name:        "text before the first heading has no section",
+			content:     "Mở đầu.\n\n## Chi tiết\n\nNội dung.",
+			wantCleaned: "Mở đầu.\n\nChi tiết\n\nNội dung.",
+			wantChunks: []ContentChunk{
+				{Index: 0, Content: "Mở đầu."},
+				{Index: 1, Section: "Chi tiết", Content: "Nội dung."},
+			},
+		},
+		{
+			name:             "long paragraph split at sentence ends",
+			content:          longParagraph,
+			wantCleaned:      strings.TrimSpace(longParagraph),
+			wantSentenceCuts: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cleaned, chunks := ChunkContent(tt.content)
+			if cleaned != tt.wantCleaned {
+				t.Errorf("cleaned = %q, want %q", cleaned, tt.wantCleaned)
+			}
+
+			runes := []rune(cleaned)
+			for i, chunk := range chunks {
+				if chunk.Index != i {
+					t.Errorf("chunk %d has index %d", i, chunk.Index)
+				}
+				if got := string(runes[chunk.StartOffset:chunk.EndOffset]); got != chunk.Content {
+					t.Errorf("chunk %d offsets select %q, want its content %q", i, got, chunk.Content)
+				}
+				if n := utf8.RuneCountInString(chunk.Content); n > maxChunkRunes {
+					t.Errorf("chunk %d has %d runes, more than %d", i, n, maxChunkRunes)
+				}
+			}
+
+			if tt.wantSentenceCuts {
+				if len(chunks) < 2 {
+					t.Fatalf("got %d chunks, want the paragraph split", len(chunks))
+				}
+				for i, chunk := range chunks {
+					if !strings.HasSuffix(chunk.Content, ".") {
+						t.Errorf("chunk %d = %q, want it cut after a sentence", i, chunk.Content)
+					}
+				}
+				return
+			}
+
+			if len(chunks) != len(tt.wantChunks) {
+				t.Fatalf("got %d chunks, want %d: %+v", len(chunks), len(tt.wantChunks), chunks)
+			}
+			for i, want := range tt.wantChunks {
+				got := chunks[i]
+				if got.Index != want.Index || got.Section != want.Section || got.Content != want.Content {
+					t.Errorf("chunk %d = {%d %q %q}, want {%d %q %q}", i, got.Index, got.Section, got.Content, want.Index, want.Section, want.Content)
+				}
+			}
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package services
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 
@@ -15,8 +16,8 @@ func NewContactService(db *sql.DB) *ContactService {
 	return &ContactService{db: db}
 }
 
-func (s *ContactService) GetContacts(status string, limit, offset int) ([]models.Contact, error) {
-	query := `SELECT id, name, email, phone, company, message, status, created_at FROM contacts WHERE 1=1`
+func (s *ContactService) GetContacts(ctx context.Context, status string, limit, offset int) ([]models.Contact, error) {
+	query := `SELECT id, name, email, phone, company, message, status, HOST(client_ip), created_at FROM contacts WHERE 1=1`
 	args := []interface{}{}
 	argPos := 1
 
@@ -29,7 +30,7 @@ func (s *ContactService) GetContacts(status string, limit, offset int) ([]models
 	query += fmt.Sprintf(" ORDER BY created_at DESC LIMIT $%d OFFSET $%d", argPos, argPos+1)
 	args = append(args, limit, offset)
 
-	rows, err := s.db.Query(query, args...)
+	rows, err := s.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -38,7 +39,7 @@ func (s *ContactService) GetContacts(status string, limit, offset int) ([]models
 	var contacts []models.Contact
 	for rows.Next() {
 		var c models.Contact
-		err := rows.Scan(&c.ID, &c.Name, &c.Email, &c.Phone, &c.Company, &c.Message, &c.Status, &c.CreatedAt)
+		err := rows.Scan(&c.ID, &c.Name, &c.Email, &c.Phone, &c.Company, &c.Message, &c.Status, &c.ClientIP, &c.CreatedAt)
 		if err != nil {
 			return nil, err
 		}
@@ -48,30 +49,36 @@ func (s *ContactService) GetContacts(status string, limit, offset int) ([]models
 	return contacts, nil
 }
 
-func (s *ContactService) GetContactByID(id int) (*models.Contact, error) {
-	query := `SELECT id, name, email, phone, company, message, status, created_at FROM contacts WHERE id = $1`
+func (s *ContactService) GetContactByID(ctx context.Context, id int) (*models.Contact, error) {
+	query := `SELECT id, name, email, phone, company, message, status, HOST(client_ip), created_at FROM contacts WHERE id = $1`
 	var c models.Contact
-	err := s.db.QueryRow(query, id).Scan(&c.ID, &c.Name, &c.Email, &c.Phone, &c.Company, &c.Message, &c.Status, &c.CreatedAt)
+	err := s.db.QueryRowContext(ctx, query, id).Scan(&c.ID, &c.Name, &c.Email, &c.Phone, &c.Company, &c.Message, &c.Status, &c.ClientIP, &c.CreatedAt)
 	if err != nil {
-		return nil, err
+		return nil, notFound(err, "contact_not_found")
 	}
 	return &c, nil
 }
 
-func (s *ContactService) CreateContact(c *models.Contact) error {
-	query := `INSERT INTO contacts (name, email, phone, company, message, status) VALUES ($1, $2, $3, $4, $5, $6) RETURNING id, created_at`
-	err := s.db.QueryRow(query, c.Name, c.Email, c.Phone, c.Company, c.Message, c.Status).Scan(&c.ID, &c.CreatedAt)
-	return err
+func (s *ContactService) CreateContact(ctx context.Context, c *models.Contact) error {
+	query := `INSERT INTO contacts (name, email, phone, company, message, status, client_ip) VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id, created_at`
+	err := s.db.QueryRowContext(ctx, query, c.Name, c.Email, c.Phone, c.Company, c.Message, c.Status, c.ClientIP).Scan(&c.ID, &c.CreatedAt)
+	return dbError(err)
 }
 
-func (s *ContactService) UpdateContactStatus(id int, status string) error {
+func (s *ContactService) UpdateContactStatus(ctx context.Context, id int, status string) error {
 	query := `UPDATE contacts SET status = $2 WHERE id = $1`
-	_, err := s.db.Exec(query, id, status)
-	return err
+	result, err := s.db.ExecContext(ctx, query, id, status)
+	if err != nil {
+		return dbError(err)
+	}
+	return affectedOne(result, "contact_not_found")
 }
 
-func (s *ContactService) DeleteContact(id int) error {
+func (s *ContactService) DeleteContact(ctx context.Context, id int) error {
 	query := `DELETE FROM contacts WHERE id = $1`
-	_, err := s.db.Exec(query, id)
-	return err
+	result, err := s.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return dbError(err)
+	}
+	return affectedOne(result, "contact_not_found")
 }
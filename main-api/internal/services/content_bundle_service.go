@@ -0,0 +1,924 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"bizgenie-api/internal/apperror"
+	"bizgenie-api/internal/models"
+
+	"github.com/lib/pq"
+)
+
+// ContentBundleVersion is written to exported bundles; imports accept versions up to it
+const ContentBundleVersion = 1
+
+// ContentBundleSections lists the sections of a bundle in import order
+var ContentBundleSections = []string{"categories", "products", "blog_categories", "blog_posts", "video_demos", "social_media_links"}
+
+var (
+	// ErrInvalidContentBundle is returned when a bundle is malformed or references missing content
+	ErrInvalidContentBundle = apperror.Validation("invalid_content_bundle")
+	// ErrInvalidContentSelection is returned when an export selects unknown content
+	ErrInvalidContentSelection = apperror.Validation("invalid_content_selection")
+)
+
+// ContentBundleService exports content with natural keys and imports it into another environment
+type ContentBundleService struct {
+	db *sql.DB
+}
+
+func NewContentBundleService(db *sql.DB) *ContentBundleService {
+	return &ContentBundleService{db: db}
+}
+
+// Export builds a bundle from one snapshot. selection maps a section to the keys to export,
+// "*" selecting all of them; sections missing from selection are left out. Categories and
+// blog categories referenced by exported items are always included.
+func (s *ContentBundleService) Export(ctx context.Context, selection map[string][]string) (*models.ContentBundle, error) {
+	for section := range selection {
+		if !slices.Contains(ContentBundleSections, section) {
+			return nil, ErrInvalidContentSelection.WithArgs("unknown section " + section)
+		}
+	}
+
+	tx, err := s.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	bundle := &models.ContentBundle{Version: ContentBundleVersion, ExportedAt: time.Now().UTC()}
+	var missing []string
+
+	if keys, ok := selectedKeys(selection, "products"); ok {
+		if bundle.Products, err = loadBundleProducts(ctx, tx, keys); err != nil {
+			return nil, err
+		}
+		missing = append(missing, missingKeys("products", keys, bundle.Products, func(p models.BundleProduct) string { return p.Slug })...)
+	}
+	if keys, ok := selectedKeys(selection, "blog_posts"); ok {
+		if bundle.BlogPosts, err = loadBundleBlogPosts(ctx, tx, keys); err != nil {
+			return nil, err
+		}
+		missing = append(missing, missingKeys("blog_posts", keys, bundle.BlogPosts, func(p models.BundleBlogPost) string { return p.Slug })...)
+	}
+	if keys, ok := selectedKeys(selection, "video_demos"); ok {
+		if bundle.VideoDemos, err = loadBundleVideoDemos(ctx, tx, keys); err != nil {
+			return nil, err
+		}
+		missing = append(missing, missingKeys("video_demos", keys, bundle.VideoDemos, func(v models.BundleVideoDemo) string { return v.VideoURL })...)
+	}
+	if keys, ok := selectedKeys(selection, "social_media_links"); ok {
+		if bundle.SocialMediaLinks, err = loadBundleSocialMediaLinks(ctx, tx, keys); err != nil {
+			return nil, err
+		}
+		missing = append(missing, missingKeys("social_media_links", keys, bundle.SocialMediaLinks, func(l models.BundleSocialMediaLink) string { return l.Platform })...)
+	}
+
+	// Categories: the selected ones, those of exported products, and their ancestors
+	categories, err := loadBundleCategories(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+	wanted := map[string]bool{}
+	if keys, ok := selectedKeys(selection, "categories"); ok {
+		for _, c := range categories {
+			if keys == nil || slices.Contains(keys, c.Slug) {
+				wanted[c.Slug] = true
+			}
+		}
+		missing = append(missing, missingKeys("categories", keys, categories, func(c models.BundleCategory) string { return c.Slug })...)
+	}
+	for _, p := range bundle.Products {
+		wanted[p.CategorySlug] = true
+	}
+	parents := map[string]*string{}
+	for _, c := range categories {
+		parents[c.Slug] = c.ParentSlug
+	}
+	for changed := true; changed; {
+		changed = false
+		for slug := range wanted {
+			if parent := parents[slug]; parent != nil && !wanted[*parent] {
+				wanted[*parent] = true
+				changed = true
+			}
+		}
+	}
+	for _, c := range categories {
+		if wanted[c.Slug] {
+			bundle.Categories = append(bundle.Categories, c)
+		}
+	}
+
+	// Blog categories: the selected ones and those of exported posts
+	blogCategories, err := loadBundleBlogCategories(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+	wanted = map[string]bool{}
+	if keys, ok := selectedKeys(selection, "blog_categories"); ok {
+		for _, c := range blogCategories {
+			if keys == nil || slices.Contains(keys, c.Slug) {
+				wanted[c.Slug] = true
+			}
+		}
+		missing = append(missing, missingKeys("blog_categories", keys, blogCategories, func(c models.BundleBlogCategory) string { return c.Slug })...)
+	}
+	for _, p := range bundle.BlogPosts {
+		if p.CategorySlug != nil {
+			wanted[*p.CategorySlug] = true
+		}
+	}
+	for _, c := range blogCategories {
+		if wanted[c.Slug] {
+			bundle.BlogCategories = append(bundle.BlogCategories, c)
+		}
+	}
+
+	if len(missing) > 0 {
+		return nil, ErrInvalidContentSelection.WithArgs("not found: " + strings.Join(missing, ", "))
+	}
+	return bundle, nil
+}
+
+// selectedKeys returns the keys selected for a section and whether it is selected at all.
+// nil keys select the whole section.
+func selectedKeys(selection map[string][]string, section string) ([]string, bool) {
+	keys, ok := selection[section]
+	if !ok || slices.Contains(keys, "*") {
+		return nil, ok
+	}
+	return keys, true
+}
+
+// missingKeys returns the selected keys of a section that no loaded item has
+func missingKeys[T any](section string, keys []string, items []T, key func(T) string) []string {
+	found := map[string]bool{}
+	for _, item := range items {
+		found[key(item)] = true
+	}
+	var missing []string
+	for _, k := range keys {
+		if !found[k] {
+			missing = append(missing, section+"/"+k)
+		}
+	}
+	return missing
+}
+
+func loadBundleCategories(ctx context.Context, q queryer) ([]models.BundleCategory, error) {
+	query := `
+		SELECT c.slug, c.name, c.description, p.slug, COALESCE(c."order", 0)
+		FROM categories c
+		LEFT JOIN categories p ON p.id = c.parent_id
+		ORDER BY c."order", c.id
+	`
+	rows, err := q.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var categories []models.BundleCategory
+	for rows.Next() {
+		var c models.BundleCategory
+		if err := rows.Scan(&c.Slug, &c.Name, &c.Description, &c.ParentSlug, &c.Order); err != nil {
+			return nil, err
+		}
+		categories = append(categories, c)
+	}
+	return categories, rows.Err()
+}
+
+// loadBundleProducts loads the products with the given slugs, or all products for nil slugs
+func loadBundleProducts(ctx context.Context, q queryer, slugs []string) ([]models.BundleProduct, error) {
+	query := `
+		SELECT p.slug, p.name, p.short_description, p.description, c.slug,
+		       p.image_urls, p.features, p.specifications, p.status
+		FROM products p
+		JOIN categories c ON c.id = p.category_id
+		WHERE $1::text[] IS NULL OR p.slug = ANY($1)
+		ORDER BY p.id
+	`
+	rows, err := q.QueryContext(ctx, query, pq.Array(slugs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var products []models.BundleProduct
+	for rows.Next() {
+		var p models.BundleProduct
+		err := rows.Scan(&p.Slug, &p.Name, &p.ShortDescription, &p.Description, &p.CategorySlug,
+			&p.ImageURLs, &p.Features, &p.Specifications, &p.Status)
+		if err != nil {
+			return nil, err
+		}
+		products = append(products, p)
+	}
+	return products, rows.Err()
+}
+
+func loadBundleBlogCategories(ctx context.Context, q queryer) ([]models.BundleBlogCategory, error) {
+	query := `SELECT slug, name, description, COALESCE("order", 0) FROM blog_categories ORDER BY "order", id`
+	rows, err := q.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var categories []models.BundleBlogCategory
+	for rows.Next() {
+		var c models.BundleBlogCategory
+		if err := rows.Scan(&c.Slug, &c.Name, &c.Description, &c.Order); err != nil {
+			return nil, err
+		}
+		categories = append(categories, c)
+	}
+	return categories, rows.Err()
+}
+
+// loadBundleBlogPosts loads the posts with the given slugs, or all posts for nil slugs
+func loadBundleBlogPosts(ctx context.Context, q queryer, slugs []string) ([]models.BundleBlogPost, error) {
+	query := `
+		SELECT b.slug, b.title, b.excerpt, b.content, b.featured_image, u.username,
+		       bc.slug, b.status, b.published_at
+		FROM blog_posts b
+		JOIN users u ON u.id = b.author_id
+		LEFT JOIN blog_categories bc ON bc.id = b.category_id
+		WHERE $1::text[] IS NULL OR b.slug = ANY($1)
+		ORDER BY b.id
+	`
+	rows, err := q.QueryContext(ctx, query, pq.Array(slugs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var posts []models.BundleBlogPost
+	for rows.Next() {
+		var p models.BundleBlogPost
+		var publishedAt sql.NullTime
+		err := rows.Scan(&p.Slug, &p.Title, &p.Excerpt, &p.Content, &p.FeaturedImage, &p.AuthorUsername,
+			&p.CategorySlug, &p.Status, &publishedAt)
+		if err != nil {
+			return nil, err
+		}
+		if publishedAt.Valid {
+			p.PublishedAt = &models.FlexibleTime{Time: publishedAt.Time}
+		}
+		posts = append(posts, p)
+	}
+	return posts, rows.Err()
+}
+
+// loadBundleVideoDemos loads the video demos with the given URLs, or all of them for nil urls
+func loadBundleVideoDemos(ctx context.Context, q queryer, urls []string) ([]models.BundleVideoDemo, error) {
+	query := `
+		SELECT video_url, title, description, video_type, youtube_id, thumbnail_url, status, COALESCE("order", 0)
+		FROM video_demos
+		WHERE $1::text[] IS NULL OR video_url = ANY($1)
+		ORDER BY "order", id
+	`
+	rows, err := q.QueryContext(ctx, query, pq.Array(urls))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var demos []models.BundleVideoDemo
+	for rows.Next() {
+		var v models.BundleVideoDemo
+		err := rows.Scan(&v.VideoURL, &v.Title, &v.Description, &v.VideoType, &v.YouTubeID,
+			&v.ThumbnailURL, &v.Status, &v.Order)
+		if err != nil {
+			return nil, err
+		}
+		demos = append(demos, v)
+	}
+	return demos, rows.Err()
+}
+
+// loadBundleSocialMediaLinks loads the links of the given platforms, or all links for nil platforms
+func loadBundleSocialMediaLinks(ctx context.Context, q queryer, platforms []string) ([]models.BundleSocialMediaLink, error) {
+	query := `
+		SELECT platform, url, icon_name, COALESCE("order", 0), is_active
+		FROM social_media_links
+		WHERE $1::text[] IS NULL OR platform = ANY($1)
+		ORDER BY "order", id
+	`
+	rows, err := q.QueryContext(ctx, query, pq.Array(platforms))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var links []models.BundleSocialMediaLink
+	for rows.Next() {
+		var l models.BundleSocialMediaLink
+		if err := rows.Scan(&l.Platform, &l.URL, &l.IconName, &l.Order, &l.IsActive); err != nil {
+			return nil, err
+		}
+		links = append(links, l)
+	}
+	return links, rows.Err()
+}
+
+// bundleImport carries the state of one import
+type bundleImport struct {
+	ctx      context.Context
+	tx       *sql.Tx
+	result   *models.BundleImportResult
+	problems []string
+}
+
+// record adds a change to the result and reports whether the item needs writing
+func (imp *bundleImport) record(section, key string, existing, incoming interface{}) (bool, error) {
+	return recordChange(imp.result, section, key, existing, incoming)
+}
+
+// recordChange adds the change from existing (a nil pointer when the item is new) to
+// incoming to result and reports whether the item needs writing
+func recordChange(result *models.BundleImportResult, section, key string, existing, incoming interface{}) (bool, error) {
+	change := models.BundleChange{Type: section, Key: key, Action: "create"}
+	if !reflect.ValueOf(existing).IsNil() {
+		fields, err := diffFields(existing, incoming)
+		if err != nil {
+			return false, err
+		}
+		change.Action = "update"
+		change.Fields = fields
+		if len(fields) == 0 {
+			change.Action = "unchanged"
+		}
+	}
+
+	switch change.Action {
+	case "create":
+		result.Created++
+	case "update":
+		result.Updated++
+	default:
+		result.Unchanged++
+	}
+	result.Changes = append(result.Changes, change)
+	return change.Action != "unchanged", nil
+}
+
+func (imp *bundleImport) problem(format string, args ...interface{}) {
+	imp.problems = append(imp.problems, fmt.Sprintf(format, args...))
+}
+
+// lookupID returns the id of the row found by query, or 0 when there is none
+func (imp *bundleImport) lookupID(query string, args ...interface{}) (int, error) {
+	var id int
+	err := imp.tx.QueryRowContext(imp.ctx, query, args...).Scan(&id)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+	return id, err
+}
+
+// Import upserts a bundle by natural key in one transaction, resolving category and author
+// references by slug and username. With dryRun the transaction is rolled back, so the
+// result previews the changes. Products and blog posts written are listed in the result.
+func (s *ContentBundleService) Import(ctx context.Context, bundle *models.ContentBundle, dryRun bool) (*models.BundleImportResult, error) {
+	if problems := validateBundle(bundle); len(problems) > 0 {
+		return nil, ErrInvalidContentBundle.WithArgs(strings.Join(problems, "; "))
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	imp := &bundleImport{
+		ctx:    ctx,
+		tx:     tx,
+		result: &models.BundleImportResult{DryRun: dryRun, Changes: []models.BundleChange{}},
+	}
+
+	steps := []func(*models.ContentBundle) error{
+		imp.importCategories,
+		imp.importProducts,
+		imp.importBlogCategories,
+		imp.importBlogPosts,
+		imp.importVideoDemos,
+		imp.importSocialMediaLinks,
+	}
+	for _, step := range steps {
+		if err := step(bundle); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(imp.problems) > 0 {
+		return nil, ErrInvalidContentBundle.WithArgs(strings.Join(imp.problems, "; "))
+	}
+
+	if dryRun {
+		imp.result.ProductIDs = nil
+		imp.result.BlogPostIDs = nil
+		return imp.result, nil
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return imp.result, nil
+}
+
+// validateBundle checks the version, required fields and duplicate keys of a bundle
+func validateBundle(bundle *models.ContentBundle) []string {
+	var problems []string
+	if bundle.Version < 1 || bundle.Version > ContentBundleVersion {
+		return []string{fmt.Sprintf("unsupported bundle version %d", bundle.Version)}
+	}
+
+	check := func(section string, keys []string, required map[string]bool) {
+		seen := map[string]bool{}
+		for i, key := range keys {
+			if key == "" {
+				problems = append(problems, fmt.Sprintf("%s[%d]: missing key", section, i))
+				continue
+			}
+			if seen[key] {
+				problems = append(problems, fmt.Sprintf("%s/%s: duplicate", section, key))
+			}
+			seen[key] = true
+		}
+		var fields []string
+		for field, ok := range required {
+			if !ok {
+				fields = append(fields, field)
+			}
+		}
+		sort.Strings(fields)
+		for _, field := range fields {
+			problems = append(problems, fmt.Sprintf("%s: %s is required", section, field))
+		}
+	}
+
+	var keys []string
+	required := map[string]bool{"name": true}
+	for _, c := range bundle.Categories {
+		keys = append(keys, c.Slug)
+		required["name"] = required["name"] && c.Name != ""
+	}
+	check("categories", keys, required)
+
+	keys = nil
+	required = map[string]bool{"name": true, "category_slug": true}
+	for _, p := range bundle.Products {
+		keys = append(keys, p.Slug)
+		required["name"] = required["name"] && p.Name != ""
+		required["category_slug"] = required["category_slug"] && p.CategorySlug != ""
+	}
+	check("products", keys, required)
+
+	keys = nil
+	required = map[string]bool{"name": true}
+	for _, c := range bundle.BlogCategories {
+		keys = append(keys, c.Slug)
+		required["name"] = required["name"] && c.Name != ""
+	}
+	check("blog_categories", keys, required)
+
+	keys = nil
+	required = map[string]bool{"title": true, "content": true, "author_username": true}
+	for _, p := range bundle.BlogPosts {
+		keys = append(keys, p.Slug)
+		required["title"] = required["title"] && p.Title != ""
+		required["content"] = required["content"] && p.Content != ""
+		required["author_username"] = required["author_username"] && p.AuthorUsername != ""
+	}
+	check("blog_posts", keys, required)
+
+	keys = nil
+	required = map[string]bool{"title": true}
+	for _, v := range bundle.VideoDemos {
+		keys = append(keys, v.VideoURL)
+		required["title"] = required["title"] && v.Title != ""
+	}
+	check("video_demos", keys, required)
+
+	keys = nil
+	required = map[string]bool{"url": true}
+	for _, l := range bundle.SocialMediaLinks {
+		keys = append(keys, l.Platform)
+		required["url"] = required["url"] && l.URL != ""
+	}
+	check("social_media_links", keys, required)
+
+	return problems
+}
+
+func (imp *bundleImport) importCategories(bundle *models.ContentBundle) error {
+	if len(bundle.Categories) == 0 {
+		return nil
+	}
+
+	existing, err := loadBundleCategories(imp.ctx, imp.tx)
+	if err != nil {
+		return err
+	}
+	bySlug := map[string]*models.BundleCategory{}
+	for i := range existing {
+		bySlug[existing[i].Slug] = &existing[i]
+	}
+
+	// Parents are set in a second pass so categories may come in any order
+	var written []models.BundleCategory
+	for _, c := range bundle.Categories {
+		write, err := imp.record("categories", c.Slug, bySlug[c.Slug], c)
+		if err != nil {
+			return err
+		}
+		if !write {
+			continue
+		}
+
+		query := `
+			INSERT INTO categories (name, slug, description, "order") VALUES ($1, $2, $3, $4)
+			ON CONFLICT (slug) DO UPDATE SET name = EXCLUDED.name, description = EXCLUDED.description, "order" = EXCLUDED."order"
+		`
+		if _, err := imp.tx.ExecContext(imp.ctx, query, c.Name, c.Slug, c.Description, c.Order); err != nil {
+			return fmt.Errorf("failed to import category %s: %w", c.Slug, err)
+		}
+		written = append(written, c)
+	}
+
+	for _, c := range written {
+		var parentID *int
+		if c.ParentSlug != nil {
+			if *c.ParentSlug == c.Slug {
+				imp.problem("categories/%s: category cannot be its own parent", c.Slug)
+				continue
+			}
+			id, err := imp.lookupID(`SELECT id FROM categories WHERE slug = $1`, *c.ParentSlug)
+			if err != nil {
+				return err
+			}
+			if id == 0 {
+				imp.problem("categories/%s: parent category %s not found", c.Slug, *c.ParentSlug)
+				continue
+			}
+			parentID = &id
+		}
+		if _, err := imp.tx.ExecContext(imp.ctx, `UPDATE categories SET parent_id = $2 WHERE slug = $1`, c.Slug, parentID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (imp *bundleImport) importProducts(bundle *models.ContentBundle) error {
+	if len(bundle.Products) == 0 {
+		return nil
+	}
+
+	slugs := make([]string, len(bundle.Products))
+	for i, p := range bundle.Products {
+		slugs[i] = p.Slug
+	}
+	existing, err := loadBundleProducts(imp.ctx, imp.tx, slugs)
+	if err != nil {
+		return err
+	}
+	bySlug := map[string]*models.BundleProduct{}
+	for i := range existing {
+		bySlug[existing[i].Slug] = &existing[i]
+	}
+
+	for _, p := range bundle.Products {
+		if p.Status == "" {
+			p.Status = "draft"
+		}
+		write, err := imp.record("products", p.Slug, bySlug[p.Slug], p)
+		if err != nil {
+			return err
+		}
+		if !write {
+			continue
+		}
+
+		categoryID, err := imp.lookupID(`SELECT id FROM categories WHERE slug = $1`, p.CategorySlug)
+		if err != nil {
+			return err
+		}
+		if categoryID == 0 {
+			imp.problem("products/%s: category %s not found", p.Slug, p.CategorySlug)
+			continue
+		}
+
+		query := `
+			INSERT INTO products (name, slug, short_description, description, category_id,
+			                      image_urls, features, specifications, status)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+			ON CONFLICT (slug) DO UPDATE
+			SET name = EXCLUDED.name, short_description = EXCLUDED.short_description,
+			    description = EXCLUDED.description, category_id = EXCLUDED.category_id,
+			    image_urls = EXCLUDED.image_urls, features = EXCLUDED.features,
+			    specifications = EXCLUDED.specifications, status = EXCLUDED.status,
+			    updated_at = CURRENT_TIMESTAMP
+			RETURNING id
+		`
+		var id int
+		err = imp.tx.QueryRowContext(imp.ctx, query, p.Name, p.Slug, p.ShortDescription, p.Description, categoryID,
+			p.ImageURLs, p.Features, p.Specifications, p.Status,
+		).Scan(&id)
+		if err != nil {
+			return fmt.Errorf("failed to import product %s: %w", p.Slug, err)
+		}
+		imp.result.ProductIDs = append(imp.result.ProductIDs, id)
+	}
+	return nil
+}
+
+func (imp *bundleImport) importBlogCategories(bundle *models.ContentBundle) error {
+	if len(bundle.BlogCategories) == 0 {
+		return nil
+	}
+
+	existing, err := loadBundleBlogCategories(imp.ctx, imp.tx)
+	if err != nil {
+		return err
+	}
+	bySlug := map[string]*models.BundleBlogCategory{}
+	for i := range existing {
+		bySlug[existing[i].Slug] = &existing[i]
+	}
+
+	for _, c := range bundle.BlogCategories {
+		write, err := imp.record("blog_categories", c.Slug, bySlug[c.Slug], c)
+		if err != nil {
+			return err
+		}
+		if !write {
+			continue
+		}
+
+		query := `
+			INSERT INTO blog_categories (name, slug, description, "order") VALUES ($1, $2, $3, $4)
+			ON CONFLICT (slug) DO UPDATE SET name = EXCLUDED.name, description = EXCLUDED.description, "order" = EXCLUDED."order"
+		`
+		if _, err := imp.tx.ExecContext(imp.ctx, query, c.Name, c.Slug, c.Description, c.Order); err != nil {
+			return fmt.Errorf("failed to import blog category %s: %w", c.Slug, err)
+		}
+	}
+	return nil
+}
+
+func (imp *bundleImport) importBlogPosts(bundle *models.ContentBundle) error {
+	if len(bundle.BlogPosts) == 0 {
+		return nil
+	}
+
+	slugs := make([]string, len(bundle.BlogPosts))
+	for i, p := range bundle.BlogPosts {
+		slugs[i] = p.Slug
+	}
+	existing, err := loadBundleBlogPosts(imp.ctx, imp.tx, slugs)
+	if err != nil {
+		return err
+	}
+	bySlug := map[string]*models.BundleBlogPost{}
+	for i := range existing {
+		bySlug[existing[i].Slug] = &existing[i]
+	}
+
+	for _, p := range bundle.BlogPosts {
+		if p.Status == "" {
+			p.Status = "draft"
+		}
+		// Like CreateBlogPost, a missing publish time keeps the current one or defaults to now
+		current := bySlug[p.Slug]
+		if p.PublishedAt == nil || p.PublishedAt.Time.IsZero() {
+			p.PublishedAt = nil
+			if current != nil {
+				p.PublishedAt = current.PublishedAt
+			}
+		}
+		// Usernames match case-insensitively, as at login
+		if current != nil && strings.EqualFold(current.AuthorUsername, p.AuthorUsername) {
+			p.AuthorUsername = current.AuthorUsername
+		}
+
+		write, err := imp.record("blog_posts", p.Slug, current, p)
+		if err != nil {
+			return err
+		}
+		if !write {
+			continue
+		}
+
+		authorID, err := imp.lookupID(`SELECT id FROM users WHERE LOWER(username) = LOWER($1)`, p.AuthorUsername)
+		if err != nil {
+			return err
+		}
+		if authorID == 0 {
+			imp.problem("blog_posts/%s: author %s not found", p.Slug, p.AuthorUsername)
+			continue
+		}
+
+		var categoryID *int
+		if p.CategorySlug != nil {
+			id, err := imp.lookupID(`SELECT id FROM blog_categories WHERE slug = $1`, *p.CategorySlug)
+			if err != nil {
+				return err
+			}
+			if id == 0 {
+				imp.problem("blog_posts/%s: blog category %s not found", p.Slug, *p.CategorySlug)
+				continue
+			}
+			categoryID = &id
+		}
+
+		var publishedAt interface{} = time.Now()
+		if p.PublishedAt != nil {
+			publishedAt = p.PublishedAt.Time
+		}
+
+		query := `
+			INSERT INTO blog_posts (title, slug, excerpt, content, featured_image, author_id, category_id, status, published_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+			ON CONFLICT (slug) DO UPDATE
+			SET title = EXCLUDED.title, excerpt = EXCLUDED.excerpt, content = EXCLUDED.content,
+			    featured_image = EXCLUDED.featured_image, author_id = EXCLUDED.author_id,
+			    category_id = EXCLUDED.category_id, status = EXCLUDED.status,
+			    published_at = EXCLUDED.published_at, updated_at = CURRENT_TIMESTAMP
+			RETURNING id
+		`
+		var id int
+		err = imp.tx.QueryRowContext(imp.ctx, query, p.Title, p.Slug, p.Excerpt, p.Content, p.FeaturedImage,
+			authorID, categoryID, p.Status, publishedAt,
+		).Scan(&id)
+		if err != nil {
+			return fmt.Errorf("failed to import blog post %s: %w", p.Slug, err)
+		}
+		imp.result.BlogPostIDs = append(imp.result.BlogPostIDs, id)
+	}
+	return nil
+}
+
+func (imp *bundleImport) importVideoDemos(bundle *models.ContentBundle) error {
+	if len(bundle.VideoDemos) == 0 {
+		return nil
+	}
+
+	urls := make([]string, len(bundle.VideoDemos))
+	for i, v := range bundle.VideoDemos {
+		urls[i] = v.VideoURL
+	}
+	existing, err := loadBundleVideoDemos(imp.ctx, imp.tx, urls)
+	if err != nil {
+		return err
+	}
+	// video_url is not unique in the schema, so duplicates cannot be matched safely
+	byURL := map[string]*models.BundleVideoDemo{}
+	duplicated := map[string]bool{}
+	for i := range existing {
+		if byURL[existing[i].VideoURL] != nil {
+			duplicated[existing[i].VideoURL] = true
+		}
+		byURL[existing[i].VideoURL] = &existing[i]
+	}
+
+	for _, v := range bundle.VideoDemos {
+		if duplicated[v.VideoURL] {
+			imp.problem("video_demos/%s: several video demos have this URL", v.VideoURL)
+			continue
+		}
+		if v.VideoType == "" {
+			v.VideoType = "url"
+		}
+		if v.Status == "" {
+			v.Status = "draft"
+		}
+		write, err := imp.record("video_demos", v.VideoURL, byURL[v.VideoURL], v)
+		if err != nil {
+			return err
+		}
+		if !write {
+			continue
+		}
+
+		query := `
+			INSERT INTO video_demos (title, description, video_url, video_type, youtube_id, thumbnail_url, status, "order")
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		`
+		if byURL[v.VideoURL] != nil {
+			query = `
+				UPDATE video_demos
+				SET title = $1, description = $2, video_type = $4, youtube_id = $5,
+				    thumbnail_url = $6, status = $7, "order" = $8, updated_at = CURRENT_TIMESTAMP
+				WHERE video_url = $3
+			`
+		}
+		_, err = imp.tx.ExecContext(imp.ctx, query, v.Title, v.Description, v.VideoURL, v.VideoType,
+			v.YouTubeID, v.ThumbnailURL, v.Status, v.Order)
+		if err != nil {
+			return fmt.Errorf("failed to import video demo %s: %w", v.VideoURL, err)
+		}
+	}
+	return nil
+}
+
+func (imp *bundleImport) importSocialMediaLinks(bundle *models.ContentBundle) error {
+	if len(bundle.SocialMediaLinks) == 0 {
+		return nil
+	}
+
+	platforms := make([]string, len(bundle.SocialMediaLinks))
+	for i, l := range bundle.SocialMediaLinks {
+		platforms[i] = l.Platform
+	}
+	existing, err := loadBundleSocialMediaLinks(imp.ctx, imp.tx, platforms)
+	if err != nil {
+		return err
+	}
+	// platform is not unique in the schema, so duplicates cannot be matched safely
+	byPlatform := map[string]*models.BundleSocialMediaLink{}
+	duplicated := map[string]bool{}
+	for i := range existing {
+		if byPlatform[existing[i].Platform] != nil {
+			duplicated[existing[i].Platform] = true
+		}
+		byPlatform[existing[i].Platform] = &existing[i]
+	}
+
+	for _, l := range bundle.SocialMediaLinks {
+		if duplicated[l.Platform] {
+			imp.problem("social_media_links/%s: several links exist for this platform", l.Platform)
+			continue
+		}
+		write, err := imp.record("social_media_links", l.Platform, byPlatform[l.Platform], l)
+		if err != nil {
+			return err
+		}
+		if !write {
+			continue
+		}
+
+		query := `
+			INSERT INTO social_media_links (platform, url, icon_name, "order", is_active)
+			VALUES ($1, $2, $3, $4, $5)
+		`
+		if byPlatform[l.Platform] != nil {
+			query = `
+				UPDATE social_media_links
+				SET url = $2, icon_name = $3, "order" = $4, is_active = $5, updated_at = CURRENT_TIMESTAMP
+				WHERE platform = $1
+			`
+		}
+		if _, err := imp.tx.ExecContext(imp.ctx, query, l.Platform, l.URL, l.IconName, l.Order, l.IsActive); err != nil {
+			return fmt.Errorf("failed to import social media link %s: %w", l.Platform, err)
+		}
+	}
+	return nil
+}
+
+// diffFields returns the JSON fields whose values differ between two bundle items.
+// Values are compared after decoding, so JSON key order and formatting do not count.
+func diffFields(existing, incoming interface{}) ([]string, error) {
+	decode := func(v interface{}) (map[string]interface{}, error) {
+		data, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		fields := map[string]interface{}{}
+		return fields, json.Unmarshal(data, &fields)
+	}
+
+	before, err := decode(existing)
+	if err != nil {
+		return nil, err
+	}
+	after, err := decode(incoming)
+	if err != nil {
+		return nil, err
+	}
+
+	var changed []string
+	for field, value := range after {
+		if !reflect.DeepEqual(before[field], value) {
+			changed = append(changed, field)
+		}
+	}
+	for field := range before {
+		if _, ok := after[field]; !ok {
+			changed = append(changed, field)
+		}
+	}
+	sort.Strings(changed)
+	return changed, nil
+}
@@ -0,0 +1,84 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"strings"
+
+	"bizgenie-api/internal/apperror"
+
+	"github.com/lib/pq"
+)
+
+// notFound returns the not found error with code for sql.ErrNoRows, keeping it as the
+// cause so errors.Is(err, sql.ErrNoRows) still holds, and dbError(err) for anything else
+func notFound(err error, code string) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return apperror.NotFound(code).Wrap(err)
+	}
+	return dbError(err)
+}
+
+// affectedOne returns the not found error with code when an UPDATE or DELETE matched no row
+func affectedOne(result sql.Result, code string) error {
+	n, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return apperror.NotFound(code).Wrap(sql.ErrNoRows)
+	}
+	return nil
+}
+
+// dbError turns constraint violations into errors the client can act on: a taken slug,
+// username or email is a conflict, a reference to a missing row a validation error, and
+// deleting a row others still reference a conflict. Other errors are returned as is.
+func dbError(err error) error {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return err
+	}
+	column := keyColumn(pqErr.Detail)
+	switch pqErr.Code.Name() {
+	case "unique_violation":
+		switch column {
+		case "slug", "username", "email":
+			return apperror.Conflict(column + "_taken").
+				WithFields(apperror.FieldError{Field: column, Code: "taken"}).Wrap(err)
+		}
+		return apperror.Conflict("duplicate_value").Wrap(err)
+	case "foreign_key_violation":
+		if strings.Contains(pqErr.Detail, "still referenced") {
+			return apperror.Conflict("resource_in_use").Wrap(err)
+		}
+		if column != "" {
+			return apperror.Invalid(apperror.FieldError{Field: column, Code: "not_found"}).Wrap(err)
+		}
+		return apperror.Validation("invalid_value").Wrap(err)
+	case "not_null_violation":
+		if pqErr.Column != "" {
+			return apperror.Invalid(apperror.FieldError{Field: pqErr.Column, Code: "required"}).Wrap(err)
+		}
+		return apperror.Validation("invalid_value").Wrap(err)
+	case "check_violation", "invalid_text_representation", "data_exception":
+		return apperror.Validation("invalid_value").Wrap(err)
+	case "string_data_right_truncation":
+		return apperror.Validation("value_too_long").Wrap(err)
+	}
+	return err
+}
+
+// keyColumn returns the column named in the detail of a constraint violation, such as
+// "slug" in `Key (slug)=(ai-chatbot) already exists.`
+func keyColumn(detail string) string {
+	rest, ok := strings.CutPrefix(detail, "Key (")
+	if !ok {
+		return ""
+	}
+	column, _, ok := strings.Cut(rest, ")=")
+	if !ok || strings.Contains(column, ",") {
+		return ""
+	}
+	return column
+}
@@ -0,0 +1,84 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"bizgenie-api/internal/metrics"
+	"bizgenie-api/internal/models"
+)
+
+type FAQService struct {
+	db *sql.DB
+}
+
+func NewFAQService(db *sql.DB) *FAQService {
+	return &FAQService{db: db}
+}
+
+func (s *FAQService) GetFAQs(ctx context.Context, status string) ([]models.FAQ, error) {
+	defer metrics.ObserveQuery("faqs.list", time.Now())
+	query := `SELECT id, question, answer, status, "order", created_at, updated_at FROM faqs`
+	args := []interface{}{}
+	if status != "" {
+		query += " WHERE status = $1"
+		args = append(args, status)
+	}
+	query += " ORDER BY \"order\", created_at"
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var faqs []models.FAQ
+	for rows.Next() {
+		var f models.FAQ
+		err := rows.Scan(&f.ID, &f.Question, &f.Answer, &f.Status, &f.Order, &f.CreatedAt, &f.UpdatedAt)
+		if err != nil {
+			return nil, err
+		}
+		faqs = append(faqs, f)
+	}
+
+	return faqs, nil
+}
+
+func (s *FAQService) GetFAQByID(ctx context.Context, id int) (*models.FAQ, error) {
+	defer metrics.ObserveQuery("faqs.get_by_id", time.Now())
+	query := `SELECT id, question, answer, status, "order", created_at, updated_at FROM faqs WHERE id = $1`
+	var f models.FAQ
+	err := s.db.QueryRowContext(ctx, query, id).Scan(&f.ID, &f.Question, &f.Answer, &f.Status, &f.Order, &f.CreatedAt, &f.UpdatedAt)
+	if err != nil {
+		return nil, notFound(err, "faq_not_found")
+	}
+	return &f, nil
+}
+
+func (s *FAQService) CreateFAQ(ctx context.Context, f *models.FAQ) error {
+	query := `INSERT INTO faqs (question, answer, status, "order") VALUES ($1, $2, $3, $4) RETURNING id, created_at, updated_at`
+	err := s.db.QueryRowContext(ctx, query, f.Question, f.Answer, f.Status, f.Order).Scan(&f.ID, &f.CreatedAt, &f.UpdatedAt)
+	return dbError(err)
+}
+
+// UpdateFAQ updates a FAQ and clears its embedding so it is recomputed from the new text
+func (s *FAQService) UpdateFAQ(ctx context.Context, id int, f *models.FAQ) error {
+	query := `UPDATE faqs SET question = $2, answer = $3, status = $4, "order" = $5, embedding = NULL, updated_at = CURRENT_TIMESTAMP WHERE id = $1 RETURNING created_at, updated_at`
+	err := s.db.QueryRowContext(ctx, query, id, f.Question, f.Answer, f.Status, f.Order).Scan(&f.CreatedAt, &f.UpdatedAt)
+	if err != nil {
+		return notFound(err, "faq_not_found")
+	}
+	f.ID = id
+	return nil
+}
+
+func (s *FAQService) DeleteFAQ(ctx context.Context, id int) error {
+	query := `DELETE FROM faqs WHERE id = $1`
+	result, err := s.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return dbError(err)
+	}
+	return affectedOne(result, "faq_not_found")
+}
@@ -0,0 +1,174 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"bizgenie-api/internal/database"
+	"bizgenie-api/internal/logger"
+	"bizgenie-api/internal/models"
+)
+
+// notifierCheckInterval limits how often readiness probes reach out to the Slack webhook
+const notifierCheckInterval = time.Minute
+
+// HealthService checks the dependencies a replica needs to serve traffic
+type HealthService struct {
+	db              *sql.DB
+	migrator        *database.Migrator
+	slackWebhookURL string
+	timeout         time.Duration
+	httpClient      *http.Client
+
+	mu             sync.Mutex
+	notifier       models.HealthComponent
+	notifierExpiry time.Time
+}
+
+// NewHealthService returns a HealthService bounding each check by timeout. migrator may be
+// nil when the embedded migrations cannot be loaded, which fails the migrations check.
+func NewHealthService(db *sql.DB, migrator *database.Migrator, slackWebhookURL string, timeout time.Duration) *HealthService {
+	return &HealthService{
+		db:              db,
+		migrator:        migrator,
+		slackWebhookURL: slackWebhookURL,
+		timeout:         timeout,
+		httpClient:      &http.Client{Timeout: timeout},
+	}
+}
+
+// healthCheck returns a detail message, or an error when the dependency is unusable.
+// Both are only logged: the report is public, so it carries status and latency only.
+type healthCheck struct {
+	name     string
+	critical bool
+	run      func(ctx context.Context) (string, error)
+}
+
+// Ready runs every check concurrently. The report is "fail" when a critical component
+// fails and "degraded" when only optional ones do, such as the Slack notifier.
+func (s *HealthService) Ready(ctx context.Context) *models.HealthReport {
+	checks := []healthCheck{
+		{name: "database", critical: true, run: s.checkDatabase},
+		{name: "pgvector", critical: true, run: s.checkPgvector},
+		{name: "migrations", critical: true, run: s.checkMigrations},
+	}
+
+	report := &models.HealthReport{Status: "ok", Components: map[string]models.HealthComponent{}}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, check := range checks {
+		wg.Add(1)
+		go func(check healthCheck) {
+			defer wg.Done()
+			component := s.runCheck(ctx, check)
+			mu.Lock()
+			report.Components[check.name] = component
+			mu.Unlock()
+		}(check)
+	}
+	notifier := s.checkNotifier(ctx)
+	wg.Wait()
+	report.Components["notifier"] = notifier
+
+	for _, component := range report.Components {
+		if component.Status != "fail" {
+			continue
+		}
+		if component.Critical {
+			report.Status = "fail"
+		} else if report.Status == "ok" {
+			report.Status = "degraded"
+		}
+	}
+	return report
+}
+
+func (s *HealthService) runCheck(ctx context.Context, check healthCheck) models.HealthComponent {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	start := time.Now()
+	detail, err := check.run(ctx)
+	component := models.HealthComponent{
+		Status:    "ok",
+		Critical:  check.critical,
+		LatencyMs: time.Since(start).Milliseconds(),
+	}
+	if err != nil {
+		component.Status = "fail"
+		logger.WarnContext(ctx, "Health check %s failed: %v", check.name, err)
+	} else {
+		logger.DebugContext(ctx, "Health check %s ok: %s", check.name, detail)
+	}
+	return component
+}
+
+func (s *HealthService) checkDatabase(ctx context.Context) (string, error) {
+	if err := s.db.PingContext(ctx); err != nil {
+		return "", err
+	}
+	stats := s.db.Stats()
+	return fmt.Sprintf("%d open, %d in use", stats.OpenConnections, stats.InUse), nil
+}
+
+func (s *HealthService) checkPgvector(ctx context.Context) (string, error) {
+	var version string
+	err := s.db.QueryRowContext(ctx, `SELECT extversion FROM pg_extension WHERE extname = 'vector'`).Scan(&version)
+	if err == sql.ErrNoRows {
+		return "", fmt.Errorf("vector extension is not installed")
+	}
+	if err != nil {
+		return "", err
+	}
+	return "version " + version, nil
+}
+
+func (s *HealthService) checkMigrations(ctx context.Context) (string, error) {
+	if s.migrator == nil {
+		return "", fmt.Errorf("embedded migrations could not be loaded")
+	}
+	pending, err := s.migrator.Pending(ctx)
+	if err != nil {
+		return "", err
+	}
+	if len(pending) > 0 {
+		return "", fmt.Errorf("%d pending: %s", len(pending), strings.Join(pending, ", "))
+	}
+	return fmt.Sprintf("up to date at %03d", s.migrator.LatestVersion()), nil
+}
+
+// checkNotifier reports whether the Slack webhook host answers. The result is cached for
+// notifierCheckInterval so frequent probes from every replica do not hit Slack.
+func (s *HealthService) checkNotifier(ctx context.Context) models.HealthComponent {
+	if s.slackWebhookURL == "" {
+		return models.HealthComponent{Status: "skipped"}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if time.Now().Before(s.notifierExpiry) {
+		return s.notifier
+	}
+
+	s.notifier = s.runCheck(ctx, healthCheck{name: "notifier", run: func(ctx context.Context) (string, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodHead, s.slackWebhookURL, nil)
+		if err != nil {
+			return "", err
+		}
+		resp, err := s.httpClient.Do(req)
+		if err != nil {
+			return "", err
+		}
+		resp.Body.Close()
+		// Any HTTP answer means the webhook host is reachable; it rejects HEAD requests
+		return fmt.Sprintf("slack answered %d", resp.StatusCode), nil
+	}})
+	s.notifierExpiry = time.Now().Add(notifierCheckInterval)
+	return s.notifier
+}
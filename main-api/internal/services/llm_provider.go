@@ -0,0 +1,260 @@
+package services
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"math"
+	"net/http"
+	"strings"
+	"time"
+	"unicode"
+
+	"bizgenie-api/internal/tracing"
+)
+
+// EmbeddingDimensions matches the vector(1536) columns in the database
+const EmbeddingDimensions = 1536
+
+// ChatMessage is a single message of a chat completion request
+type ChatMessage struct {
+	Role    string `json:"role"` // 'system', 'user' or 'assistant'
+	Content string `json:"content"`
+}
+
+// ChatProvider generates chat completions, streaming the answer through onDelta
+type ChatProvider interface {
+	Name() string
+	Model() string
+	StreamChat(ctx context.Context, messages []ChatMessage, onDelta func(string) error) (string, error)
+}
+
+// EmbeddingProvider turns text into a vector of EmbeddingDimensions floats
+type EmbeddingProvider interface {
+	Embed(ctx context.Context, text string) ([]float32, error)
+}
+
+// NewLLMProviders returns the chat and embedding providers for the given provider name.
+// "openai" talks to any OpenAI-compatible HTTP API, "fake" runs locally without network access.
+func NewLLMProviders(provider, baseURL, apiKey, chatModel, embeddingModel string) (ChatProvider, EmbeddingProvider, error) {
+	switch provider {
+	case "openai":
+		client := &OpenAIClient{
+			baseURL:        strings.TrimRight(baseURL, "/"),
+			apiKey:         apiKey,
+			chatModel:      chatModel,
+			embeddingModel: embeddingModel,
+			httpClient:     &http.Client{Timeout: 60 * time.Second, Transport: tracing.Transport("llm", nil)},
+		}
+		return client, client, nil
+	case "fake":
+		fake := &FakeLLM{}
+		return fake, fake, nil
+	default:
+		return nil, nil, fmt.Errorf("unknown assistant provider: %s", provider)
+	}
+}
+
+// OpenAIClient implements ChatProvider and EmbeddingProvider against an OpenAI-compatible API
+type OpenAIClient struct {
+	baseURL        string
+	apiKey         string
+	chatModel      string
+	embeddingModel string
+	httpClient     *http.Client
+}
+
+func (c *OpenAIClient) Name() string  { return "openai" }
+func (c *OpenAIClient) Model() string { return c.chatModel }
+
+func (c *OpenAIClient) post(ctx context.Context, path string, payload interface{}) (*http.Response, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+path, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		errBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("%s returned status %d: %s", path, resp.StatusCode, string(errBody))
+	}
+
+	return resp, nil
+}
+
+func (c *OpenAIClient) Embed(ctx context.Context, text string) ([]float32, error) {
+	resp, err := c.post(ctx, "/embeddings", map[string]interface{}{
+		"model": c.embeddingModel,
+		"input": text,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		Data []struct {
+			Embedding []float32 `json:"embedding"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, err
+	}
+
+	if len(result.Data) == 0 {
+		return nil, fmt.Errorf("embedding response contained no data")
+	}
+	if len(result.Data[0].Embedding) != EmbeddingDimensions {
+		return nil, fmt.Errorf("embedding has %d dimensions, expected %d", len(result.Data[0].Embedding), EmbeddingDimensions)
+	}
+
+	return result.Data[0].Embedding, nil
+}
+
+func (c *OpenAIClient) StreamChat(ctx context.Context, messages []ChatMessage, onDelta func(string) error) (string, error) {
+	resp, err := c.post(ctx, "/chat/completions", map[string]interface{}{
+		"model":    c.chatModel,
+		"messages": messages,
+		"stream":   true,
+	})
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	var answer strings.Builder
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, "data:") {
+			continue
+		}
+
+		data := strings.TrimSpace(strings.TrimPrefix(line, "data:"))
+		if data == "[DONE]" {
+			break
+		}
+
+		var chunk struct {
+			Choices []struct {
+				Delta struct {
+					Content string `json:"content"`
+				} `json:"delta"`
+			} `json:"choices"`
+		}
+		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+			return answer.String(), fmt.Errorf("invalid stream chunk: %w", err)
+		}
+
+		for _, choice := range chunk.Choices {
+			if choice.Delta.Content == "" {
+				continue
+			}
+			answer.WriteString(choice.Delta.Content)
+			if err := onDelta(choice.Delta.Content); err != nil {
+				return answer.String(), err
+			}
+		}
+	}
+
+	return answer.String(), scanner.Err()
+}
+
+// FakeLLM is a deterministic, offline provider for development and tests.
+// Embeddings hash words into buckets so texts sharing words end up close together;
+// answers quote the first sentence of the provided sources.
+type FakeLLM struct{}
+
+func (f *FakeLLM) Name() string  { return "fake" }
+func (f *FakeLLM) Model() string { return "fake" }
+
+func (f *FakeLLM) Embed(ctx context.Context, text string) ([]float32, error) {
+	vector := make([]float32, EmbeddingDimensions)
+
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+	for _, word := range words {
+		h := fnv.New32a()
+		h.Write([]byte(word))
+		vector[h.Sum32()%EmbeddingDimensions]++
+	}
+
+	var norm float64
+	for _, v := range vector {
+		norm += float64(v * v)
+	}
+	if norm == 0 {
+		// Cosine distance is undefined for the zero vector
+		vector[0] = 1
+		return vector, nil
+	}
+
+	norm = math.Sqrt(norm)
+	for i := range vector {
+		vector[i] = float32(float64(vector[i]) / norm)
+	}
+
+	return vector, nil
+}
+
+func (f *FakeLLM) StreamChat(ctx context.Context, messages []ChatMessage, onDelta func(string) error) (string, error) {
+	// The sources are passed in the last user message as "[n] title\ncontent" blocks
+	var prompt string
+	if len(messages) > 0 {
+		prompt = messages[len(messages)-1].Content
+	}
+
+	var parts []string
+	for _, block := range strings.Split(prompt, "\n\n[")[1:] {
+		lines := strings.SplitN(block, "\n", 2)
+		end := strings.Index(lines[0], "]")
+		if len(lines) < 2 || end < 0 {
+			continue
+		}
+		sentence := strings.TrimSpace(lines[1])
+		if i := strings.IndexAny(sentence, ".!?\n"); i > 0 {
+			sentence = sentence[:i+1]
+		}
+		parts = append(parts, fmt.Sprintf("%s [%s", sentence, lines[0][:end+1]))
+		if len(parts) == 2 {
+			break
+		}
+	}
+
+	answer := "Xin lỗi, tôi chưa tìm thấy thông tin phù hợp trong nội dung của BizGenie."
+	if len(parts) > 0 {
+		answer = strings.Join(parts, " ")
+	}
+
+	for _, word := range strings.SplitAfter(answer, " ") {
+		if err := ctx.Err(); err != nil {
+			return "", err
+		}
+		if err := onDelta(word); err != nil {
+			return "", err
+		}
+	}
+
+	return answer, nil
+}
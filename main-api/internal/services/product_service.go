@@ -1,11 +1,20 @@
 package services
 
 import (
+	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 
 	"bizgenie-api/internal/logger"
+	"bizgenie-api/internal/metrics"
 	"bizgenie-api/internal/models"
+
+	"github.com/pgvector/pgvector-go"
 )
 
 type ProductService struct {
@@ -16,7 +25,8 @@ func NewProductService(db *sql.DB) *ProductService {
 	return &ProductService{db: db}
 }
 
-func (s *ProductService) GetProducts(status string, categoryID *int, limit, offset int) ([]models.Product, error) {
+func (s *ProductService) GetProducts(ctx context.Context, filter models.ProductFilter, limit, offset int) ([]models.Product, error) {
+	defer metrics.ObserveQuery("products.list", time.Now())
 	query := `
 		SELECT p.id, p.name, p.slug, p.short_description, p.description, 
 		       p.category_id, p.image_urls, p.features, p.specifications, 
@@ -26,27 +36,16 @@ func (s *ProductService) GetProducts(status string, categoryID *int, limit, offs
 		LEFT JOIN categories c ON p.category_id = c.id
 		WHERE 1=1
 	`
-	args := []interface{}{}
-	argPos := 1
-
-	if status != "" {
-		query += fmt.Sprintf(" AND p.status = $%d", argPos)
-		args = append(args, status)
-		argPos++
-	}
-
-	if categoryID != nil {
-		query += fmt.Sprintf(" AND p.category_id = $%d", argPos)
-		args = append(args, *categoryID)
-		argPos++
-	}
+	where, args := productFilterClause(filter, "")
+	query += where
+	argPos := len(args) + 1
 
 	query += fmt.Sprintf(" ORDER BY p.created_at DESC LIMIT $%d OFFSET $%d", argPos, argPos+1)
 	args = append(args, limit, offset)
 
-	rows, err := s.db.Query(query, args...)
+	rows, err := s.db.QueryContext(ctx, query, args...)
 	if err != nil {
-		logger.ErrorWithErr("Failed to query products", err)
+		logger.ErrorWithErrContext(ctx, "Failed to query products", err)
 		return nil, err
 	}
 	defer rows.Close()
@@ -77,7 +76,8 @@ func (s *ProductService) GetProducts(status string, categoryID *int, limit, offs
 	return products, nil
 }
 
-func (s *ProductService) GetProductByID(id int) (*models.Product, error) {
+func (s *ProductService) GetProductByID(ctx context.Context, id int) (*models.Product, error) {
+	defer metrics.ObserveQuery("products.get_by_id", time.Now())
 	query := `
 		SELECT p.id, p.name, p.slug, p.short_description, p.description, 
 		       p.category_id, p.image_urls, p.features, p.specifications, 
@@ -92,7 +92,7 @@ func (s *ProductService) GetProductByID(id int) (*models.Product, error) {
 	var category models.Category
 	var categoryID sql.NullInt64
 
-	err := s.db.QueryRow(query, id).Scan(
+	err := s.db.QueryRowContext(ctx, query, id).Scan(
 		&p.ID, &p.Name, &p.Slug, &p.ShortDescription, &p.Description,
 		&p.CategoryID, &p.ImageURLs, &p.Features, &p.Specifications,
 		&p.Status, &p.CreatedAt, &p.UpdatedAt,
@@ -100,7 +100,7 @@ func (s *ProductService) GetProductByID(id int) (*models.Product, error) {
 		&category.ParentID, &category.Order, &category.CreatedAt,
 	)
 	if err != nil {
-		return nil, err
+		return nil, notFound(err, "product_not_found")
 	}
 
 	if categoryID.Valid {
@@ -110,7 +110,8 @@ func (s *ProductService) GetProductByID(id int) (*models.Product, error) {
 	return &p, nil
 }
 
-func (s *ProductService) GetProductBySlug(slug string) (*models.Product, error) {
+func (s *ProductService) GetProductBySlug(ctx context.Context, slug string) (*models.Product, error) {
+	defer metrics.ObserveQuery("products.get_by_slug", time.Now())
 	query := `
 		SELECT p.id, p.name, p.slug, p.short_description, p.description, 
 		       p.category_id, p.image_urls, p.features, p.specifications, 
@@ -125,7 +126,7 @@ func (s *ProductService) GetProductBySlug(slug string) (*models.Product, error)
 	var category models.Category
 	var categoryID sql.NullInt64
 
-	err := s.db.QueryRow(query, slug).Scan(
+	err := s.db.QueryRowContext(ctx, query, slug).Scan(
 		&p.ID, &p.Name, &p.Slug, &p.ShortDescription, &p.Description,
 		&p.CategoryID, &p.ImageURLs, &p.Features, &p.Specifications,
 		&p.Status, &p.CreatedAt, &p.UpdatedAt,
@@ -133,7 +134,7 @@ func (s *ProductService) GetProductBySlug(slug string) (*models.Product, error)
 		&category.ParentID, &category.Order, &category.CreatedAt,
 	)
 	if err != nil {
-		return nil, err
+		return nil, notFound(err, "product_not_found")
 	}
 
 	if categoryID.Valid {
@@ -143,7 +144,10 @@ func (s *ProductService) GetProductBySlug(slug string) (*models.Product, error)
 	return &p, nil
 }
 
-func (s *ProductService) CreateProduct(p *models.Product) error {
+func (s *ProductService) CreateProduct(ctx context.Context, p *models.Product) error {
+	if p.Slug == "" {
+		p.Slug = Slugify(p.Name)
+	}
 	query := `
 		INSERT INTO products (name, slug, short_description, description, category_id, 
 		                     image_urls, features, specifications, status)
@@ -151,15 +155,15 @@ func (s *ProductService) CreateProduct(p *models.Product) error {
 		RETURNING id, created_at, updated_at
 	`
 
-	err := s.db.QueryRow(
+	err := s.db.QueryRowContext(ctx,
 		query, p.Name, p.Slug, p.ShortDescription, p.Description, p.CategoryID,
 		p.ImageURLs, p.Features, p.Specifications, p.Status,
 	).Scan(&p.ID, &p.CreatedAt, &p.UpdatedAt)
 
-	return err
+	return dbError(err)
 }
 
-func (s *ProductService) UpdateProduct(id int, p *models.Product) error {
+func (s *ProductService) UpdateProduct(ctx context.Context, id int, p *models.Product) error {
 	query := `
 		UPDATE products 
 		SET name = $2, slug = $3, short_description = $4, description = $5,
@@ -169,22 +173,219 @@ func (s *ProductService) UpdateProduct(id int, p *models.Product) error {
 		RETURNING updated_at
 	`
 
-	err := s.db.QueryRow(
+	err := s.db.QueryRowContext(ctx,
 		query, id, p.Name, p.Slug, p.ShortDescription, p.Description,
 		p.CategoryID, p.ImageURLs, p.Features, p.Specifications, p.Status,
 	).Scan(&p.UpdatedAt)
 
-	return err
+	return notFound(err, "product_not_found")
 }
 
-func (s *ProductService) DeleteProduct(id int) error {
+func (s *ProductService) DeleteProduct(ctx context.Context, id int) error {
 	query := `DELETE FROM products WHERE id = $1`
-	_, err := s.db.Exec(query, id)
-	return err
+	result, err := s.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return dbError(err)
+	}
+	return affectedOne(result, "product_not_found")
+}
+
+// UpdateProductEmbedding stores an embedding supplied by an admin, which the assistant
+// then never recomputes. An empty embedding clears it and hands the product back to the
+// assistant.
+func (s *ProductService) UpdateProductEmbedding(ctx context.Context, id int, embedding []float32) error {
+	var vector interface{}
+	if len(embedding) > 0 {
+		vector = pgvector.NewVector(embedding)
+	}
+	query := `UPDATE products SET embedding = $2, embedding_manual = $2 IS NOT NULL WHERE id = $1`
+	result, err := s.db.ExecContext(ctx, query, id, vector)
+	if err != nil {
+		return dbError(err)
+	}
+	return affectedOne(result, "product_not_found")
+}
+
+// productFilterClause returns " AND ..." conditions on products p for filter, with
+// placeholders numbered from $1. The spec key skipSpec is left out so its facet
+// can still offer the alternatives to the selected values.
+func productFilterClause(filter models.ProductFilter, skipSpec string) (string, []interface{}) {
+	where := ""
+	args := []interface{}{}
+
+	if filter.Status != "" {
+		args = append(args, filter.Status)
+		where += fmt.Sprintf(" AND p.status = $%d", len(args))
+	}
+
+	if filter.CategoryID != nil {
+		args = append(args, *filter.CategoryID)
+		where += fmt.Sprintf(" AND p.category_id = $%d", len(args))
+	}
+
+	// Sorted so the same filter always produces the same SQL
+	keys := make([]string, 0, len(filter.Specs))
+	for key := range filter.Specs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if key == skipSpec || len(filter.Specs[key]) == 0 {
+			continue
+		}
+
+		var alternatives []string
+		for _, value := range filter.Specs[key] {
+			for _, doc := range specContainmentDocs(key, value) {
+				args = append(args, doc)
+				alternatives = append(alternatives, fmt.Sprintf("p.specifications @> $%d::jsonb", len(args)))
+			}
+		}
+		where += " AND (" + strings.Join(alternatives, " OR ") + ")"
+	}
+
+	if len(filter.Features) > 0 {
+		doc, _ := json.Marshal(filter.Features)
+		args = append(args, string(doc))
+		where += fmt.Sprintf(" AND p.features @> $%d::jsonb", len(args))
+	}
+
+	return where, args
+}
+
+// specContainmentDocs returns the JSON documents a specification matching key=value
+// contains: the value as a string or, if it parses as one, a number or boolean,
+// either directly or as an element of an array
+func specContainmentDocs(key, value string) []string {
+	candidates := []interface{}{value}
+	if _, err := strconv.ParseFloat(value, 64); (err == nil && json.Valid([]byte(value))) || value == "true" || value == "false" {
+		candidates = append(candidates, json.RawMessage(value))
+	}
+
+	var docs []string
+	for _, candidate := range candidates {
+		for _, v := range []interface{}{candidate, []interface{}{candidate}} {
+			doc, _ := json.Marshal(map[string]interface{}{key: v})
+			docs = append(docs, string(doc))
+		}
+	}
+	return docs
+}
+
+// GetProductFacets returns the specification keys and values and the features of the
+// products matching filter, with product counts. Counts of a filtered specification
+// key ignore that key's own selection so its other values stay selectable.
+func (s *ProductService) GetProductFacets(ctx context.Context, filter models.ProductFilter) (*models.ProductFacets, error) {
+	defer metrics.ObserveQuery("products.facets", time.Now())
+	facets := &models.ProductFacets{
+		Specifications: []models.ProductSpecFacet{},
+		Features:       []models.ProductFacetValue{},
+	}
+
+	where, args := productFilterClause(filter, "")
+	if err := s.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM products p WHERE 1=1`+where, args...).Scan(&facets.Total); err != nil {
+		return nil, err
+	}
+
+	specs, err := s.specFacets(ctx, filter, "")
+	if err != nil {
+		return nil, err
+	}
+
+	for key, values := range filter.Specs {
+		if len(values) == 0 {
+			continue
+		}
+		keyed, err := s.specFacets(ctx, filter, key)
+		if err != nil {
+			return nil, err
+		}
+		specs[key] = keyed[key]
+		if len(specs[key]) == 0 {
+			delete(specs, key)
+		}
+	}
+
+	keys := make([]string, 0, len(specs))
+	for key := range specs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		facets.Specifications = append(facets.Specifications, models.ProductSpecFacet{Key: key, Values: specs[key]})
+	}
+
+	featureQuery := `
+		SELECT f.value, COUNT(DISTINCT p.id)
+		FROM products p
+		CROSS JOIN LATERAL jsonb_array_elements_text(
+			CASE WHEN jsonb_typeof(p.features) = 'array' THEN p.features ELSE '[]'::jsonb END
+		) AS f(value)
+		WHERE 1=1` + where + `
+		GROUP BY f.value
+		ORDER BY 2 DESC, f.value
+	`
+	rows, err := s.db.QueryContext(ctx, featureQuery, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var fv models.ProductFacetValue
+		if err := rows.Scan(&fv.Value, &fv.Count); err != nil {
+			return nil, err
+		}
+		facets.Features = append(facets.Features, fv)
+	}
+
+	return facets, rows.Err()
 }
 
-func (s *ProductService) UpdateProductEmbedding(id int, embedding []float32) error {
-	query := `UPDATE products SET embedding = $2 WHERE id = $1`
-	_, err := s.db.Exec(query, id, embedding)
-	return err
+// specFacets counts specification values of the products matching filter. When
+// onlyKey is set, only that key is counted and its own selection is ignored.
+func (s *ProductService) specFacets(ctx context.Context, filter models.ProductFilter, onlyKey string) (map[string][]models.ProductFacetValue, error) {
+	where, args := productFilterClause(filter, onlyKey)
+	if onlyKey != "" {
+		args = append(args, onlyKey)
+		where += fmt.Sprintf(" AND kv.key = $%d", len(args))
+	}
+
+	// Array values count once per element; nested objects are not offered as filters
+	query := `
+		SELECT kv.key, v.value, COUNT(DISTINCT p.id)
+		FROM products p
+		CROSS JOIN LATERAL jsonb_each(
+			CASE WHEN jsonb_typeof(p.specifications) = 'object' THEN p.specifications ELSE '{}'::jsonb END
+		) AS kv
+		CROSS JOIN LATERAL (
+			SELECT e #>> '{}' AS value
+			FROM jsonb_array_elements(
+				CASE WHEN jsonb_typeof(kv.value) = 'array' THEN kv.value ELSE jsonb_build_array(kv.value) END
+			) AS e
+			WHERE jsonb_typeof(e) IN ('string', 'number', 'boolean')
+		) AS v
+		WHERE 1=1` + where + `
+		GROUP BY kv.key, v.value
+		ORDER BY kv.key, 3 DESC, v.value
+	`
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	specs := map[string][]models.ProductFacetValue{}
+	for rows.Next() {
+		var key string
+		var fv models.ProductFacetValue
+		if err := rows.Scan(&key, &fv.Value, &fv.Count); err != nil {
+			return nil, err
+		}
+		specs[key] = append(specs[key], fv)
+	}
+
+	return specs, rows.Err()
 }
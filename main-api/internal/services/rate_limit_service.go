@@ -0,0 +1,149 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"math"
+	"sync"
+	"time"
+
+	"bizgenie-api/internal/logger"
+)
+
+// RateLimitStore keeps the token buckets of the rate limiter
+type RateLimitStore interface {
+	Take(ctx context.Context, key string, capacity int, period time.Duration) (bool, float64, error)
+	DeleteIdleBuckets(ctx context.Context, idle time.Duration) (int64, error)
+}
+
+// RunRateLimitCleanup deletes the idle buckets of store every interval until ctx is done.
+// Every replica runs it; the deletes are idempotent.
+func RunRateLimitCleanup(ctx context.Context, store RateLimitStore, interval, idle time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		deleted, err := store.DeleteIdleBuckets(ctx, idle)
+		if err != nil {
+			logger.ErrorWithErrContext(ctx, "Failed to delete idle rate limit buckets", err)
+			continue
+		}
+		logger.DebugContext(ctx, "Deleted %d idle rate limit buckets", deleted)
+	}
+}
+
+// RateLimitService keeps the token buckets of the rate limiter in Postgres so every replica
+// draws from the same buckets. Buckets refill continuously: a bucket of capacity 5 per
+// 10 minutes gains a token every 2 minutes, up to 5.
+type RateLimitService struct {
+	db *sql.DB
+}
+
+func NewRateLimitService(db *sql.DB) *RateLimitService {
+	return &RateLimitService{db: db}
+}
+
+// Take removes a token from the bucket named key, creating it full if needed, and returns
+// whether a token was available and how many tokens are left. Refill and removal happen in
+// one statement on the database clock, so concurrent requests on different replicas cannot
+// both take the last token.
+func (s *RateLimitService) Take(ctx context.Context, key string, capacity int, period time.Duration) (bool, float64, error) {
+	rate := float64(capacity) / period.Seconds()
+	bucket := HashClient(key)
+
+	var tokens float64
+	err := s.db.QueryRowContext(ctx, `
+		INSERT INTO rate_limit_buckets (key, tokens, updated_at)
+		VALUES ($1, $2 - 1, NOW())
+		ON CONFLICT (key) DO UPDATE SET
+			tokens = LEAST($2, rate_limit_buckets.tokens + EXTRACT(EPOCH FROM NOW() - rate_limit_buckets.updated_at) * $3) - 1,
+			updated_at = NOW()
+		WHERE LEAST($2, rate_limit_buckets.tokens + EXTRACT(EPOCH FROM NOW() - rate_limit_buckets.updated_at) * $3) >= 1
+		RETURNING tokens`, bucket, capacity, rate).Scan(&tokens)
+	if err == nil {
+		return true, tokens, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return false, 0, err
+	}
+
+	// The bucket is empty and was left untouched; report how far it has refilled
+	err = s.db.QueryRowContext(ctx, `
+		SELECT LEAST($2, tokens + EXTRACT(EPOCH FROM NOW() - updated_at) * $3)
+		FROM rate_limit_buckets WHERE key = $1`, bucket, capacity, rate).Scan(&tokens)
+	if err != nil {
+		return false, 0, err
+	}
+	return false, tokens, nil
+}
+
+// DeleteIdleBuckets removes buckets unused for longer than idle. A bucket idle for longer
+// than its period is full, which is the same as having no bucket.
+func (s *RateLimitService) DeleteIdleBuckets(ctx context.Context, idle time.Duration) (int64, error) {
+	result, err := s.db.ExecContext(ctx,
+		`DELETE FROM rate_limit_buckets WHERE updated_at < NOW() - $1 * INTERVAL '1 second'`, idle.Seconds())
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// MemoryRateLimitStore keeps token buckets in process memory. Each replica then limits on
+// its own, so it suits local development and single-instance deployments.
+type MemoryRateLimitStore struct {
+	mu      sync.Mutex
+	buckets map[string]*memoryBucket
+}
+
+type memoryBucket struct {
+	tokens    float64
+	updatedAt time.Time
+}
+
+func NewMemoryRateLimitStore() *MemoryRateLimitStore {
+	return &MemoryRateLimitStore{buckets: map[string]*memoryBucket{}}
+}
+
+// Take removes a token from the bucket named key, like RateLimitService.Take
+func (s *MemoryRateLimitStore) Take(ctx context.Context, key string, capacity int, period time.Duration) (bool, float64, error) {
+	rate := float64(capacity) / period.Seconds()
+	now := time.Now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	bucket, ok := s.buckets[key]
+	if !ok {
+		bucket = &memoryBucket{tokens: float64(capacity), updatedAt: now}
+		s.buckets[key] = bucket
+	}
+	bucket.tokens = math.Min(float64(capacity), bucket.tokens+now.Sub(bucket.updatedAt).Seconds()*rate)
+	bucket.updatedAt = now
+	if bucket.tokens < 1 {
+		return false, bucket.tokens, nil
+	}
+	bucket.tokens--
+	return true, bucket.tokens, nil
+}
+
+// DeleteIdleBuckets removes buckets unused for longer than idle
+func (s *MemoryRateLimitStore) DeleteIdleBuckets(ctx context.Context, idle time.Duration) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var deleted int64
+	for key, bucket := range s.buckets {
+		if time.Since(bucket.updatedAt) > idle {
+			delete(s.buckets, key)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
@@ -0,0 +1,357 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+	"time"
+
+	"bizgenie-api/internal/logger"
+	"bizgenie-api/internal/metrics"
+	"bizgenie-api/internal/models"
+)
+
+// recommendationCacheTTL bounds how long a replica serves a list without seeing the
+// latest cache version, e.g. while the database is unreachable
+const recommendationCacheTTL = 10 * time.Minute
+
+// recommendationCacheName is the row of cache_versions shared by all replicas
+const recommendationCacheName = "recommendations"
+
+type recommendationCacheEntry struct {
+	related   *models.RelatedContent
+	expiresAt time.Time
+}
+
+// RecommendationService builds "you may also like" lists for products and blog posts.
+// Results are cached in memory; call Invalidate after any product or blog write. The
+// cache is versioned in Postgres so a write on one replica invalidates all of them.
+type RecommendationService struct {
+	db      *sql.DB
+	mu      sync.RWMutex
+	cache   map[string]recommendationCacheEntry
+	version int64 // Version of cache_versions the entries were computed under
+}
+
+func NewRecommendationService(db *sql.DB) *RecommendationService {
+	return &RecommendationService{
+		db:    db,
+		cache: make(map[string]recommendationCacheEntry),
+	}
+}
+
+// Invalidate drops all cached recommendations on every replica. Any write can change
+// every list, so the whole cache is cleared rather than individual entries. Failing to
+// bump the shared version only leaves the other replicas stale until the TTL, so it is
+// logged rather than failing the write.
+func (s *RecommendationService) Invalidate(ctx context.Context) {
+	s.mu.Lock()
+	s.cache = make(map[string]recommendationCacheEntry)
+	s.mu.Unlock()
+
+	query := `UPDATE cache_versions SET version = version + 1, updated_at = NOW() WHERE name = $1`
+	if _, err := s.db.ExecContext(ctx, query, recommendationCacheName); err != nil {
+		logger.WarnContext(ctx, "Failed to invalidate recommendations on other replicas: %v", err)
+	}
+}
+
+// currentVersion returns the shared cache version, dropping the local entries when another
+// replica has bumped it
+func (s *RecommendationService) currentVersion(ctx context.Context) (int64, error) {
+	var version int64
+	query := `SELECT version FROM cache_versions WHERE name = $1`
+	if err := s.db.QueryRowContext(ctx, query, recommendationCacheName).Scan(&version); err != nil {
+		return 0, err
+	}
+
+	s.mu.Lock()
+	if version != s.version {
+		s.cache = make(map[string]recommendationCacheEntry)
+		s.version = version
+	}
+	s.mu.Unlock()
+	return version, nil
+}
+
+// cached returns the entry for key if it is current. It also returns the version the
+// caller should store a freshly computed entry under; ok is false for the version when
+// it could not be read, and the entry must then not be cached.
+func (s *RecommendationService) cached(ctx context.Context, key string) (related *models.RelatedContent, version int64, versionOK bool) {
+	version, err := s.currentVersion(ctx)
+	if err != nil {
+		logger.WarnContext(ctx, "Failed to read the recommendation cache version: %v", err)
+		return nil, 0, false
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	entry, ok := s.cache[key]
+	if !ok || time.Now().After(entry.expiresAt) {
+		return nil, version, true
+	}
+	return entry.related, version, true
+}
+
+// store caches related under key unless the cache moved past version while it was computed
+func (s *RecommendationService) store(key string, version int64, related *models.RelatedContent) {
+	s.mu.Lock()
+	if version == s.version {
+		s.cache[key] = recommendationCacheEntry{related: related, expiresAt: time.Now().Add(recommendationCacheTTL)}
+	}
+	s.mu.Unlock()
+}
+
+// GetRelatedForProduct returns similar products and relevant blog posts for a product.
+// Returns a not found error if the product does not exist.
+func (s *RecommendationService) GetRelatedForProduct(ctx context.Context, id, limit int) (*models.RelatedContent, error) {
+	defer metrics.ObserveQuery("recommendations.product", time.Now())
+	key := fmt.Sprintf("product:%d:%d", id, limit)
+	related, version, versionOK := s.cached(ctx, key)
+	if related != nil {
+		return related, nil
+	}
+
+	var hasEmbedding bool
+	err := s.db.QueryRowContext(ctx, `SELECT embedding IS NOT NULL FROM products WHERE id = $1`, id).Scan(&hasEmbedding)
+	if err != nil {
+		return nil, notFound(err, "product_not_found")
+	}
+
+	related = &models.RelatedContent{}
+
+	if hasEmbedding {
+		related.Products, err = s.similarProductsByEmbedding(ctx, id, limit)
+		if err != nil {
+			return nil, err
+		}
+	}
+	// Fall back to category and feature overlap when embeddings are missing
+	if len(related.Products) == 0 {
+		related.Products, err = s.similarProductsByAttributes(ctx, id, limit)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	related.BlogPosts, err = s.blogPostsForProduct(ctx, id, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	if versionOK {
+		s.store(key, version, related)
+	}
+	return related, nil
+}
+
+// GetRelatedForBlogPost returns similar blog posts and relevant products for a blog post.
+// Returns a not found error if the blog post does not exist.
+func (s *RecommendationService) GetRelatedForBlogPost(ctx context.Context, id, limit int) (*models.RelatedContent, error) {
+	defer metrics.ObserveQuery("recommendations.blog_post", time.Now())
+	key := fmt.Sprintf("blog:%d:%d", id, limit)
+	related, version, versionOK := s.cached(ctx, key)
+	if related != nil {
+		return related, nil
+	}
+
+	var exists bool
+	if err := s.db.QueryRowContext(ctx, `SELECT EXISTS(SELECT 1 FROM blog_posts WHERE id = $1)`, id).Scan(&exists); err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, notFound(sql.ErrNoRows, "blog_post_not_found")
+	}
+
+	related = &models.RelatedContent{}
+	var err error
+
+	related.BlogPosts, err = s.similarBlogPosts(ctx, id, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	related.Products, err = s.productsForBlogPost(ctx, id, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	if versionOK {
+		s.store(key, version, related)
+	}
+	return related, nil
+}
+
+func (s *RecommendationService) similarProductsByEmbedding(ctx context.Context, id, limit int) ([]models.Product, error) {
+	query := `
+		SELECT p.id, p.name, p.slug, p.short_description, p.description,
+		       p.category_id, p.image_urls, p.features, p.specifications,
+		       p.status, p.created_at, p.updated_at
+		FROM products p, (SELECT embedding FROM products WHERE id = $1) src
+		WHERE p.id <> $1
+		  AND p.status = 'published'
+		  AND p.embedding IS NOT NULL
+		ORDER BY p.embedding <=> src.embedding
+		LIMIT $2
+	`
+	return s.queryProducts(ctx, query, id, limit)
+}
+
+func (s *RecommendationService) similarProductsByAttributes(ctx context.Context, id, limit int) ([]models.Product, error) {
+	// Score: same category counts 2, each shared feature string counts 1
+	query := `
+		WITH src AS (
+			SELECT category_id,
+			       CASE WHEN jsonb_typeof(features) = 'array' THEN features ELSE '[]'::jsonb END AS features
+			FROM products WHERE id = $1
+		)
+		SELECT p.id, p.name, p.slug, p.short_description, p.description,
+		       p.category_id, p.image_urls, p.features, p.specifications,
+		       p.status, p.created_at, p.updated_at
+		FROM products p, src
+		WHERE p.id <> $1
+		  AND p.status = 'published'
+		ORDER BY (CASE WHEN p.category_id = src.category_id THEN 2 ELSE 0 END) + (
+		           SELECT COUNT(*)
+		           FROM jsonb_array_elements_text(CASE WHEN jsonb_typeof(p.features) = 'array' THEN p.features ELSE '[]'::jsonb END) f
+		           WHERE f IN (SELECT jsonb_array_elements_text(src.features))
+		         ) DESC,
+		         p.created_at DESC
+		LIMIT $2
+	`
+	return s.queryProducts(ctx, query, id, limit)
+}
+
+// blogPostsForProduct matches blog posts against any word of the product name and features
+func (s *RecommendationService) blogPostsForProduct(ctx context.Context, id, limit int) ([]models.BlogPost, error) {
+	query := `
+		WITH src AS (
+			SELECT replace(plainto_tsquery('simple',
+			           p.name || ' ' || COALESCE((SELECT string_agg(f, ' ') FROM jsonb_array_elements_text(
+			               CASE WHEN jsonb_typeof(p.features) = 'array' THEN p.features ELSE '[]'::jsonb END) f), '')
+			       )::text, '&', '|')::tsquery AS tsq
+			FROM products p WHERE p.id = $1
+		),
+		ranked AS (
+			SELECT b.*,
+			       setweight(to_tsvector('simple', b.title), 'A') ||
+			       setweight(to_tsvector('simple', COALESCE(b.excerpt, '')), 'B') ||
+			       setweight(to_tsvector('simple', regexp_replace(b.content, '<[^>]+>', ' ', 'g')), 'C') AS doc
+			FROM blog_posts b
+			WHERE b.status = 'published'
+		)
+		SELECT b.id, b.title, b.slug, b.excerpt, b.featured_image, b.author_id, b.category_id,
+		       b.status, b.published_at, b.created_at, b.updated_at
+		FROM ranked b, src
+		WHERE b.doc @@ src.tsq
+		ORDER BY ts_rank(b.doc, src.tsq) DESC, b.published_at DESC
+		LIMIT $2
+	`
+	return s.queryBlogPosts(ctx, query, id, limit)
+}
+
+// similarBlogPosts ranks posts in the same category first, then by shared title words
+func (s *RecommendationService) similarBlogPosts(ctx context.Context, id, limit int) ([]models.BlogPost, error) {
+	query := `
+		WITH src AS (
+			SELECT category_id,
+			       replace(plainto_tsquery('simple', title || ' ' || COALESCE(excerpt, ''))::text, '&', '|')::tsquery AS tsq
+			FROM blog_posts WHERE id = $1
+		)
+		SELECT b.id, b.title, b.slug, b.excerpt, b.featured_image, b.author_id, b.category_id,
+		       b.status, b.published_at, b.created_at, b.updated_at
+		FROM blog_posts b, src
+		WHERE b.id <> $1
+		  AND b.status = 'published'
+		ORDER BY (CASE WHEN b.category_id = src.category_id THEN 1 ELSE 0 END) +
+		         ts_rank(
+		             setweight(to_tsvector('simple', b.title), 'A') ||
+		             setweight(to_tsvector('simple', COALESCE(b.excerpt, '')), 'B'),
+		             src.tsq
+		         ) DESC,
+		         b.published_at DESC
+		LIMIT $2
+	`
+	return s.queryBlogPosts(ctx, query, id, limit)
+}
+
+// productsForBlogPost matches products against any word of the post title and excerpt
+func (s *RecommendationService) productsForBlogPost(ctx context.Context, id, limit int) ([]models.Product, error) {
+	query := `
+		WITH src AS (
+			SELECT replace(plainto_tsquery('simple', title || ' ' || COALESCE(excerpt, ''))::text, '&', '|')::tsquery AS tsq
+			FROM blog_posts WHERE id = $1
+		),
+		ranked AS (
+			SELECT p.*,
+			       setweight(to_tsvector('simple', p.name), 'A') ||
+			       setweight(to_tsvector('simple', COALESCE(p.short_description, '')), 'B') ||
+			       setweight(to_tsvector('simple', COALESCE(p.features::text, '')), 'B') ||
+			       setweight(to_tsvector('simple', COALESCE(p.description, '')), 'C') AS doc
+			FROM products p
+			WHERE p.status = 'published'
+		)
+		SELECT p.id, p.name, p.slug, p.short_description, p.description,
+		       p.category_id, p.image_urls, p.features, p.specifications,
+		       p.status, p.created_at, p.updated_at
+		FROM ranked p, src
+		WHERE p.doc @@ src.tsq
+		ORDER BY ts_rank(p.doc, src.tsq) DESC, p.created_at DESC
+		LIMIT $2
+	`
+	return s.queryProducts(ctx, query, id, limit)
+}
+
+func (s *RecommendationService) queryProducts(ctx context.Context, query string, args ...interface{}) ([]models.Product, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	products := []models.Product{}
+	for rows.Next() {
+		var p models.Product
+		err := rows.Scan(
+			&p.ID, &p.Name, &p.Slug, &p.ShortDescription, &p.Description,
+			&p.CategoryID, &p.ImageURLs, &p.Features, &p.Specifications,
+			&p.Status, &p.CreatedAt, &p.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		products = append(products, p)
+	}
+
+	return products, rows.Err()
+}
+
+func (s *RecommendationService) queryBlogPosts(ctx context.Context, query string, args ...interface{}) ([]models.BlogPost, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	posts := []models.BlogPost{}
+	for rows.Next() {
+		var p models.BlogPost
+		var categoryID sql.NullInt64
+
+		err := rows.Scan(
+			&p.ID, &p.Title, &p.Slug, &p.Excerpt, &p.FeaturedImage, &p.AuthorID, &categoryID,
+			&p.Status, &p.PublishedAt, &p.CreatedAt, &p.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		if categoryID.Valid {
+			cid := int(categoryID.Int64)
+			p.CategoryID = &cid
+		}
+		posts = append(posts, p)
+	}
+
+	return posts, rows.Err()
+}
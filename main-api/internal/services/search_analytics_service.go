@@ -0,0 +1,152 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"strings"
+	"time"
+
+	"bizgenie-api/internal/models"
+)
+
+var (
+	emailPattern = regexp.MustCompile(`[^\s@]+@[^\s@]+\.[^\s@]+`)
+	phonePattern = regexp.MustCompile(`\+?\d[\d\s.-]{7,}\d`)
+)
+
+type SearchAnalyticsService struct {
+	db *sql.DB
+}
+
+func NewSearchAnalyticsService(db *sql.DB) *SearchAnalyticsService {
+	return &SearchAnalyticsService{db: db}
+}
+
+// NormalizeQuery lowercases the query, collapses whitespace and masks
+// anything that looks like an email address or phone number
+func NormalizeQuery(query string) string {
+	q := strings.ToLower(strings.Join(strings.Fields(query), " "))
+	q = emailPattern.ReplaceAllString(q, "[email]")
+	q = phonePattern.ReplaceAllString(q, "[phone]")
+	if runes := []rune(q); len(runes) > 255 {
+		q = string(runes[:255])
+	}
+	return q
+}
+
+// LogQuery records a search query and returns its ID for click attribution
+func (s *SearchAnalyticsService) LogQuery(ctx context.Context, query, source string, resultCount int, latency time.Duration) (int64, error) {
+	sqlQuery := `
+		INSERT INTO search_queries (query, source, result_count, latency_ms)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id
+	`
+
+	var id int64
+	err := s.db.QueryRowContext(ctx, sqlQuery, NormalizeQuery(query), source, resultCount, latency.Milliseconds()).Scan(&id)
+	return id, err
+}
+
+// LogClick records a click-through on a search hit. Clicks on a search that was never
+// logged are refused rather than left to the foreign key, since anyone can send them.
+func (s *SearchAnalyticsService) LogClick(ctx context.Context, searchID int64, resultType string, resultID int, position *int) error {
+	query := `
+		INSERT INTO search_clicks (search_query_id, result_type, result_id, position)
+		SELECT id, $2, $3, $4 FROM search_queries WHERE id = $1
+	`
+	result, err := s.db.ExecContext(ctx, query, searchID, resultType, resultID, position)
+	if err != nil {
+		return dbError(err)
+	}
+	return affectedOne(result, "search_not_found")
+}
+
+// GetAnalytics returns top queries, zero-result queries and click-through rate for [from, to)
+func (s *SearchAnalyticsService) GetAnalytics(ctx context.Context, from, to time.Time, limit int) (*models.SearchAnalytics, error) {
+	analytics := &models.SearchAnalytics{
+		From:              from,
+		To:                to,
+		TopQueries:        []models.SearchQueryStat{},
+		ZeroResultQueries: []models.SearchQueryStat{},
+	}
+
+	var zeroResults int
+	totalsQuery := `
+		SELECT COUNT(*),
+		       COUNT(*) FILTER (WHERE result_count = 0),
+		       COUNT(*) FILTER (WHERE EXISTS (SELECT 1 FROM search_clicks sc WHERE sc.search_query_id = sq.id))
+		FROM search_queries sq
+		WHERE sq.created_at >= $1 AND sq.created_at < $2
+	`
+	err := s.db.QueryRowContext(ctx, totalsQuery, from, to).Scan(&analytics.TotalSearches, &zeroResults, &analytics.SearchesWithClick)
+	if err != nil {
+		return nil, err
+	}
+
+	if analytics.TotalSearches > 0 {
+		analytics.ClickThroughRate = float64(analytics.SearchesWithClick) / float64(analytics.TotalSearches)
+		analytics.ZeroResultRate = float64(zeroResults) / float64(analytics.TotalSearches)
+	}
+
+	analytics.TopQueries, err = s.queryStats(ctx, from, to, false, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	analytics.ZeroResultQueries, err = s.queryStats(ctx, from, to, true, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return analytics, nil
+}
+
+func (s *SearchAnalyticsService) queryStats(ctx context.Context, from, to time.Time, zeroResultsOnly bool, limit int) ([]models.SearchQueryStat, error) {
+	query := `
+		SELECT sq.query,
+		       COUNT(*) AS searches,
+		       AVG(sq.result_count)::float8,
+		       AVG(sq.latency_ms)::float8,
+		       COALESCE(SUM(clicks.count), 0) AS clicks,
+		       COUNT(*) FILTER (WHERE clicks.count > 0) AS searches_with_click
+		FROM search_queries sq
+		LEFT JOIN (
+			SELECT search_query_id, COUNT(*) AS count
+			FROM search_clicks
+			GROUP BY search_query_id
+		) clicks ON clicks.search_query_id = sq.id
+		WHERE sq.created_at >= $1 AND sq.created_at < $2
+		  AND ($3 = false OR sq.result_count = 0)
+		GROUP BY sq.query
+		ORDER BY searches DESC, sq.query ASC
+		LIMIT $4
+	`
+
+	rows, err := s.db.QueryContext(ctx, query, from, to, zeroResultsOnly, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	stats := []models.SearchQueryStat{}
+	for rows.Next() {
+		var stat models.SearchQueryStat
+		var searchesWithClick int
+
+		err := rows.Scan(
+			&stat.Query, &stat.Searches, &stat.AvgResults, &stat.AvgLatencyMs,
+			&stat.Clicks, &searchesWithClick,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		if stat.Searches > 0 {
+			stat.ClickThroughRate = float64(searchesWithClick) / float64(stat.Searches)
+		}
+		stats = append(stats, stat)
+	}
+
+	return stats, rows.Err()
+}
@@ -1,24 +1,52 @@
 package services
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
+	"strings"
+	"time"
 
+	"bizgenie-api/internal/metrics"
 	"bizgenie-api/internal/models"
 
+	"github.com/lib/pq"
 	"github.com/pgvector/pgvector-go"
 )
 
+// SearchTypes lists the entity types covered by the site-wide search
+var SearchTypes = []string{"product", "blog", "video", "category"}
+
 type SearchService struct {
-	db *sql.DB
+	db       *sql.DB
+	efSearch int
+	probes   int
 }
 
-func NewSearchService(db *sql.DB) *SearchService {
-	return &SearchService{db: db}
+// NewSearchService creates a search service; efSearch and probes tune the
+// HNSW/IVFFlat indexes per query and 0 keeps the server default
+func NewSearchService(db *sql.DB, efSearch, probes int) *SearchService {
+	return &SearchService{db: db, efSearch: efSearch, probes: probes}
+}
+
+// applyVectorSettings sets the ANN tuning parameters for the current transaction only
+func applyVectorSettings(ctx context.Context, tx *sql.Tx, efSearch, probes int) error {
+	if efSearch > 0 {
+		if _, err := tx.ExecContext(ctx, fmt.Sprintf("SET LOCAL hnsw.ef_search = %d", efSearch)); err != nil {
+			return err
+		}
+	}
+	if probes > 0 {
+		if _, err := tx.ExecContext(ctx, fmt.Sprintf("SET LOCAL ivfflat.probes = %d", probes)); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 // SearchProducts performs semantic search using pgvector
-func (s *SearchService) SearchProducts(queryEmbedding []float32, limit int, threshold float64) ([]models.Product, error) {
+func (s *SearchService) SearchProducts(ctx context.Context, queryEmbedding []float32, limit int, threshold float64) ([]models.Product, error) {
+	defer metrics.ObserveSearch("products_vector", time.Now())
 	if len(queryEmbedding) == 0 {
 		return nil, fmt.Errorf("query embedding is required")
 	}
@@ -39,7 +67,18 @@ func (s *SearchService) SearchProducts(queryEmbedding []float32, limit int, thre
 		LIMIT $3
 	`
 
-	rows, err := s.db.Query(query, vector, threshold, limit)
+	// SET LOCAL only lives as long as the transaction, so the query must run inside it
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if err := applyVectorSettings(ctx, tx, s.efSearch, s.probes); err != nil {
+		return nil, err
+	}
+
+	rows, err := tx.QueryContext(ctx, query, vector, threshold, limit)
 	if err != nil {
 		return nil, err
 	}
@@ -67,8 +106,9 @@ func (s *SearchService) SearchProducts(queryEmbedding []float32, limit int, thre
 }
 
 // SearchProductsByText performs full-text search (fallback when no embedding)
-func (s *SearchService) SearchProductsByText(query string, limit int) ([]models.Product, error) {
-	searchQuery := fmt.Sprintf("%%%s%%", query)
+func (s *SearchService) SearchProductsByText(ctx context.Context, query string, limit int) ([]models.Product, error) {
+	defer metrics.ObserveSearch("products_text", time.Now())
+	searchQuery := likePattern(query)
 
 	sqlQuery := `
 		SELECT p.id, p.name, p.slug, p.short_description, p.description, 
@@ -76,12 +116,12 @@ func (s *SearchService) SearchProductsByText(query string, limit int) ([]models.
 		       p.status, p.created_at, p.updated_at
 		FROM products p
 		WHERE p.status = 'published'
-		  AND (p.name ILIKE $1 OR p.description ILIKE $1 OR p.short_description ILIKE $1)
+		  AND (p.name ILIKE $1 ESCAPE '\' OR p.description ILIKE $1 ESCAPE '\' OR p.short_description ILIKE $1 ESCAPE '\')
 		ORDER BY p.created_at DESC
 		LIMIT $2
 	`
 
-	rows, err := s.db.Query(sqlQuery, searchQuery, limit)
+	rows, err := s.db.QueryContext(ctx, sqlQuery, searchQuery, limit)
 	if err != nil {
 		return nil, err
 	}
@@ -105,3 +145,201 @@ func (s *SearchService) SearchProductsByText(query string, limit int) ([]models.
 
 	return products, nil
 }
+
+// siteSearchHits is the CTE shared by the site-wide search queries.
+// $1 is the raw query text and $2 its likePattern; each branch scores its rows with
+// ts_rank over weighted fields plus a bonus when the title contains the query verbatim.
+// Product and blog categories may share a slug, so category_key prefixes it with the
+// kind of category for filtering and facets.
+const siteSearchHits = `
+	WITH q AS (
+		SELECT plainto_tsquery('simple', $1) AS tsq, $2::text AS pattern
+	),
+	hits AS (
+		SELECT 'product' AS type, p.id, p.name AS title,
+		       LEFT(COALESCE(NULLIF(p.short_description, ''), p.description, ''), 300) AS excerpt,
+		       '/products/' || p.slug AS url,
+		       c.slug AS category_slug, c.name AS category_name, 'product:' || c.slug AS category_key,
+		       ts_rank(
+		           setweight(to_tsvector('simple', p.name), 'A') ||
+		           setweight(to_tsvector('simple', COALESCE(p.short_description, '')), 'B') ||
+		           setweight(to_tsvector('simple', COALESCE(p.description, '')), 'C'),
+		           q.tsq
+		       ) + CASE WHEN p.name ILIKE q.pattern ESCAPE '\' THEN 1 ELSE 0 END AS score
+		FROM products p
+		CROSS JOIN q
+		LEFT JOIN categories c ON p.category_id = c.id
+		WHERE p.status = 'published'
+		  AND (to_tsvector('simple', p.name || ' ' || COALESCE(p.short_description, '') || ' ' || COALESCE(p.description, '')) @@ q.tsq
+		       OR p.name ILIKE q.pattern ESCAPE '\' OR p.short_description ILIKE q.pattern ESCAPE '\' OR p.description ILIKE q.pattern ESCAPE '\')
+
+		UNION ALL
+
+		SELECT 'blog', b.id, b.title,
+		       LEFT(COALESCE(NULLIF(b.excerpt, ''), regexp_replace(b.content, '<[^>]+>', ' ', 'g')), 300),
+		       '/blog/' || b.slug,
+		       bc.slug, bc.name, 'blog:' || bc.slug,
+		       ts_rank(
+		           setweight(to_tsvector('simple', b.title), 'A') ||
+		           setweight(to_tsvector('simple', COALESCE(b.excerpt, '')), 'B') ||
+		           setweight(to_tsvector('simple', regexp_replace(b.content, '<[^>]+>', ' ', 'g')), 'C'),
+		           q.tsq
+		       ) + CASE WHEN b.title ILIKE q.pattern ESCAPE '\' THEN 1 ELSE 0 END
+		FROM blog_posts b
+		CROSS JOIN q
+		LEFT JOIN blog_categories bc ON b.category_id = bc.id
+		WHERE b.status = 'published'
+		  AND (to_tsvector('simple', b.title || ' ' || COALESCE(b.excerpt, '') || ' ' || regexp_replace(b.content, '<[^>]+>', ' ', 'g')) @@ q.tsq
+		       OR b.title ILIKE q.pattern ESCAPE '\' OR b.excerpt ILIKE q.pattern ESCAPE '\' OR b.content ILIKE q.pattern ESCAPE '\')
+
+		UNION ALL
+
+		SELECT 'video', v.id, v.title,
+		       LEFT(COALESCE(v.description, ''), 300),
+		       '/video-demo?id=' || v.id,
+		       NULL, NULL, NULL,
+		       ts_rank(
+		           setweight(to_tsvector('simple', v.title), 'A') ||
+		           setweight(to_tsvector('simple', COALESCE(v.description, '')), 'B'),
+		           q.tsq
+		       ) + CASE WHEN v.title ILIKE q.pattern ESCAPE '\' THEN 1 ELSE 0 END
+		FROM video_demos v
+		CROSS JOIN q
+		WHERE v.status = 'published'
+		  AND (to_tsvector('simple', v.title || ' ' || COALESCE(v.description, '')) @@ q.tsq
+		       OR v.title ILIKE q.pattern ESCAPE '\' OR v.description ILIKE q.pattern ESCAPE '\')
+
+		UNION ALL
+
+		SELECT 'category', c.id, c.name,
+		       LEFT(COALESCE(c.description, ''), 300),
+		       '/products?category=' || c.slug,
+		       c.slug, c.name, 'product:' || c.slug,
+		       ts_rank(
+		           setweight(to_tsvector('simple', c.name), 'A') ||
+		           setweight(to_tsvector('simple', COALESCE(c.description, '')), 'B'),
+		           q.tsq
+		       ) + CASE WHEN c.name ILIKE q.pattern ESCAPE '\' THEN 1 ELSE 0 END
+		FROM categories c
+		CROSS JOIN q
+		WHERE to_tsvector('simple', c.name || ' ' || COALESCE(c.description, '')) @@ q.tsq
+		   OR c.name ILIKE q.pattern ESCAPE '\' OR c.description ILIKE q.pattern ESCAPE '\'
+	)
+`
+
+// SiteSearch performs a ranked search across products, blog posts, video demos and categories.
+// types and category are optional filters; category is a category facet value such as
+// "product:may-loc-nuoc" or "blog:tin-tuc". Facets for one dimension ignore that dimension's filter.
+func (s *SearchService) SiteSearch(ctx context.Context, query string, types []string, category string, limit, offset int) (*models.SearchResult, error) {
+	defer metrics.ObserveSearch("site", time.Now())
+	var typeFilter interface{}
+	if len(types) > 0 {
+		typeFilter = pq.Array(types)
+	}
+	pattern := likePattern(query)
+
+	result := &models.SearchResult{
+		Query:  query,
+		Limit:  limit,
+		Offset: offset,
+		Hits:   []models.SearchHit{},
+		Facets: map[string][]models.SearchFacet{
+			"type":     {},
+			"category": {},
+		},
+	}
+
+	hitsQuery := siteSearchHits + `
+		SELECT type, id, title, excerpt, url, score, category_slug, category_name,
+		       COUNT(*) OVER() AS total
+		FROM hits
+		WHERE ($3::text[] IS NULL OR type = ANY($3::text[]))
+		  AND ($4 = '' OR category_key = $4)
+		ORDER BY score DESC, title ASC
+		LIMIT $5 OFFSET $6
+	`
+
+	rows, err := s.db.QueryContext(ctx, hitsQuery, query, pattern, typeFilter, category, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var hit models.SearchHit
+		var categorySlug, categoryName sql.NullString
+
+		err := rows.Scan(
+			&hit.Type, &hit.ID, &hit.Title, &hit.Excerpt, &hit.URL, &hit.Score,
+			&categorySlug, &categoryName, &result.Total,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		if categorySlug.Valid {
+			hit.CategorySlug = &categorySlug.String
+		}
+		if categoryName.Valid {
+			hit.CategoryName = &categoryName.String
+		}
+
+		result.Hits = append(result.Hits, hit)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// A page past the end yields no rows, so the total has to be counted separately
+	if len(result.Hits) == 0 && offset > 0 {
+		countQuery := siteSearchHits + `
+			SELECT COUNT(*) FROM hits
+			WHERE ($3::text[] IS NULL OR type = ANY($3::text[]))
+			  AND ($4 = '' OR category_key = $4)
+		`
+		if err := s.db.QueryRowContext(ctx, countQuery, query, pattern, typeFilter, category).Scan(&result.Total); err != nil {
+			return nil, err
+		}
+	}
+
+	facetsQuery := siteSearchHits + `
+		SELECT 'type', type, type, COUNT(*)
+		FROM hits
+		WHERE ($4 = '' OR category_key = $4)
+		GROUP BY type
+
+		UNION ALL
+
+		SELECT 'category', category_key, MAX(category_name), COUNT(*)
+		FROM hits
+		WHERE category_key IS NOT NULL
+		  AND ($3::text[] IS NULL OR type = ANY($3::text[]))
+		GROUP BY category_key
+
+		ORDER BY 1, 4 DESC
+	`
+
+	facetRows, err := s.db.QueryContext(ctx, facetsQuery, query, pattern, typeFilter, category)
+	if err != nil {
+		return nil, err
+	}
+	defer facetRows.Close()
+
+	for facetRows.Next() {
+		var name string
+		var facet models.SearchFacet
+		if err := facetRows.Scan(&name, &facet.Value, &facet.Label, &facet.Count); err != nil {
+			return nil, err
+		}
+		result.Facets[name] = append(result.Facets[name], facet)
+	}
+
+	return result, facetRows.Err()
+}
+
+// likePattern returns an ILIKE ... ESCAPE '\' pattern matching text containing query,
+// with the wildcards % and _ in query taken literally
+func likePattern(query string) string {
+	escaped := strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(query)
+	return "%" + escaped + "%"
+}
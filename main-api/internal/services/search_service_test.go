@@ -0,0 +1,21 @@
+package services
+
+import "testing"
+
+func TestLikePattern(t *testing.T) {
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{"máy lọc", `%máy lọc%`},
+		{"%", `%\%%`},
+		{"_", `%\_%`},
+		{`50%_off\`, `%50\%\_off\\%`},
+		{"", `%%`},
+	}
+	for _, tt := range tests {
+		if got := likePattern(tt.query); got != tt.want {
+			t.Errorf("likePattern(%q) = %q, want %q", tt.query, got, tt.want)
+		}
+	}
+}
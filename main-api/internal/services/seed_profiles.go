@@ -0,0 +1,212 @@
+package services
+
+import (
+	"time"
+
+	"bizgenie-api/internal/models"
+)
+
+// SeedProfile is a set of fixtures loaded by SeedService. Items without a slug get the
+// one CreateX would generate from their name or title.
+type SeedProfile struct {
+	Users    []SeedUser
+	Content  models.ContentBundle
+	FAQs     []models.FAQ
+	Contacts []models.Contact
+}
+
+// SeedUser is a user with a known password, reset on every seed run
+type SeedUser struct {
+	Username string
+	Email    string
+	Password string
+	Role     string
+}
+
+func seedText(s string) *string {
+	return &s
+}
+
+// seedProfiles holds the fixtures by profile name. "demo" is sample content for local
+// development, "e2e" a small deterministic data set for end-to-end tests.
+var seedProfiles = map[string]*SeedProfile{
+	"demo": {
+		Users: []SeedUser{
+			{Username: "editor", Email: "editor@bizgenie.vn", Password: "Editor@12355", Role: "editor"},
+		},
+		Content: models.ContentBundle{
+			Categories: []models.BundleCategory{
+				{Slug: "giai-phap-saas", Name: "Giải pháp SaaS", Description: seedText("Các giải pháp phần mềm dạng dịch vụ"), Order: 1},
+				{Slug: "he-thong-quan-ly", Name: "Hệ thống quản lý", Description: seedText("Hệ thống quản lý doanh nghiệp"), Order: 2},
+				{Slug: "cong-cu-phan-tich", Name: "Công cụ phân tích", Description: seedText("Công cụ phân tích dữ liệu và báo cáo"), Order: 3},
+				{Slug: "tich-hop-api", Name: "Tích hợp API", Description: seedText("Giải pháp tích hợp và API"), Order: 4},
+			},
+			Products: []models.BundleProduct{
+				{
+					Slug:             "bizgenie-crm",
+					Name:             "BizGenie CRM",
+					ShortDescription: seedText("Hệ thống quản lý quan hệ khách hàng toàn diện"),
+					Description:      seedText("BizGenie CRM là giải pháp quản lý khách hàng hiện đại, giúp doanh nghiệp tối ưu hóa quy trình bán hàng và chăm sóc khách hàng. Hệ thống tích hợp đầy đủ các tính năng từ quản lý lead, pipeline, đến báo cáo và phân tích."),
+					CategorySlug:     "he-thong-quan-ly",
+					ImageURLs:        models.JSONB(`["/images/products/crm-1.jpg", "/images/products/crm-2.jpg"]`),
+					Features:         models.JSONB(`["Quản lý pipeline bán hàng", "Tự động hóa email marketing", "Báo cáo và phân tích real-time", "Tích hợp đa kênh", "Mobile app đầy đủ tính năng"]`),
+					Specifications:   models.JSONB(`{"platform": "Web, iOS, Android", "pricing": "Từ 500.000đ/tháng", "support": "24/7", "integration": "API, Webhook, Zapier"}`),
+					Status:           "published",
+				},
+				{
+					Slug:             "bizgenie-analytics",
+					Name:             "BizGenie Analytics",
+					ShortDescription: seedText("Công cụ phân tích dữ liệu doanh nghiệp mạnh mẽ"),
+					Description:      seedText("BizGenie Analytics cung cấp khả năng phân tích dữ liệu sâu sắc với AI/ML, giúp doanh nghiệp đưa ra quyết định dựa trên dữ liệu. Hỗ trợ nhiều nguồn dữ liệu và visualization đa dạng."),
+					CategorySlug:     "cong-cu-phan-tich",
+					ImageURLs:        models.JSONB(`["/images/products/analytics-1.jpg"]`),
+					Features:         models.JSONB(`["Dashboard tùy biến", "AI-powered insights", "Real-time data processing", "Export đa định dạng", "Collaborative reports"]`),
+					Specifications:   models.JSONB(`{"platform": "Web", "pricing": "Từ 1.000.000đ/tháng", "data_sources": "Unlimited", "retention": "2 năm"}`),
+					Status:           "published",
+				},
+				{
+					Slug:             "bizgenie-api-gateway",
+					Name:             "BizGenie API Gateway",
+					ShortDescription: seedText("Cổng kết nối API tập trung và bảo mật"),
+					Description:      seedText("Giải pháp quản lý API tập trung với khả năng bảo mật cao, rate limiting, monitoring và analytics. Hỗ trợ REST và GraphQL APIs."),
+					CategorySlug:     "tich-hop-api",
+					ImageURLs:        models.JSONB(`["/images/products/api-1.jpg"]`),
+					Features:         models.JSONB(`["API versioning", "Rate limiting", "Authentication & Authorization", "Request/Response transformation", "API analytics"]`),
+					Specifications:   models.JSONB(`{"platform": "Cloud, On-premise", "pricing": "Theo số lượng API calls", "support": "Enterprise SLA"}`),
+					Status:           "published",
+				},
+			},
+			// The blog categories created by migration 003, recreated after -reset
+			BlogCategories: []models.BundleBlogCategory{
+				{Slug: "product", Name: "Product", Description: seedText("Bài viết về sản phẩm"), Order: 1},
+				{Slug: "case-study", Name: "Case Study", Description: seedText("Nghiên cứu tình huống"), Order: 2},
+				{Slug: "how-to", Name: "How To", Description: seedText("Hướng dẫn"), Order: 3},
+				{Slug: "news", Name: "News", Description: seedText("Tin tức"), Order: 4},
+				{Slug: "tutorial", Name: "Tutorial", Description: seedText("Hướng dẫn chi tiết"), Order: 5},
+			},
+			BlogPosts: []models.BundleBlogPost{
+				{
+					Slug:           "xuat-huong-saas-2024",
+					Title:          "Xu hướng SaaS năm 2024: Tương lai của doanh nghiệp số",
+					Excerpt:        seedText("Khám phá các xu hướng công nghệ SaaS đang định hình tương lai của doanh nghiệp số hóa."),
+					Content:        "## Xu hướng SaaS năm 2024\n\nDoanh nghiệp chuyển dần sang các giải pháp SaaS để giảm chi phí hạ tầng và triển khai nhanh hơn.\n\n## AI trong sản phẩm SaaS\n\nCác tính năng AI như gợi ý, tóm tắt và trợ lý ảo trở thành tiêu chuẩn.",
+					FeaturedImage:  seedText("/images/blog/saas-trends-2024.jpg"),
+					AuthorUsername: "admin",
+					CategorySlug:   seedText("news"),
+					Status:         "published",
+				},
+				{
+					Slug:           "chon-giai-phap-crm",
+					Title:          "Cách chọn giải pháp CRM phù hợp cho doanh nghiệp vừa và nhỏ",
+					Excerpt:        seedText("Hướng dẫn chi tiết về cách đánh giá và lựa chọn hệ thống CRM phù hợp với quy mô và ngân sách."),
+					Content:        "## Xác định nhu cầu\n\nLiệt kê quy trình bán hàng hiện tại và những điểm cần cải thiện.\n\n## So sánh giải pháp\n\nĐánh giá chi phí, khả năng tích hợp và hỗ trợ của từng nhà cung cấp.",
+					FeaturedImage:  seedText("/images/blog/choose-crm.jpg"),
+					AuthorUsername: "admin",
+					CategorySlug:   seedText("how-to"),
+					Status:         "published",
+				},
+				{
+					Title:          "Ra mắt BizGenie API Gateway",
+					Excerpt:        seedText("Bản nháp thông báo ra mắt sản phẩm mới."),
+					Content:        "Nội dung đang được biên tập.",
+					AuthorUsername: "editor",
+					CategorySlug:   seedText("product"),
+					Status:         "draft",
+				},
+			},
+			VideoDemos: []models.BundleVideoDemo{
+				{
+					VideoURL:     "https://www.youtube.com/watch?v=dQw4w9WgXcQ",
+					Title:        "Giới thiệu BizGenie CRM",
+					Description:  seedText("Tổng quan các tính năng chính của BizGenie CRM"),
+					VideoType:    "youtube",
+					YouTubeID:    seedText("dQw4w9WgXcQ"),
+					ThumbnailURL: seedText("https://img.youtube.com/vi/dQw4w9WgXcQ/maxresdefault.jpg"),
+					Status:       "published",
+					Order:        1,
+				},
+			},
+			SocialMediaLinks: []models.BundleSocialMediaLink{
+				{Platform: "facebook", URL: "https://facebook.com/bizgenie", IconName: seedText("facebook"), Order: 1, IsActive: true},
+				{Platform: "youtube", URL: "https://youtube.com/@bizgenie", IconName: seedText("youtube"), Order: 2, IsActive: true},
+				{Platform: "linkedin", URL: "https://linkedin.com/company/bizgenie", IconName: seedText("linkedin"), Order: 3, IsActive: true},
+			},
+		},
+		FAQs: []models.FAQ{
+			{Question: "BizGenie CRM có bản dùng thử không?", Answer: "Có, BizGenie CRM có bản dùng thử miễn phí 14 ngày với đầy đủ tính năng.", Status: "published", Order: 1},
+			{Question: "Sản phẩm có hỗ trợ tích hợp API không?", Answer: "Tất cả sản phẩm đều có REST API và webhook; BizGenie API Gateway hỗ trợ thêm GraphQL.", Status: "published", Order: 2},
+		},
+		Contacts: []models.Contact{
+			{Name: "Nguyễn Văn A", Email: "nguyenvana@example.com", Phone: seedText("0901234567"), Company: seedText("Công ty ABC"), Message: "Tôi muốn tìm hiểu về giải pháp CRM của BizGenie.", Status: "new"},
+			{Name: "Trần Thị B", Email: "tranthib@example.com", Phone: seedText("0987654321"), Company: seedText("Công ty XYZ"), Message: "Cần tư vấn về gói Analytics.", Status: "read"},
+		},
+	},
+	"e2e": {
+		Users: []SeedUser{
+			{Username: "e2e-admin", Email: "e2e-admin@example.com", Password: "E2e-admin-password", Role: "admin"},
+			{Username: "e2e-editor", Email: "e2e-editor@example.com", Password: "E2e-editor-password", Role: "editor"},
+		},
+		Content: models.ContentBundle{
+			Categories: []models.BundleCategory{
+				{Slug: "e2e-parent", Name: "E2E Parent", Order: 1},
+				{Slug: "e2e-child", Name: "E2E Child", ParentSlug: seedText("e2e-parent"), Order: 2},
+			},
+			Products: []models.BundleProduct{
+				{
+					Slug:             "e2e-published-product",
+					Name:             "E2E Published Product",
+					ShortDescription: seedText("Published product used by end-to-end tests"),
+					CategorySlug:     "e2e-child",
+					Features:         models.JSONB(`["Feature A", "Feature B"]`),
+					Specifications:   models.JSONB(`{"platform": "Web", "pricing": "Free"}`),
+					Status:           "published",
+				},
+				{
+					Slug:         "e2e-draft-product",
+					Name:         "E2E Draft Product",
+					CategorySlug: "e2e-parent",
+					Status:       "draft",
+				},
+			},
+			BlogCategories: []models.BundleBlogCategory{
+				{Slug: "e2e-blog", Name: "E2E Blog", Order: 99},
+			},
+			BlogPosts: []models.BundleBlogPost{
+				{
+					Slug:           "e2e-published-post",
+					Title:          "E2E Published Post",
+					Excerpt:        seedText("Published post used by end-to-end tests"),
+					Content:        "## First section\n\nPublished post body.\n\n## Second section\n\nMore text.",
+					AuthorUsername: "e2e-editor",
+					CategorySlug:   seedText("e2e-blog"),
+					Status:         "published",
+					PublishedAt:    &models.FlexibleTime{Time: seedPublishedAt},
+				},
+				{
+					Slug:           "e2e-draft-post",
+					Title:          "E2E Draft Post",
+					Content:        "Draft post body.",
+					AuthorUsername: "e2e-editor",
+					Status:         "draft",
+					PublishedAt:    &models.FlexibleTime{Time: seedPublishedAt},
+				},
+			},
+			VideoDemos: []models.BundleVideoDemo{
+				{VideoURL: "https://example.com/e2e-demo.mp4", Title: "E2E Demo", VideoType: "url", Status: "published", Order: 1},
+			},
+			SocialMediaLinks: []models.BundleSocialMediaLink{
+				{Platform: "e2e", URL: "https://example.com/e2e", Order: 1, IsActive: true},
+			},
+		},
+		FAQs: []models.FAQ{
+			{Question: "E2E published question?", Answer: "E2E published answer.", Status: "published", Order: 1},
+			{Question: "E2E draft question?", Answer: "E2E draft answer.", Status: "draft", Order: 2},
+		},
+		Contacts: []models.Contact{
+			{Name: "E2E Contact", Email: "e2e-contact@example.com", Message: "E2E contact message.", Status: "new"},
+		},
+	},
+}
+
+// seedPublishedAt is the fixed publish date of e2e blog posts
+var seedPublishedAt = time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC)
@@ -0,0 +1,594 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"bizgenie-api/internal/logger"
+	"bizgenie-api/internal/models"
+)
+
+// ErrUnknownSeedProfile is returned for a profile name that is not in SeedProfileNames
+var ErrUnknownSeedProfile = errors.New("unknown seed profile")
+
+// seedTables are emptied by Reset, together with the data derived from them
+var seedTables = []string{
+	"users", "categories", "blog_categories", "products", "product_images", "blog_posts",
+	"contacts", "video_demos", "social_media_links", "faqs",
+	"content_chunks", "search_queries", "search_clicks", "assistant_conversations",
+}
+
+// SeedService loads fixture profiles through the regular services, so seeded rows get
+// slugs, defaults, embeddings and content chunks exactly like rows written by the admin API
+type SeedService struct {
+	db             *sql.DB
+	users          *UserService
+	auth           *AuthService
+	categories     *CategoryService
+	products       *ProductService
+	blogCategories *BlogCategoryService
+	blogs          *BlogService
+	videoDemos     *VideoDemoService
+	socialMedia    *SocialMediaService
+	faqs           *FAQService
+	contacts       *ContactService
+	chunks         *ChunkService
+	assistant      *AssistantService
+}
+
+func NewSeedService(db *sql.DB, auth *AuthService, chunks *ChunkService, assistant *AssistantService) *SeedService {
+	return &SeedService{
+		db:             db,
+		users:          NewUserService(db),
+		auth:           auth,
+		categories:     NewCategoryService(db),
+		products:       NewProductService(db),
+		blogCategories: NewBlogCategoryService(db),
+		blogs:          NewBlogService(db),
+		videoDemos:     NewVideoDemoService(db),
+		socialMedia:    NewSocialMediaService(db),
+		faqs:           NewFAQService(db),
+		contacts:       NewContactService(db),
+		chunks:         chunks,
+		assistant:      assistant,
+	}
+}
+
+// Reset empties the content tables, users and derived data, restarting their id sequences
+func (s *SeedService) Reset(ctx context.Context) error {
+	query := fmt.Sprintf(`TRUNCATE %s RESTART IDENTITY CASCADE`, strings.Join(seedTables, ", "))
+	_, err := s.db.ExecContext(ctx, query)
+	return err
+}
+
+// seedRun carries the state of one Seed call
+type seedRun struct {
+	ctx     context.Context
+	result  *models.BundleImportResult
+	faqIDs  []int
+	catIDs  map[string]int // category slug -> id
+	bcatIDs map[string]int // blog category slug -> id
+}
+
+// Seed upserts the fixtures of a profile by natural key: username, slug, FAQ question,
+// contact email, video URL and social media platform. Items that already match are left
+// alone, so running it twice changes nothing. Embeddings and content chunks of written
+// products, blog posts and FAQs are refreshed afterwards; failures there are only logged.
+func (s *SeedService) Seed(ctx context.Context, profileName string) (*models.BundleImportResult, error) {
+	profile, ok := seedProfiles[profileName]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s (available: %s)", ErrUnknownSeedProfile, profileName, strings.Join(SeedProfileNames(), ", "))
+	}
+
+	run := &seedRun{ctx: ctx, result: &models.BundleImportResult{}}
+	steps := []func(*seedRun, *SeedProfile) error{
+		s.seedUsers, s.seedCategories, s.seedProducts, s.seedBlogCategories, s.seedBlogPosts,
+		s.seedVideoDemos, s.seedSocialMediaLinks, s.seedFAQs, s.seedContacts,
+	}
+	for _, step := range steps {
+		if err := step(run, profile); err != nil {
+			return run.result, err
+		}
+	}
+
+	s.refreshDerived(ctx, run)
+	return run.result, nil
+}
+
+// SeedProfileNames returns the names of the available profiles
+func SeedProfileNames() []string {
+	names := make([]string, 0, len(seedProfiles))
+	for name := range seedProfiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// refreshDerived recomputes embeddings and content chunks for the items Seed wrote.
+// Nothing is computed while the assistant is disabled.
+func (s *SeedService) refreshDerived(ctx context.Context, run *seedRun) {
+	if !s.assistant.Enabled() {
+		return
+	}
+	for _, id := range run.result.ProductIDs {
+		if err := s.assistant.RefreshEmbedding(ctx, "product", id); err != nil {
+			logger.WarnContext(ctx, "Failed to refresh embedding for product %d: %v", id, err)
+		}
+		if _, err := s.chunks.Sync(ctx, "product", id, false); err != nil {
+			logger.WarnContext(ctx, "Failed to sync chunks for product %d: %v", id, err)
+		}
+	}
+	for _, id := range run.result.BlogPostIDs {
+		if _, err := s.chunks.Sync(ctx, "blog", id, false); err != nil {
+			logger.WarnContext(ctx, "Failed to sync chunks for blog %d: %v", id, err)
+		}
+	}
+	for _, id := range run.faqIDs {
+		if err := s.assistant.RefreshEmbedding(ctx, "faq", id); err != nil {
+			logger.WarnContext(ctx, "Failed to refresh embedding for faq %d: %v", id, err)
+		}
+	}
+}
+
+// seedUserState is what Seed compares for a user; Password reports whether the stored
+// hash matches the fixture password
+type seedUserState struct {
+	Email    string `json:"email"`
+	Role     string `json:"role"`
+	Password bool   `json:"password"`
+}
+
+func (s *SeedService) seedUsers(run *seedRun, profile *SeedProfile) error {
+	for _, fixture := range profile.Users {
+		incoming := &seedUserState{Email: fixture.Email, Role: fixture.Role, Password: true}
+		var existingState *seedUserState
+		existing, err := s.users.GetUserByUsername(run.ctx, fixture.Username)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return err
+		}
+		if existing != nil {
+			existingState = &seedUserState{
+				Email:    existing.Email,
+				Role:     existing.Role,
+				Password: s.auth.CheckPassword(fixture.Password, existing.PasswordHash),
+			}
+		}
+
+		write, err := recordChange(run.result, "users", fixture.Username, existingState, incoming)
+		if err != nil || !write {
+			return err
+		}
+
+		user := &models.User{Username: fixture.Username, Email: fixture.Email, Role: fixture.Role}
+		hash, err := s.auth.HashPassword(fixture.Password)
+		if err != nil {
+			return err
+		}
+		if existing == nil {
+			err = s.users.CreateUser(run.ctx, user, hash)
+		} else if err = s.users.UpdateUser(run.ctx, existing.ID, user); err == nil {
+			err = s.users.UpdatePassword(run.ctx, existing.ID, hash)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to seed user %s: %w", fixture.Username, err)
+		}
+	}
+	return nil
+}
+
+func (s *SeedService) seedCategories(run *seedRun, profile *SeedProfile) error {
+	all, err := s.categories.GetCategories(run.ctx, nil)
+	if err != nil {
+		return err
+	}
+	run.catIDs = map[string]int{}
+	slugs := map[int]string{}
+	for _, c := range all {
+		run.catIDs[c.Slug] = c.ID
+		slugs[c.ID] = c.Slug
+	}
+
+	// Parents come before their children in the fixtures
+	for _, fixture := range profile.Content.Categories {
+		incoming := fixture
+		if incoming.Slug == "" {
+			incoming.Slug = Slugify(incoming.Name)
+		}
+
+		var existing *models.BundleCategory
+		if id, ok := run.catIDs[incoming.Slug]; ok {
+			c, err := s.categories.GetCategoryByID(run.ctx, id)
+			if err != nil {
+				return err
+			}
+			existing = &models.BundleCategory{Slug: c.Slug, Name: c.Name, Description: c.Description, Order: c.Order}
+			if c.ParentID != nil {
+				parent := slugs[*c.ParentID]
+				existing.ParentSlug = &parent
+			}
+		}
+
+		write, err := recordChange(run.result, "categories", incoming.Slug, existing, &incoming)
+		if err != nil {
+			return err
+		}
+		if !write {
+			continue
+		}
+
+		category := &models.Category{Name: incoming.Name, Slug: incoming.Slug, Description: incoming.Description, Order: incoming.Order}
+		if incoming.ParentSlug != nil {
+			parentID, ok := run.catIDs[*incoming.ParentSlug]
+			if !ok {
+				return fmt.Errorf("category %s: unknown parent %s", incoming.Slug, *incoming.ParentSlug)
+			}
+			category.ParentID = &parentID
+		}
+		if existing == nil {
+			err = s.categories.CreateCategory(run.ctx, category)
+		} else {
+			category.ID = run.catIDs[incoming.Slug]
+			err = s.categories.UpdateCategory(run.ctx, category.ID, category)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to seed category %s: %w", incoming.Slug, err)
+		}
+		run.catIDs[category.Slug] = category.ID
+		slugs[category.ID] = category.Slug
+	}
+	return nil
+}
+
+func (s *SeedService) seedProducts(run *seedRun, profile *SeedProfile) error {
+	categorySlugs := map[int]string{}
+	for slug, id := range run.catIDs {
+		categorySlugs[id] = slug
+	}
+
+	for _, fixture := range profile.Content.Products {
+		incoming := fixture
+		if incoming.Slug == "" {
+			incoming.Slug = Slugify(incoming.Name)
+		}
+		categoryID, ok := run.catIDs[incoming.CategorySlug]
+		if !ok {
+			return fmt.Errorf("product %s: unknown category %s", incoming.Slug, incoming.CategorySlug)
+		}
+
+		var existing *models.BundleProduct
+		current, err := s.products.GetProductBySlug(run.ctx, incoming.Slug)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return err
+		}
+		if current != nil {
+			existing = &models.BundleProduct{
+				Slug: current.Slug, Name: current.Name, ShortDescription: current.ShortDescription,
+				Description: current.Description, CategorySlug: categorySlugs[current.CategoryID],
+				ImageURLs: current.ImageURLs, Features: current.Features, Specifications: current.Specifications,
+				Status: current.Status,
+			}
+		}
+
+		write, err := recordChange(run.result, "products", incoming.Slug, existing, &incoming)
+		if err != nil {
+			return err
+		}
+		if !write {
+			continue
+		}
+
+		product := &models.Product{
+			Name: incoming.Name, Slug: incoming.Slug, ShortDescription: incoming.ShortDescription,
+			Description: incoming.Description, CategoryID: categoryID, ImageURLs: incoming.ImageURLs,
+			Features: incoming.Features, Specifications: incoming.Specifications, Status: incoming.Status,
+		}
+		if current == nil {
+			err = s.products.CreateProduct(run.ctx, product)
+		} else {
+			product.ID = current.ID
+			err = s.products.UpdateProduct(run.ctx, current.ID, product)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to seed product %s: %w", incoming.Slug, err)
+		}
+		run.result.ProductIDs = append(run.result.ProductIDs, product.ID)
+	}
+	return nil
+}
+
+func (s *SeedService) seedBlogCategories(run *seedRun, profile *SeedProfile) error {
+	all, err := s.blogCategories.GetBlogCategories(run.ctx)
+	if err != nil {
+		return err
+	}
+	run.bcatIDs = map[string]int{}
+	for _, c := range all {
+		run.bcatIDs[c.Slug] = c.ID
+	}
+
+	for _, fixture := range profile.Content.BlogCategories {
+		incoming := fixture
+		if incoming.Slug == "" {
+			incoming.Slug = Slugify(incoming.Name)
+		}
+
+		var existing *models.BundleBlogCategory
+		id, found := run.bcatIDs[incoming.Slug]
+		if found {
+			c, err := s.blogCategories.GetBlogCategoryByID(run.ctx, id)
+			if err != nil {
+				return err
+			}
+			existing = &models.BundleBlogCategory{Slug: c.Slug, Name: c.Name, Description: c.Description, Order: c.Order}
+		}
+
+		write, err := recordChange(run.result, "blog_categories", incoming.Slug, existing, &incoming)
+		if err != nil {
+			return err
+		}
+		if !write {
+			continue
+		}
+
+		category := &models.BlogCategory{Name: incoming.Name, Slug: incoming.Slug, Description: incoming.Description, Order: incoming.Order}
+		if found {
+			err = s.blogCategories.UpdateBlogCategory(run.ctx, id, category)
+		} else if err = s.blogCategories.CreateBlogCategory(run.ctx, category); err == nil {
+			run.bcatIDs[category.Slug] = category.ID
+		}
+		if err != nil {
+			return fmt.Errorf("failed to seed blog category %s: %w", incoming.Slug, err)
+		}
+	}
+	return nil
+}
+
+func (s *SeedService) seedBlogPosts(run *seedRun, profile *SeedProfile) error {
+	categorySlugs := map[int]string{}
+	for slug, id := range run.bcatIDs {
+		categorySlugs[id] = slug
+	}
+
+	for _, fixture := range profile.Content.BlogPosts {
+		incoming := fixture
+		if incoming.Slug == "" {
+			incoming.Slug = Slugify(incoming.Title)
+		}
+		author, err := s.users.GetUserByUsername(run.ctx, incoming.AuthorUsername)
+		if err != nil {
+			return fmt.Errorf("blog post %s: author %s: %w", incoming.Slug, incoming.AuthorUsername, err)
+		}
+		var categoryID *int
+		if incoming.CategorySlug != nil {
+			id, ok := run.bcatIDs[*incoming.CategorySlug]
+			if !ok {
+				return fmt.Errorf("blog post %s: unknown blog category %s", incoming.Slug, *incoming.CategorySlug)
+			}
+			categoryID = &id
+		}
+
+		var existing *models.BundleBlogPost
+		current, err := s.blogs.GetBlogPostBySlug(run.ctx, incoming.Slug)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return err
+		}
+		if current != nil {
+			existing = &models.BundleBlogPost{
+				Slug: current.Slug, Title: current.Title, Excerpt: current.Excerpt, Content: current.Content,
+				FeaturedImage: current.FeaturedImage, AuthorUsername: current.Author.Username,
+				Status: current.Status, PublishedAt: current.PublishedAt,
+			}
+			if current.CategoryID != nil {
+				slug := categorySlugs[*current.CategoryID]
+				existing.CategorySlug = &slug
+			}
+			// Fixtures without a publish date keep the one set when the post was created
+			if incoming.PublishedAt == nil {
+				incoming.PublishedAt = current.PublishedAt
+			}
+		}
+
+		write, err := recordChange(run.result, "blog_posts", incoming.Slug, existing, &incoming)
+		if err != nil {
+			return err
+		}
+		if !write {
+			continue
+		}
+
+		post := &models.BlogPost{
+			Title: incoming.Title, Slug: incoming.Slug, Excerpt: incoming.Excerpt, Content: incoming.Content,
+			FeaturedImage: incoming.FeaturedImage, AuthorID: author.ID, CategoryID: categoryID,
+			Status: incoming.Status, PublishedAt: incoming.PublishedAt,
+		}
+		if current == nil {
+			err = s.blogs.CreateBlogPost(run.ctx, post)
+		} else {
+			post.ID = current.ID
+			err = s.blogs.UpdateBlogPost(run.ctx, current.ID, post)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to seed blog post %s: %w", incoming.Slug, err)
+		}
+		run.result.BlogPostIDs = append(run.result.BlogPostIDs, post.ID)
+	}
+	return nil
+}
+
+func (s *SeedService) seedVideoDemos(run *seedRun, profile *SeedProfile) error {
+	all, err := s.videoDemos.GetVideoDemos(run.ctx, "", 1000, 0)
+	if err != nil {
+		return err
+	}
+	byURL := map[string]models.VideoDemo{}
+	for _, d := range all {
+		byURL[d.VideoURL] = d
+	}
+
+	for _, fixture := range profile.Content.VideoDemos {
+		incoming := fixture
+		var existing *models.BundleVideoDemo
+		current, found := byURL[incoming.VideoURL]
+		if found {
+			existing = &models.BundleVideoDemo{
+				VideoURL: current.VideoURL, Title: current.Title, Description: current.Description,
+				VideoType: current.VideoType, YouTubeID: current.YouTubeID, ThumbnailURL: current.ThumbnailURL,
+				Status: current.Status, Order: current.Order,
+			}
+		}
+
+		write, err := recordChange(run.result, "video_demos", incoming.VideoURL, existing, &incoming)
+		if err != nil {
+			return err
+		}
+		if !write {
+			continue
+		}
+
+		demo := &models.VideoDemo{
+			Title: incoming.Title, Description: incoming.Description, VideoURL: incoming.VideoURL,
+			VideoType: incoming.VideoType, YouTubeID: incoming.YouTubeID, ThumbnailURL: incoming.ThumbnailURL,
+			Status: incoming.Status, Order: incoming.Order,
+		}
+		if found {
+			err = s.videoDemos.UpdateVideoDemo(run.ctx, current.ID, demo)
+		} else {
+			err = s.videoDemos.CreateVideoDemo(run.ctx, demo)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to seed video demo %s: %w", incoming.VideoURL, err)
+		}
+	}
+	return nil
+}
+
+func (s *SeedService) seedSocialMediaLinks(run *seedRun, profile *SeedProfile) error {
+	all, err := s.socialMedia.GetSocialMediaLinks(run.ctx, false)
+	if err != nil {
+		return err
+	}
+	byPlatform := map[string]models.SocialMediaLink{}
+	for _, l := range all {
+		byPlatform[l.Platform] = l
+	}
+
+	for _, fixture := range profile.Content.SocialMediaLinks {
+		incoming := fixture
+		var existing *models.BundleSocialMediaLink
+		current, found := byPlatform[incoming.Platform]
+		if found {
+			existing = &models.BundleSocialMediaLink{
+				Platform: current.Platform, URL: current.URL, IconName: current.IconName,
+				Order: current.Order, IsActive: current.IsActive,
+			}
+		}
+
+		write, err := recordChange(run.result, "social_media_links", incoming.Platform, existing, &incoming)
+		if err != nil {
+			return err
+		}
+		if !write {
+			continue
+		}
+
+		link := &models.SocialMediaLink{
+			Platform: incoming.Platform, URL: incoming.URL, IconName: incoming.IconName,
+			Order: incoming.Order, IsActive: incoming.IsActive,
+		}
+		if found {
+			err = s.socialMedia.UpdateSocialMediaLink(run.ctx, current.ID, link)
+		} else {
+			err = s.socialMedia.CreateSocialMediaLink(run.ctx, link)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to seed social media link %s: %w", incoming.Platform, err)
+		}
+	}
+	return nil
+}
+
+func (s *SeedService) seedFAQs(run *seedRun, profile *SeedProfile) error {
+	all, err := s.faqs.GetFAQs(run.ctx, "")
+	if err != nil {
+		return err
+	}
+	byQuestion := map[string]models.FAQ{}
+	for _, f := range all {
+		byQuestion[f.Question] = f
+	}
+
+	for _, fixture := range profile.FAQs {
+		incoming := fixture
+		var existing *models.FAQ
+		if current, found := byQuestion[incoming.Question]; found {
+			existing = &current
+			incoming.ID, incoming.CreatedAt, incoming.UpdatedAt = current.ID, current.CreatedAt, current.UpdatedAt
+		}
+
+		write, err := recordChange(run.result, "faqs", incoming.Question, existing, &incoming)
+		if err != nil {
+			return err
+		}
+		if !write {
+			continue
+		}
+
+		if existing != nil {
+			err = s.faqs.UpdateFAQ(run.ctx, existing.ID, &incoming)
+		} else {
+			err = s.faqs.CreateFAQ(run.ctx, &incoming)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to seed faq %q: %w", incoming.Question, err)
+		}
+		run.faqIDs = append(run.faqIDs, incoming.ID)
+	}
+	return nil
+}
+
+// seedContacts creates missing contacts; contacts are submitted by visitors, so for
+// existing ones only the status is brought back to the fixture's
+func (s *SeedService) seedContacts(run *seedRun, profile *SeedProfile) error {
+	all, err := s.contacts.GetContacts(run.ctx, "", 1000, 0)
+	if err != nil {
+		return err
+	}
+	byEmail := map[string]models.Contact{}
+	for _, c := range all {
+		byEmail[c.Email] = c
+	}
+
+	for _, fixture := range profile.Contacts {
+		incoming := fixture
+		var existing *models.Contact
+		if current, found := byEmail[incoming.Email]; found {
+			existing = &current
+			status := incoming.Status
+			incoming = current
+			incoming.Status = status
+		}
+
+		write, err := recordChange(run.result, "contacts", incoming.Email, existing, &incoming)
+		if err != nil {
+			return err
+		}
+		if !write {
+			continue
+		}
+
+		if existing != nil {
+			err = s.contacts.UpdateContactStatus(run.ctx, existing.ID, incoming.Status)
+		} else {
+			err = s.contacts.CreateContact(run.ctx, &incoming)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to seed contact %s: %w", incoming.Email, err)
+		}
+	}
+	return nil
+}
@@ -2,22 +2,31 @@ package services
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
 	"time"
 
 	"bizgenie-api/internal/logger"
+	"bizgenie-api/internal/metrics"
 	"bizgenie-api/internal/models"
+	"bizgenie-api/internal/requestid"
+	"bizgenie-api/internal/tracing"
 )
 
 type SlackService struct {
 	webhookURL string
+	httpClient *http.Client
 }
 
 func NewSlackService(webhookURL string) *SlackService {
 	return &SlackService{
 		webhookURL: webhookURL,
+		httpClient: &http.Client{
+			Timeout:   10 * time.Second,
+			Transport: tracing.Transport("slack", nil),
+		},
 	}
 }
 
@@ -44,15 +53,25 @@ type SlackField struct {
 	Text string `json:"text"`
 }
 
-func (s *SlackService) SendContactNotification(contact *models.Contact, productName string) error {
-	logger.Info("SlackService.SendContactNotification called: ContactID=%d, WebhookURL configured=%v", contact.ID, s.webhookURL != "")
+func (s *SlackService) SendContactNotification(ctx context.Context, contact *models.Contact, productName string) (err error) {
+	defer func() {
+		switch {
+		case s.webhookURL == "":
+			metrics.Notifications.Inc("slack", "skipped")
+		case err != nil:
+			metrics.Notifications.Inc("slack", "failure")
+		default:
+			metrics.Notifications.Inc("slack", "success")
+		}
+	}()
+	logger.InfoContext(ctx, "SlackService.SendContactNotification called: ContactID=%d, WebhookURL configured=%v", contact.ID, s.webhookURL != "")
 	
 	if s.webhookURL == "" {
-		logger.Warn("Slack webhook URL is not configured, skipping notification")
+		logger.WarnContext(ctx, "Slack webhook URL is not configured, skipping notification")
 		return nil
 	}
 
-	logger.Info("Preparing Slack message for contact ID=%d", contact.ID)
+	logger.InfoContext(ctx, "Preparing Slack message for contact ID=%d", contact.ID)
 
 	// Format message content
 	messageContent := contact.Message
@@ -133,33 +152,32 @@ func (s *SlackService) SendContactNotification(contact *models.Contact, productN
 
 	jsonData, err := json.Marshal(message)
 	if err != nil {
-		logger.ErrorWithErr("Failed to marshal Slack message", err)
+		logger.ErrorWithErrContext(ctx, "Failed to marshal Slack message", err)
 		return err
 	}
 
-	logger.Info("Sending POST request to Slack webhook: URL=%s, Body size=%d bytes", s.webhookURL, len(jsonData))
+	logger.InfoContext(ctx, "Sending POST request to Slack webhook: URL=%s, Body size=%d bytes", s.webhookURL, len(jsonData))
 
-	req, err := http.NewRequest("POST", s.webhookURL, bytes.NewBuffer(jsonData))
+	req, err := http.NewRequestWithContext(ctx, "POST", s.webhookURL, bytes.NewBuffer(jsonData))
 	if err != nil {
-		logger.ErrorWithErr("Failed to create Slack request", err)
+		logger.ErrorWithErrContext(ctx, "Failed to create Slack request", err)
 		return err
 	}
 
 	req.Header.Set("Content-Type", "application/json")
-
-	client := &http.Client{
-		Timeout: 10 * time.Second,
+	if requestID := requestid.FromContext(ctx); requestID != "" {
+		req.Header.Set(requestid.Header, requestID)
 	}
 
-	logger.Info("Executing HTTP request to Slack webhook")
-	resp, err := client.Do(req)
+	logger.InfoContext(ctx, "Executing HTTP request to Slack webhook")
+	resp, err := s.httpClient.Do(req)
 	if err != nil {
-		logger.ErrorWithErr("Failed to send Slack notification", err)
+		logger.ErrorWithErrContext(ctx, "Failed to send Slack notification", err)
 		return err
 	}
 	defer resp.Body.Close()
 
-	logger.Info("Slack webhook response: Status=%d, StatusCode=%s", resp.StatusCode, resp.Status)
+	logger.InfoContext(ctx, "Slack webhook response: Status=%d, StatusCode=%s", resp.StatusCode, resp.Status)
 
 	// Read response body for debugging
 	bodyBytes := make([]byte, 1024)
@@ -167,14 +185,14 @@ func (s *SlackService) SendContactNotification(contact *models.Contact, productN
 	bodyStr := ""
 	if n > 0 {
 		bodyStr = string(bodyBytes[:n])
-		logger.Info("Slack webhook response body: %s", bodyStr)
+		logger.InfoContext(ctx, "Slack webhook response body: %s", bodyStr)
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		logger.Error("Slack webhook returned non-200 status: %d, Response body: %s", resp.StatusCode, bodyStr)
+		logger.ErrorContext(ctx, "Slack webhook returned non-200 status: %d, Response body: %s", resp.StatusCode, bodyStr)
 		return fmt.Errorf("slack webhook returned status %d: %s", resp.StatusCode, bodyStr)
 	}
 
-	logger.Info("Slack notification sent successfully for contact ID: %d", contact.ID)
+	logger.InfoContext(ctx, "Slack notification sent successfully for contact ID: %d", contact.ID)
 	return nil
 }
@@ -0,0 +1,48 @@
+package services
+
+import (
+	"strings"
+	"unicode"
+)
+
+// vietnameseLetters maps accented Vietnamese letters to their base letter
+var vietnameseLetters = map[rune]rune{}
+
+func init() {
+	groups := map[rune]string{
+		'a': "àáạảãâầấậẩẫăằắặẳẵ",
+		'e': "èéẹẻẽêềếệểễ",
+		'i': "ìíịỉĩ",
+		'o': "òóọỏõôồốộổỗơờớợởỡ",
+		'u': "ùúụủũưừứựửữ",
+		'y': "ỳýỵỷỹ",
+		'd': "đ",
+	}
+	for base, letters := range groups {
+		for _, r := range letters {
+			vietnameseLetters[r] = base
+		}
+	}
+}
+
+// Slugify turns a name into a URL slug: lowercase ASCII letters and digits separated by
+// single hyphens, with Vietnamese diacritics removed ("Giải pháp SaaS" -> "giai-phap-saas")
+func Slugify(name string) string {
+	var b strings.Builder
+	hyphen := false
+	for _, r := range strings.ToLower(name) {
+		if base, ok := vietnameseLetters[r]; ok {
+			r = base
+		}
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			if hyphen && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			b.WriteRune(r)
+			hyphen = false
+			continue
+		}
+		hyphen = true
+	}
+	return b.String()
+}
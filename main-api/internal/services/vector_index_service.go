@@ -0,0 +1,305 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math"
+
+	"bizgenie-api/internal/apperror"
+	"bizgenie-api/internal/logger"
+	"bizgenie-api/internal/models"
+
+	"github.com/pgvector/pgvector-go"
+)
+
+// vectorIndexLockKey serializes index rebuilds across API replicas
+const vectorIndexLockKey = 727001
+
+// ErrVectorIndexRebuildInProgress is returned when another replica or request is already
+// rebuilding an index
+var ErrVectorIndexRebuildInProgress = apperror.Conflict("vector_index_rebuild_in_progress")
+
+// VectorIndexTables lists the tables whose embedding column has a managed ANN index
+var VectorIndexTables = []string{"products", "content_chunks"}
+
+// vectorIndexNames names the ANN index of each table in VectorIndexTables
+var vectorIndexNames = map[string]string{
+	"products":       "idx_products_embedding",
+	"content_chunks": "idx_content_chunks_embedding",
+}
+
+// vectorIndexName returns the index name of table, or an error for a table without one.
+// Table names are interpolated into queries, so only these are accepted.
+func vectorIndexName(table string) (string, error) {
+	name, ok := vectorIndexNames[table]
+	if !ok {
+		return "", fmt.Errorf("no vector index on table: %s", table)
+	}
+	return name, nil
+}
+
+type VectorIndexService struct {
+	db       *sql.DB
+	efSearch int
+	probes   int
+}
+
+func NewVectorIndexService(db *sql.DB, efSearch, probes int) *VectorIndexService {
+	return &VectorIndexService{db: db, efSearch: efSearch, probes: probes}
+}
+
+// IVFFlatLists returns the number of IVFFlat lists recommended by pgvector for rows:
+// rows/1000 up to 1M rows and sqrt(rows) above that
+func IVFFlatLists(rows int) int {
+	if rows > 1000000 {
+		return int(math.Sqrt(float64(rows)))
+	}
+	if lists := rows / 1000; lists > 1 {
+		return lists
+	}
+	return 1
+}
+
+// HNSWParams returns m and ef_construction for an HNSW index over rows
+func HNSWParams(rows int) (m, efConstruction int) {
+	if rows > 1000000 {
+		return 32, 128
+	}
+	return 16, 64
+}
+
+// GetStatus returns the embedding coverage and the ANN indexes on the embedding column of table
+func (s *VectorIndexService) GetStatus(ctx context.Context, table string) (*models.VectorIndexStatus, error) {
+	if _, err := vectorIndexName(table); err != nil {
+		return nil, err
+	}
+	status := &models.VectorIndexStatus{Table: table, Indexes: []models.VectorIndex{}}
+
+	countQuery := fmt.Sprintf(`SELECT COUNT(*) FILTER (WHERE embedding IS NOT NULL), COUNT(*) FROM %s`, table)
+	if err := s.db.QueryRowContext(ctx, countQuery).Scan(&status.EmbeddedRows, &status.TotalRows); err != nil {
+		return nil, err
+	}
+
+	query := `
+		SELECT i.relname, am.amname, pg_get_indexdef(i.oid), pg_relation_size(i.oid), ix.indisvalid
+		FROM pg_index ix
+		JOIN pg_class i ON i.oid = ix.indexrelid
+		JOIN pg_class t ON t.oid = ix.indrelid
+		JOIN pg_am am ON am.oid = i.relam
+		JOIN pg_attribute a ON a.attrelid = t.oid AND a.attnum = ANY(ix.indkey)
+		WHERE t.relname = $1 AND a.attname = 'embedding'
+		ORDER BY i.relname
+	`
+
+	rows, err := s.db.QueryContext(ctx, query, table)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var idx models.VectorIndex
+		if err := rows.Scan(&idx.Name, &idx.Method, &idx.Definition, &idx.SizeBytes, &idx.Valid); err != nil {
+			return nil, err
+		}
+		status.Indexes = append(status.Indexes, idx)
+	}
+
+	return status, rows.Err()
+}
+
+// RebuildIndex builds a new HNSW or IVFFlat index on table sized to the current row count
+// and swaps it in place of the existing one. The build runs CONCURRENTLY so reads and
+// writes on the table are not blocked.
+func (s *VectorIndexService) RebuildIndex(ctx context.Context, table, method string) (*models.VectorIndexStatus, error) {
+	indexName, err := vectorIndexName(table)
+	if err != nil {
+		return nil, err
+	}
+	tempName := indexName + "_new"
+
+	// A build that outlives the request still finishes, and stays part of its trace
+	ctx = context.WithoutCancel(ctx)
+
+	// Advisory locks are per session, so keep a single connection for the whole rebuild
+	conn, err := s.db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	var locked bool
+	if err := conn.QueryRowContext(ctx, `SELECT pg_try_advisory_lock($1)`, vectorIndexLockKey).Scan(&locked); err != nil {
+		return nil, err
+	}
+	if !locked {
+		return nil, ErrVectorIndexRebuildInProgress
+	}
+	defer conn.ExecContext(ctx, `SELECT pg_advisory_unlock($1)`, vectorIndexLockKey)
+
+	var rows int
+	if err := conn.QueryRowContext(ctx, fmt.Sprintf(`SELECT COUNT(*) FROM %s WHERE embedding IS NOT NULL`, table)).Scan(&rows); err != nil {
+		return nil, err
+	}
+
+	var createQuery string
+	switch method {
+	case "hnsw":
+		m, efConstruction := HNSWParams(rows)
+		createQuery = fmt.Sprintf(
+			"CREATE INDEX CONCURRENTLY %s ON %s USING hnsw (embedding vector_cosine_ops) WITH (m = %d, ef_construction = %d)",
+			tempName, table, m, efConstruction,
+		)
+	case "ivfflat":
+		// IVFFlat clusters existing rows, so building it on an empty table gives useless lists
+		if rows == 0 {
+			return nil, fmt.Errorf("ivfflat index requires rows with embeddings in %s", table)
+		}
+		createQuery = fmt.Sprintf(
+			"CREATE INDEX CONCURRENTLY %s ON %s USING ivfflat (embedding vector_cosine_ops) WITH (lists = %d)",
+			tempName, table, IVFFlatLists(rows),
+		)
+	default:
+		return nil, fmt.Errorf("unsupported vector index method: %s", method)
+	}
+
+	// Remove a leftover from an interrupted build, which CONCURRENTLY leaves behind as invalid
+	if _, err := conn.ExecContext(ctx, "DROP INDEX CONCURRENTLY IF EXISTS "+tempName); err != nil {
+		return nil, err
+	}
+
+	logger.InfoContext(ctx, "Building %s vector index on %s over %d rows", method, table, rows)
+	if _, err := conn.ExecContext(ctx, createQuery); err != nil {
+		logger.ErrorWithErrContext(ctx, "Failed to build vector index", err)
+		return nil, err
+	}
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, "DROP INDEX IF EXISTS "+indexName); err != nil {
+		return nil, err
+	}
+	if _, err := tx.ExecContext(ctx, fmt.Sprintf("ALTER INDEX %s RENAME TO %s", tempName, indexName)); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	logger.InfoContext(ctx, "Vector index %s rebuilt using %s", indexName, method)
+
+	return s.GetStatus(ctx, table)
+}
+
+// MeasureRecall compares the top-k results of the ANN index on table with an exact scan
+// for sampleSize random rows and returns the average overlap
+func (s *VectorIndexService) MeasureRecall(ctx context.Context, table string, sampleSize, k int) (*models.VectorIndexRecall, error) {
+	if _, err := vectorIndexName(table); err != nil {
+		return nil, err
+	}
+	recall := &models.VectorIndexRecall{Table: table, K: k, EfSearch: s.efSearch, Probes: s.probes}
+
+	query := fmt.Sprintf(`SELECT embedding FROM %s WHERE embedding IS NOT NULL ORDER BY random() LIMIT $1`, table)
+	rows, err := s.db.QueryContext(ctx, query, sampleSize)
+	if err != nil {
+		return nil, err
+	}
+
+	var samples []pgvector.Vector
+	for rows.Next() {
+		var v pgvector.Vector
+		if err := rows.Scan(&v); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		samples = append(samples, v)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(samples) == 0 {
+		return recall, nil
+	}
+
+	var total float64
+	for _, sample := range samples {
+		approx, err := s.nearestIDs(ctx, table, sample, k, false)
+		if err != nil {
+			return nil, err
+		}
+		exact, err := s.nearestIDs(ctx, table, sample, k, true)
+		if err != nil {
+			return nil, err
+		}
+		if len(exact) == 0 {
+			continue
+		}
+
+		found := 0
+		for id := range exact {
+			if approx[id] {
+				found++
+			}
+		}
+		total += float64(found) / float64(len(exact))
+	}
+
+	recall.SampleSize = len(samples)
+	recall.Recall = total / float64(len(samples))
+
+	return recall, nil
+}
+
+// nearestIDs returns the IDs of the k nearest rows of table, forcing either an index scan
+// or an exact scan
+func (s *VectorIndexService) nearestIDs(ctx context.Context, table string, v pgvector.Vector, k int, exact bool) (map[int64]bool, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if exact {
+		if _, err := tx.ExecContext(ctx, "SET LOCAL enable_indexscan = off"); err != nil {
+			return nil, err
+		}
+	} else {
+		if _, err := tx.ExecContext(ctx, "SET LOCAL enable_seqscan = off"); err != nil {
+			return nil, err
+		}
+		if err := applyVectorSettings(ctx, tx, s.efSearch, s.probes); err != nil {
+			return nil, err
+		}
+	}
+
+	query := fmt.Sprintf(`
+		SELECT id FROM %s
+		WHERE embedding IS NOT NULL
+		ORDER BY embedding <=> $1::vector
+		LIMIT $2
+	`, table)
+
+	rows, err := tx.QueryContext(ctx, query, v, k)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ids := make(map[int64]bool, k)
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids[id] = true
+	}
+
+	return ids, rows.Err()
+}
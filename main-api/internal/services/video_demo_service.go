@@ -1,12 +1,15 @@
 package services
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"regexp"
 	"strings"
+	"time"
 
 	"bizgenie-api/internal/logger"
+	"bizgenie-api/internal/metrics"
 	"bizgenie-api/internal/models"
 )
 
@@ -39,7 +42,8 @@ func ExtractYouTubeID(url string) (string, error) {
 }
 
 // GetVideoDemos returns all video demos with optional status filter
-func (s *VideoDemoService) GetVideoDemos(status string, limit, offset int) ([]models.VideoDemo, error) {
+func (s *VideoDemoService) GetVideoDemos(ctx context.Context, status string, limit, offset int) ([]models.VideoDemo, error) {
+	defer metrics.ObserveQuery("video_demos.list", time.Now())
 	query := `
 		SELECT id, title, description, video_url, video_type, youtube_id, 
 		       thumbnail_url, status, "order", created_at, updated_at
@@ -58,9 +62,9 @@ func (s *VideoDemoService) GetVideoDemos(status string, limit, offset int) ([]mo
 	query += fmt.Sprintf(" ORDER BY \"order\" ASC, created_at DESC LIMIT $%d OFFSET $%d", argPos, argPos+1)
 	args = append(args, limit, offset)
 
-	rows, err := s.db.Query(query, args...)
+	rows, err := s.db.QueryContext(ctx, query, args...)
 	if err != nil {
-		logger.ErrorWithErr("Failed to query video demos", err)
+		logger.ErrorWithErrContext(ctx, "Failed to query video demos", err)
 		return nil, err
 	}
 	defer rows.Close()
@@ -97,7 +101,8 @@ func (s *VideoDemoService) GetVideoDemos(status string, limit, offset int) ([]mo
 }
 
 // GetVideoDemoByID returns a video demo by ID
-func (s *VideoDemoService) GetVideoDemoByID(id int) (*models.VideoDemo, error) {
+func (s *VideoDemoService) GetVideoDemoByID(ctx context.Context, id int) (*models.VideoDemo, error) {
+	defer metrics.ObserveQuery("video_demos.get_by_id", time.Now())
 	query := `
 		SELECT id, title, description, video_url, video_type, youtube_id, 
 		       thumbnail_url, status, "order", created_at, updated_at
@@ -110,15 +115,15 @@ func (s *VideoDemoService) GetVideoDemoByID(id int) (*models.VideoDemo, error) {
 	var youtubeID sql.NullString
 	var thumbnailURL sql.NullString
 
-	err := s.db.QueryRow(query, id).Scan(
+	err := s.db.QueryRowContext(ctx, query, id).Scan(
 		&d.ID, &d.Title, &description, &d.VideoURL, &d.VideoType, &youtubeID,
 		&thumbnailURL, &d.Status, &d.Order, &d.CreatedAt, &d.UpdatedAt,
 	)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("video demo not found")
+			return nil, notFound(err, "video_demo_not_found")
 		}
-		logger.ErrorWithErr("Failed to get video demo by ID", err)
+		logger.ErrorWithErrContext(ctx, "Failed to get video demo by ID", err)
 		return nil, err
 	}
 
@@ -136,7 +141,7 @@ func (s *VideoDemoService) GetVideoDemoByID(id int) (*models.VideoDemo, error) {
 }
 
 // CreateVideoDemo creates a new video demo
-func (s *VideoDemoService) CreateVideoDemo(demo *models.VideoDemo) error {
+func (s *VideoDemoService) CreateVideoDemo(ctx context.Context, demo *models.VideoDemo) error {
 	// Auto-detect video type if not specified
 	if demo.VideoType == "" {
 		if strings.Contains(demo.VideoURL, "youtube.com") || strings.Contains(demo.VideoURL, "youtu.be") {
@@ -167,7 +172,7 @@ func (s *VideoDemoService) CreateVideoDemo(demo *models.VideoDemo) error {
 		RETURNING id, created_at, updated_at
 	`
 
-	err := s.db.QueryRow(
+	err := s.db.QueryRowContext(ctx,
 		query,
 		demo.Title,
 		demo.Description,
@@ -180,15 +185,15 @@ func (s *VideoDemoService) CreateVideoDemo(demo *models.VideoDemo) error {
 	).Scan(&demo.ID, &demo.CreatedAt, &demo.UpdatedAt)
 
 	if err != nil {
-		logger.ErrorWithErr("Failed to create video demo", err)
-		return err
+		logger.ErrorWithErrContext(ctx, "Failed to create video demo", err)
+		return dbError(err)
 	}
 
 	return nil
 }
 
 // UpdateVideoDemo updates an existing video demo
-func (s *VideoDemoService) UpdateVideoDemo(id int, demo *models.VideoDemo) error {
+func (s *VideoDemoService) UpdateVideoDemo(ctx context.Context, id int, demo *models.VideoDemo) error {
 	// Auto-detect video type if not specified
 	if demo.VideoType == "" {
 		if strings.Contains(demo.VideoURL, "youtube.com") || strings.Contains(demo.VideoURL, "youtu.be") {
@@ -221,7 +226,7 @@ func (s *VideoDemoService) UpdateVideoDemo(id int, demo *models.VideoDemo) error
 		RETURNING updated_at
 	`
 
-	err := s.db.QueryRow(
+	err := s.db.QueryRowContext(ctx,
 		query,
 		demo.Title,
 		demo.Description,
@@ -236,32 +241,23 @@ func (s *VideoDemoService) UpdateVideoDemo(id int, demo *models.VideoDemo) error
 
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return fmt.Errorf("video demo not found")
+			return notFound(err, "video_demo_not_found")
 		}
-		logger.ErrorWithErr("Failed to update video demo", err)
-		return err
+		logger.ErrorWithErrContext(ctx, "Failed to update video demo", err)
+		return dbError(err)
 	}
 
 	return nil
 }
 
 // DeleteVideoDemo deletes a video demo
-func (s *VideoDemoService) DeleteVideoDemo(id int) error {
+func (s *VideoDemoService) DeleteVideoDemo(ctx context.Context, id int) error {
 	query := `DELETE FROM video_demos WHERE id = $1`
-	result, err := s.db.Exec(query, id)
+	result, err := s.db.ExecContext(ctx, query, id)
 	if err != nil {
-		logger.ErrorWithErr("Failed to delete video demo", err)
-		return err
+		logger.ErrorWithErrContext(ctx, "Failed to delete video demo", err)
+		return dbError(err)
 	}
 
-	rowsAffected, err := result.RowsAffected()
-	if err != nil {
-		return err
-	}
-
-	if rowsAffected == 0 {
-		return fmt.Errorf("video demo not found")
-	}
-
-	return nil
+	return affectedOne(result, "video_demo_not_found")
 }
@@ -0,0 +1,67 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestTransportInjectsTraceparent(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := Install(sdktrace.WithSyncer(exporter))
+	t.Cleanup(func() { provider.Shutdown(context.Background()) })
+
+	var traceparent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get("traceparent")
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	ctx, parent := Tracer().Start(context.Background(), "parent")
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, server.URL+"/services/T000/B000/secret", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := &http.Client{Transport: Transport("slack", nil)}
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	parent.End()
+
+	if req.Header.Get("traceparent") != "" {
+		t.Error("the caller's request was modified")
+	}
+
+	spans := exporter.GetSpans()
+	if len(spans) != 2 {
+		t.Fatalf("got %d spans, want the client span and its parent", len(spans))
+	}
+	span := spans[0]
+	if span.Name != "POST slack" || span.SpanKind != trace.SpanKindClient {
+		t.Errorf("span = %q (kind %v), want a client span named POST slack", span.Name, span.SpanKind)
+	}
+	if span.Parent.SpanID() != parent.SpanContext().SpanID() {
+		t.Errorf("client span parent = %s, want %s", span.Parent.SpanID(), parent.SpanContext().SpanID())
+	}
+	want := "00-" + span.SpanContext.TraceID().String() + "-" + span.SpanContext.SpanID().String() + "-01"
+	if traceparent != want {
+		t.Errorf("traceparent = %q, want %q", traceparent, want)
+	}
+	if span.Status.Code.String() != "Error" {
+		t.Errorf("status = %v, want an error for a 502 response", span.Status)
+	}
+	for _, attr := range span.Attributes {
+		if strings.Contains(attr.Value.Emit(), "secret") {
+			t.Errorf("attribute %s records the URL path", attr.Key)
+		}
+	}
+}
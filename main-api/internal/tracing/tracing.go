@@ -0,0 +1,81 @@
+// Package tracing sets up OpenTelemetry: the tracer provider exporting over OTLP, W3C trace
+// context propagation, and client spans for outbound HTTP calls.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instrumentationName identifies the spans this module creates
+const instrumentationName = "bizgenie-api"
+
+// propagator reads and writes the traceparent and tracestate headers, plus baggage
+var propagator = propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{})
+
+// Options configures Setup
+type Options struct {
+	ServiceName string
+	Environment string
+	// OTLP/HTTP collector base URL, e.g. http://otel-collector:4318; empty disables export
+	Endpoint string
+	// Fraction of new traces to record; traces started upstream keep the caller's decision
+	SampleRatio float64
+}
+
+// Setup installs the W3C propagator and, when an endpoint is configured, a tracer provider
+// exporting in batches over OTLP/HTTP. The returned function flushes pending spans and must
+// be called on shutdown. Without an endpoint spans are not recorded, but an incoming
+// traceparent is still passed on to outbound calls.
+func Setup(ctx context.Context, opts Options) (func(context.Context) error, error) {
+	if opts.Endpoint == "" {
+		otel.SetTextMapPropagator(propagator)
+		return func(context.Context) error { return nil }, nil
+	}
+
+	exporter, err := otlptracehttp.New(ctx, otlptracehttp.WithEndpointURL(strings.TrimRight(opts.Endpoint, "/")+"/v1/traces"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OTLP exporter: %w", err)
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(
+		semconv.SchemaURL,
+		semconv.ServiceName(opts.ServiceName),
+		semconv.DeploymentEnvironment(opts.Environment),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build trace resource: %w", err)
+	}
+
+	provider := Install(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(opts.SampleRatio))),
+	)
+	return provider.Shutdown, nil
+}
+
+// Install makes a tracer provider built from opts the global one, along with the W3C
+// propagator, and returns it. Tests pass sdktrace.WithSyncer(tracetest.NewInMemoryExporter())
+// to inspect the spans a call produces.
+func Install(opts ...sdktrace.TracerProviderOption) *sdktrace.TracerProvider {
+	provider := sdktrace.NewTracerProvider(opts...)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagator)
+	return provider
+}
+
+// Tracer returns the tracer for this module from the global provider. It is looked up on
+// every call so spans follow the provider installed last.
+func Tracer() trace.Tracer {
+	return otel.Tracer(instrumentationName)
+}